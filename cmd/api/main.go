@@ -1,18 +1,41 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/lucas-de-lima/go-auth-system/internal/activity"
+	"github.com/lucas-de-lima/go-auth-system/internal/audit"
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/blacklist"
+	"github.com/lucas-de-lima/go-auth-system/internal/config"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/discovery"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/introspect"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/nonce"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/status"
 	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/events"
+	"github.com/lucas-de-lima/go-auth-system/internal/lockout"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	noncestore "github.com/lucas-de-lima/go-auth-system/internal/nonce"
 	"github.com/lucas-de-lima/go-auth-system/internal/repository"
 	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/scheduler"
+	"github.com/lucas-de-lima/go-auth-system/internal/server"
 	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/lucas-de-lima/go-auth-system/internal/session"
 	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/hashing"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/lucas-de-lima/go-auth-system/pkg/validator"
 	"github.com/lucas-de-lima/go-auth-system/prisma"
 	// outros imports necessários
 )
@@ -23,16 +46,71 @@ func main() {
 		log.Printf("Aviso: Não foi possível carregar o arquivo configs/app.env: %v", err)
 	}
 
+	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Configuração inválida: %v", err)
+	}
+	// Formato dos logs (texto ou JSON) definido antes da primeira mensagem
+	logConfig := logging.DefaultConfig()
+	logConfig.Format = cfg.Log.Format
+	logging.SetupLogger(logConfig)
+	errors.SetCamelCaseKeys(cfg.Response.CamelCaseKeys)
+	errors.SetProblemJSON(cfg.Response.ProblemJSON)
+	validator.Init()
+	validator.SetStrictEmail(cfg.Register.StrictEmail)
+	validator.SetMinPasswordLength(cfg.Register.PasswordMinLength)
+	if cfg.Debug.LogDebug {
+		logging.SetDebugOutput(os.Stdout)
+	}
+
 	// Inicializar o router do Gin
 	// Substituindo gin.Default() por uma configuração personalizada
 	router := gin.New()
 
-	// Adicionando middleware de log do Gin
-	router.Use(gin.Logger())
+	// O IP do cliente (limite por IP, logs) só vem de X-Forwarded-For/X-Real-IP
+	// quando a conexão chega de um proxy confiável; por padrão nenhum é confiável
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES inválido: %v", err)
+	}
+
+	// Barras finais não são redirecionadas; rotas inexistentes respondem 404 em JSON
+	routes.ConfigureRouter(router)
+
+	// ID por requisição (X-Request-ID recebido ou UUID), ecoado na resposta
+	router.Use(middleware.RequestID())
+
+	// Campos de contexto (request id, IP, usuário, rota) para logging.With
+	router.Use(middleware.LogContext())
+
+	// Uma linha de access log por requisição, com o status final
+	router.Use(middleware.AccessLog())
 
 	// Adicionando nosso middleware de recuperação personalizado
 	router.Use(errors.GinMiddlewareRecovery())
 
+	// Limitando requisições simultâneas para proteger contra sobrecarga
+	router.Use(middleware.MaxInFlight(cfg.Server.MaxInFlight))
+
+	// Cabeçalhos contra clickjacking, MIME sniffing e vazamento de referer
+	router.Use(middleware.SecureHeaders(
+		middleware.WithFrameOptions(cfg.Headers.FrameOptions),
+		middleware.WithReferrerPolicy(cfg.Headers.ReferrerPolicy),
+		middleware.WithHSTS(cfg.Headers.HSTSMaxAge, cfg.Headers.HSTSIncludeSubdomains),
+	))
+
+	// CORS antes da manutenção e das rotas, para que preflights sempre recebam resposta
+	router.Use(middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		MaxAge:           cfg.CORS.MaxAge,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+	}))
+
+	// Modo de manutenção: MAINTENANCE_MODE define o estado inicial e SIGUSR1 o alterna
+	var maintenance atomic.Bool
+	maintenance.Store(cfg.App.Maintenance)
+	watchMaintenanceSignal(&maintenance)
+	router.Use(middleware.MaintenanceMode(maintenance.Load))
+
 	// Inicializar a conexão com o banco de dados
 	prisma.Init()
 	defer prisma.Disconnect()
@@ -53,10 +131,14 @@ func main() {
 	adminExists, _ := userRepository.GetByEmail(defaultAdminEmail)
 	if adminExists == nil {
 		adminUser := &domain.User{
-			Email:    defaultAdminEmail,
-			Password: defaultAdminPassword,
-			Name:     "Administrador",
-			Roles:    []string{"admin"},
+			Email:     defaultAdminEmail,
+			Password:  defaultAdminPassword,
+			Name:      "Administrador",
+			Roles:     []string{domain.RoleAdmin},
+			CreatedBy: domain.ActorSystem,
+			UpdatedBy: domain.ActorSystem,
+			// Criado pela aplicação, não depende da verificação de email para logar
+			EmailVerified: true,
 		}
 		err := userRepository.Create(adminUser)
 		if err != nil {
@@ -66,37 +148,190 @@ func main() {
 		}
 	}
 
-	// Obter configurações do JWT do arquivo de ambiente
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "your_jwt_secret" // Valor padrão do seu app.env
+	// Permissões efetivas, derivadas dos papéis, na claim permissions dos access tokens
+	rolePermissions := domain.DefaultRolePermissions()
+	if cfg.Authz.RolePermissions != nil {
+		rolePermissions = domain.RolePermissions(cfg.Authz.RolePermissions)
 	}
 
-	refreshKey := os.Getenv("JWT_REFRESH_SECRET")
-	if refreshKey == "" {
-		refreshKey = "your_refresh_secret" // Valor padrão do seu app.env
+	jwtOpts := []auth.JWTOption{
+		auth.WithTokenTypeEnforcement(cfg.JWT.EnforceTokenType),
+		auth.WithIssuer(cfg.JWT.IssuerURL),
+		auth.WithAudience(cfg.JWT.Audience),
+		auth.WithMaxAccessTokenTTL(cfg.JWT.MaxAccessTTL),
+		auth.WithPasswordReset(cfg.Reset.TokenSecret, cfg.Reset.TokenTTL),
+		auth.WithAccountDeletionTTL(cfg.Account.DeletionTokenTTL),
+		auth.WithEmailVerificationTTL(cfg.Register.VerificationTokenTTL),
+		auth.WithRolePermissions(rolePermissions),
+		// Chaves de refresh anteriores seguem validando os tokens já emitidos após a rotação
+		auth.WithPreviousRefreshKeys(cfg.JWT.PreviousRefreshSecrets...),
+	}
+	var jwtService *auth.JWTService
+	if cfg.JWT.PrivateKeyFile != "" {
+		// Access tokens em RS256, verificáveis por outros serviços via JWKS
+		privateKey, err := auth.LoadRSAPrivateKey(cfg.JWT.PrivateKeyFile)
+		if err != nil {
+			log.Fatalf("Erro ao carregar a chave privada JWT: %v", err)
+		}
+		jwtService = auth.NewJWTServiceRSA(privateKey, &privateKey.PublicKey, cfg.JWT.ExpirationHours, cfg.JWT.RefreshSecret, cfg.JWT.RefreshExpHours, jwtOpts...)
+	} else {
+		jwtService = auth.NewJWTService(cfg.JWT.Secret, cfg.JWT.ExpirationHours, cfg.JWT.RefreshSecret, cfg.JWT.RefreshExpHours, jwtOpts...)
 	}
 
-	jwtService := auth.NewJWTService(
-		secretKey,
-		24, // Você pode substituir por os.Getenv("JWT_EXPIRATION_HOURS")
-		refreshKey,
-		168, // Você pode substituir por os.Getenv("JWT_REFRESH_EXPIRATION_HOURS")
-	)
+	activityStore := activity.NewMemoryStore(activity.DefaultMaxEventsPerUser)
+	auditStore := audit.NewMemoryStore(audit.DefaultMaxEvents)
+
+	// Revogações de refresh tokens; banco e memória têm limpeza periódica das
+	// expiradas, enquanto no Redis cada chave expira junto com o token
+	// Varreduras em segundo plano, com a saúde exposta em /status
+	sweepers := scheduler.NewRegistry()
 
-	userService := service.NewUserService(userRepository, jwtService)
+	var revokedTokens domain.TokenBlacklist
+	switch cfg.Revoke.Backend {
+	case config.RevocationBackendRedis:
+		redisTokens, err := blacklist.NewRedisStoreFromURL(cfg.Revoke.RedisURL)
+		if err != nil {
+			log.Fatalf("REDIS_URL inválida: %v", err)
+		}
+		defer redisTokens.Close()
+		revokedTokens = redisTokens
+	case config.RevocationBackendMemory:
+		memoryTokens := blacklist.NewMemoryStore(nil)
+		revokedTokenSweeper := scheduler.NewSweeper("revoked_tokens", cfg.Revoke.PurgeInterval, memoryTokens.PurgeExpired)
+		revokedTokenSweeper.Start()
+		sweepers.Register(revokedTokenSweeper)
+		defer revokedTokenSweeper.Stop()
+		revokedTokens = memoryTokens
+	default:
+		dbTokens := repository.NewRevokedTokenRepository(prisma.DB)
+		revokedTokenSweeper := scheduler.NewSweeper("revoked_tokens", cfg.Revoke.PurgeInterval, dbTokens.PurgeExpired)
+		revokedTokenSweeper.Start()
+		sweepers.Register(revokedTokenSweeper)
+		defer revokedTokenSweeper.Stop()
+		revokedTokens = dbTokens
+	}
+
+	// Tokens de redefinição e de verificação e as notificações seguem pelo webhook;
+	// sem ele, os eventos ficam apenas no log
+	var publisher domain.EventPublisher = events.NewLogPublisher()
+	if cfg.Notify.WebhookURL != "" {
+		publisher = events.NewWebhookPublisher(cfg.Notify.WebhookURL, nil)
+	}
+
+	serviceOpts := []service.UserServiceOption{
+		service.WithEventPublisher(publisher),
+		service.WithEmailChangeNotification(cfg.Notify.EmailChange),
+		service.WithResetRedirectAllowlist(cfg.Reset.AllowedRedirectURIs),
+		service.WithSessionRevocationOnPasswordChange(cfg.Session.RevokeOnPasswordChange),
+		service.WithActivityStore(activityStore),
+		service.WithAuditStore(auditStore),
+		service.WithTokenBlacklist(revokedTokens),
+		service.WithUsernameLogin(cfg.Login.AllowUsername),
+		service.WithDeferredRefreshToken(cfg.Login.DeferRefreshToken),
+		service.WithTenantScopedEmail(cfg.Account.TenantScopedEmail),
+		service.WithHashConcurrency(cfg.Bcrypt.MaxConcurrent, cfg.Bcrypt.QueueTimeout),
+		service.WithBcryptCost(cfg.Bcrypt.Cost),
+		service.WithPasswordMinAge(cfg.Password.MinAge),
+		service.WithEmailVerificationRequired(cfg.Register.RequireEmailVerification),
+	}
+	if cfg.Session.Enabled {
+		// Sessões em memória: refresh tokens emitidos não sobrevivem a reinícios
+		serviceOpts = append(serviceOpts, service.WithSessionStore(session.NewMemoryStore(), cfg.Session.MaxPerUser))
+	}
+	if cfg.Login.LockoutThreshold > 0 {
+		// Falhas de login em memória, com limpeza periódica dos contadores vencidos
+		loginAttempts := lockout.NewMemoryStore(cfg.Login.LockoutDuration, nil)
+		loginAttemptSweeper := scheduler.NewSweeper("login_attempts", cfg.Login.LockoutDuration, loginAttempts.PurgeExpired)
+		loginAttemptSweeper.Start()
+		sweepers.Register(loginAttemptSweeper)
+		defer loginAttemptSweeper.Stop()
+		serviceOpts = append(serviceOpts, service.WithLoginLockout(loginAttempts, cfg.Login.LockoutThreshold, cfg.Login.LockoutDuration))
+	}
+	if cfg.Password.HashAlgorithm == config.HashAlgorithmArgon2id {
+		// Parâmetros já conferidos em cfg.Validate
+		argon2Hasher, err := hashing.NewArgon2Hasher(cfg.Argon2.Params())
+		if err != nil {
+			log.Fatalf("Configuração inválida: ARGON2_*: %v", err)
+		}
+		serviceOpts = append(serviceOpts, service.WithArgon2Hasher(argon2Hasher))
+	}
+	userService := service.NewUserService(userRepository, jwtService, serviceOpts...)
 
 	// Inicializar os controllers
-	userController := user.NewUserController(userService)
-	adminController := user.NewAdminController(userService)
+	userController := user.NewUserController(userService,
+		user.WithCookieConfig(user.CookieConfig{
+			ForceSecure:  cfg.Cookie.ForceSecure,
+			RefreshToken: cfg.Cookie.RefreshToken,
+		}),
+		user.WithReservedLocalParts(cfg.Register.ReservedLocalParts),
+		user.WithPasswordConfirmation(cfg.Register.RequirePasswordConfirmation),
+	)
+	adminController := user.NewAdminController(userService,
+		user.WithConfigSnapshot(cfg.Redacted()),
+		user.WithDuplicateRolesRejected(cfg.Account.RejectDuplicateRoles),
+	)
 
 	// Inicializar e configurar as rotas
-	userRoutes := routes.NewUserRoutes(userController, jwtService, adminController)
+	authOpts := []middleware.AuthOption{
+		middleware.WithAuthenticatedUserHeader(cfg.Debug.ExposeUserHeader),
+		middleware.WithRolePermissions(rolePermissions),
+		middleware.WithAccountStatus(middleware.NewAccountStatusMiddleware(userService, cfg.Account.StatusCacheTTL, nil)),
+	}
+	if cfg.Account.StrictClaims {
+		authOpts = append(authOpts, middleware.WithStrictClaims(userService))
+	}
+	if cfg.Audit.DeniedAccess {
+		// Acessos recusados por falta de papel ou permissão entram em GET /admin/audit
+		authOpts = append(authOpts, middleware.WithDeniedAccessAudit(auditStore))
+	}
+	userRoutes := routes.NewUserRoutes(userController, jwtService, adminController, authOpts...).
+		WithActivityController(user.NewActivityController(activityStore)).
+		WithAuditController(user.NewAuditController(auditStore)).
+		WithRateLimit(middleware.RateLimit(cfg.Login.RateLimitRPS, cfg.Login.RateLimitBurst))
+	if cfg.Nonce.Required {
+		// Nonces de uso único contra reenvio de formulários sensíveis
+		nonceStore := noncestore.NewMemoryStore(nil)
+		nonceSweeper := scheduler.NewSweeper("nonces", cfg.Nonce.TTL, nonceStore.PurgeExpired)
+		nonceSweeper.Start()
+		sweepers.Register(nonceSweeper)
+		defer nonceSweeper.Stop()
+
+		userRoutes.WithNonceProtection(middleware.RequireNonce(nonceStore))
+		routes.NewNonceRoutes(nonce.NewNonceController(nonceStore, cfg.Nonce.TTL, nil)).Setup(router)
+	}
 	userRoutes.Setup(router)
 
-	// Iniciar o servidor
-	log.Println("Server running on http://localhost:8080")
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Server error: %v", err)
+	discoveryController := discovery.NewDiscoveryController(cfg.JWT.IssuerURL, jwtService)
+	routes.NewDiscoveryRoutes(discoveryController).Setup(router)
+
+	introspectController := introspect.NewIntrospectController(jwtService, userService, introspect.DefaultMaxBatchSize)
+	authRoutes := routes.NewAuthRoutes(introspectController, jwtService, authOpts...)
+	if cfg.Signing.Secret != "" {
+		// Chamadas internas assinadas com HMAC do corpo em X-Signature; sem o
+		// segredo, a rota exige um access token de admin
+		authRoutes.WithRequestSigning(middleware.RequireSignature([]byte(cfg.Signing.Secret)))
+	}
+	authRoutes.Setup(router)
+
+	routes.NewStatusRoutes(status.NewStatusController(sweepers)).Setup(router)
+
+	// Iniciar o servidor com a porta e os timeouts configurados
+	srv := server.New(cfg.Server, router)
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		logging.Error("Não foi possível abrir a porta %d: %v", cfg.Server.Port, err)
+		return
+	}
+
+	// SIGINT/SIGTERM encerram o servidor após as requisições em andamento; os
+	// defers (varreduras e conexão com o banco) rodam depois disso
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Server running on http://localhost:%d", cfg.Server.Port)
+	if err := server.Serve(ctx, srv, listener, cfg.Server.ShutdownTimeout); err != nil {
+		logging.Error("Erro no servidor: %v", err)
+		return
 	}
+	logging.Info("Servidor encerrado")
 }
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// watchMaintenanceSignal alterna o modo de manutenção a cada SIGUSR1 recebido
+// (kill -USR1 <pid>), sem reiniciar o servidor
+func watchMaintenanceSignal(maintenance *atomic.Bool) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		for range signals {
+			enabled := !maintenance.Load()
+			maintenance.Store(enabled)
+			if enabled {
+				logging.Warning("Modo de manutenção ligado via SIGUSR1")
+			} else {
+				logging.Info("Modo de manutenção desligado via SIGUSR1")
+			}
+		}
+	}()
+}
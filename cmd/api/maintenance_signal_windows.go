@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+import "sync/atomic"
+
+// watchMaintenanceSignal não faz nada no Windows, que não tem SIGUSR1; o modo
+// de manutenção fica restrito a MAINTENANCE_MODE
+func watchMaintenanceSignal(*atomic.Bool) {}
@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"sync"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// DefaultMaxEventsPerUser limita o histórico mantido em memória por usuário
+const DefaultMaxEventsPerUser = 100
+
+// MemoryStore é uma implementação em memória de domain.ActivityStore que mantém
+// apenas os eventos mais recentes de cada usuário
+type MemoryStore struct {
+	mu        sync.RWMutex
+	events    map[string][]*domain.ActivityEvent // por usuário, do mais antigo para o mais recente
+	maxEvents int
+}
+
+// Garantir que MemoryStore implementa domain.ActivityStore
+var _ domain.ActivityStore = (*MemoryStore)(nil)
+
+// NewMemoryStore cria um novo histórico em memória; maxEvents <= 0 usa o padrão
+func NewMemoryStore(maxEvents int) *MemoryStore {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEventsPerUser
+	}
+	return &MemoryStore{
+		events:    make(map[string][]*domain.ActivityEvent),
+		maxEvents: maxEvents,
+	}
+}
+
+// Record registra um evento, descartando o mais antigo quando o limite é atingido
+func (s *MemoryStore) Record(event *domain.ActivityEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *event
+	events := append(s.events[event.UserID], &copied)
+	if len(events) > s.maxEvents {
+		events = events[len(events)-s.maxEvents:]
+	}
+	s.events[event.UserID] = events
+	return nil
+}
+
+// ListByUser retorna uma página dos eventos do usuário, dos mais recentes para os
+// mais antigos, junto com o total de eventos
+func (s *MemoryStore) ListByUser(userID string, offset, limit int) ([]*domain.ActivityEvent, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := s.events[userID]
+	total := len(events)
+
+	page := make([]*domain.ActivityEvent, 0, max(min(limit, total-offset), 0))
+	for i := total - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		copied := *events[i]
+		page = append(page, &copied)
+	}
+	return page, total, nil
+}
@@ -0,0 +1,43 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_ListByUser_NewestFirstAndPaginated(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		_ = store.Record(&domain.ActivityEvent{UserID: "u1", Type: domain.ActivityLogin, At: now.Add(time.Duration(i) * time.Second)})
+	}
+	_ = store.Record(&domain.ActivityEvent{UserID: "u2", Type: domain.ActivityLogin, At: now})
+
+	page, total, err := store.ListByUser("u1", 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	if assert.Len(t, page, 2) {
+		assert.True(t, page[0].At.After(page[1].At), "eventos devem vir dos mais recentes para os mais antigos")
+	}
+
+	last, _, _ := store.ListByUser("u1", 4, 2)
+	assert.Len(t, last, 1)
+
+	beyond, _, _ := store.ListByUser("u1", 10, 2)
+	assert.Empty(t, beyond)
+}
+
+func TestMemoryStore_Record_KeepsOnlyMostRecent(t *testing.T) {
+	store := NewMemoryStore(2)
+	for _, detail := range []string{"a", "b", "c"} {
+		_ = store.Record(&domain.ActivityEvent{UserID: "u1", Type: domain.ActivityLogin, Detail: detail})
+	}
+
+	events, total, _ := store.ListByUser("u1", 0, 10)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "c", events[0].Detail)
+	assert.Equal(t, "b", events[1].Detail)
+}
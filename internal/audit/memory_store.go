@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"sync"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// DefaultMaxEvents limita a trilha de auditoria mantida em memória
+const DefaultMaxEvents = 1000
+
+// MemoryStore é uma implementação em memória de domain.AuditStore que mantém
+// apenas os eventos mais recentes
+type MemoryStore struct {
+	mu        sync.RWMutex
+	events    []*domain.AuditEvent // do mais antigo para o mais recente
+	maxEvents int
+}
+
+// Garantir que MemoryStore implementa domain.AuditStore
+var _ domain.AuditStore = (*MemoryStore)(nil)
+
+// NewMemoryStore cria uma nova trilha em memória; maxEvents <= 0 usa o padrão
+func NewMemoryStore(maxEvents int) *MemoryStore {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+	return &MemoryStore{maxEvents: maxEvents}
+}
+
+// Record registra um evento, descartando o mais antigo quando o limite é atingido
+func (s *MemoryStore) Record(event *domain.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *event
+	s.events = append(s.events, &copied)
+	if len(s.events) > s.maxEvents {
+		s.events = s.events[len(s.events)-s.maxEvents:]
+	}
+	return nil
+}
+
+// List retorna uma página dos eventos, dos mais recentes para os mais antigos,
+// junto com o total de eventos
+func (s *MemoryStore) List(offset, limit int) ([]*domain.AuditEvent, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := len(s.events)
+	page := make([]*domain.AuditEvent, 0, max(min(limit, total-offset), 0))
+	for i := total - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		copied := *s.events[i]
+		page = append(page, &copied)
+	}
+	return page, total, nil
+}
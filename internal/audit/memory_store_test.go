@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_List_NewestFirstAndPaginated(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		_ = store.Record(&domain.AuditEvent{ActorID: "adm", Action: domain.AuditActionUserUpdated, TargetID: "u1", At: now.Add(time.Duration(i) * time.Second)})
+	}
+
+	page, total, err := store.List(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	if assert.Len(t, page, 2) {
+		assert.True(t, page[0].At.After(page[1].At), "eventos devem vir dos mais recentes para os mais antigos")
+	}
+
+	last, _, _ := store.List(4, 2)
+	assert.Len(t, last, 1)
+
+	beyond, _, _ := store.List(10, 2)
+	assert.Empty(t, beyond)
+}
+
+func TestMemoryStore_Record_KeepsOnlyMostRecent(t *testing.T) {
+	store := NewMemoryStore(2)
+	for _, target := range []string{"a", "b", "c"} {
+		_ = store.Record(&domain.AuditEvent{ActorID: "adm", Action: domain.AuditActionUserDeleted, TargetID: target})
+	}
+
+	events, total, _ := store.List(0, 10)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "c", events[0].TargetID)
+	assert.Equal(t, "b", events[1].TargetID)
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxBearerTokenLength limita o tamanho do token aceito no cabeçalho
+// Authorization, recusando valores absurdos antes de tentar validá-los
+const MaxBearerTokenLength = 8192
+
+// ErrMissingBearerToken indica um cabeçalho Authorization ausente ou vazio
+var ErrMissingBearerToken = errors.New("cabeçalho Authorization ausente")
+
+// ErrInvalidAuthorizationHeader indica um cabeçalho fora do formato "Bearer <token>"
+var ErrInvalidAuthorizationHeader = errors.New("formato de autorização inválido")
+
+// ExtractBearerToken extrai o token de um cabeçalho Authorization no formato
+// "Bearer <token>". O esquema é comparado sem diferenciar maiúsculas (RFC 7235)
+// e tokens vazios, com espaços ou maiores que MaxBearerTokenLength são recusados.
+func ExtractBearerToken(header string) (string, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return "", ErrMissingBearerToken
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("%w: esquema diferente de Bearer", ErrInvalidAuthorizationHeader)
+	}
+
+	token = strings.TrimSpace(token)
+	switch {
+	case token == "":
+		return "", fmt.Errorf("%w: token vazio", ErrInvalidAuthorizationHeader)
+	case len(token) > MaxBearerTokenLength:
+		return "", fmt.Errorf("%w: token com %d bytes (máximo %d)", ErrInvalidAuthorizationHeader, len(token), MaxBearerTokenLength)
+	case strings.ContainsAny(token, " \t"):
+		return "", fmt.Errorf("%w: token com espaços", ErrInvalidAuthorizationHeader)
+	}
+	return token, nil
+}
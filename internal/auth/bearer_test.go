@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractBearerToken(t *testing.T) {
+	cases := []struct {
+		name    string
+		header  string
+		token   string
+		wantErr error
+	}{
+		{"válido", "Bearer abc.def.ghi", "abc.def.ghi", nil},
+		{"esquema minúsculo", "bearer abc.def.ghi", "abc.def.ghi", nil},
+		{"espaços extras", "  Bearer   abc.def.ghi ", "abc.def.ghi", nil},
+		{"ausente", "", "", ErrMissingBearerToken},
+		{"apenas espaços", "   ", "", ErrMissingBearerToken},
+		{"sem esquema", "abc.def.ghi", "", ErrInvalidAuthorizationHeader},
+		{"outro esquema", "Basic dXNlcjpwYXNz", "", ErrInvalidAuthorizationHeader},
+		{"token vazio", "Bearer ", "", ErrInvalidAuthorizationHeader},
+		{"somente o esquema", "Bearer", "", ErrInvalidAuthorizationHeader},
+		{"token com espaços", "Bearer abc def", "", ErrInvalidAuthorizationHeader},
+		{"token longo demais", "Bearer " + strings.Repeat("a", MaxBearerTokenLength+1), "", ErrInvalidAuthorizationHeader},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := ExtractBearerToken(tc.header)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, token)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.token, token)
+		})
+	}
+}
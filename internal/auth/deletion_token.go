@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenTypeAccountDeletion é o valor da claim typ dos tokens de confirmação de exclusão
+const TokenTypeAccountDeletion = "account_deletion"
+
+// DefaultAccountDeletionTTL é a validade padrão de um token de confirmação de exclusão
+const DefaultAccountDeletionTTL = 15 * time.Minute
+
+// AccountDeletionClaims define as claims do token que confirma a exclusão da
+// conta do sub
+type AccountDeletionClaims struct {
+	// Type identifica o propósito do token (TokenTypeAccountDeletion)
+	Type string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// WithAccountDeletionTTL define a validade dos tokens de confirmação de exclusão;
+// ttl <= 0 usa DefaultAccountDeletionTTL
+func WithAccountDeletionTTL(ttl time.Duration) JWTOption {
+	return func(s *JWTService) {
+		s.deletionTTL = ttl
+	}
+}
+
+// AccountDeletionTTL retorna a validade dos tokens de confirmação de exclusão
+func (s *JWTService) AccountDeletionTTL() time.Duration {
+	if s.deletionTTL <= 0 {
+		return DefaultAccountDeletionTTL
+	}
+	return s.deletionTTL
+}
+
+// IssueAccountDeletionToken gera um token de confirmação de exclusão da conta do
+// usuário. A chave é derivada da de refresh, exclusiva para este propósito.
+func (s *JWTService) IssueAccountDeletionToken(userID string) (string, *AccountDeletionClaims, error) {
+	now := time.Now()
+	claims := &AccountDeletionClaims{
+		Type: TokenTypeAccountDeletion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.AccountDeletionTTL())),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.derivedKey(TokenTypeAccountDeletion)))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ValidateAccountDeletionToken valida a assinatura, a expiração e o propósito do
+// token de confirmação de exclusão
+func (s *JWTService) ValidateAccountDeletionToken(tokenString string) (*AccountDeletionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AccountDeletionClaims{}, hmacKeyfunc(s.derivedKey(TokenTypeAccountDeletion)))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*AccountDeletionClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token de confirmação de exclusão inválido")
+	}
+	if claims.Type != TokenTypeAccountDeletion {
+		return nil, ErrUnexpectedTokenType
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token de confirmação de exclusão sem sub")
+	}
+	return claims, nil
+}
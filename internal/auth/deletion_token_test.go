@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTService_AccountDeletionTokenRoundTrip(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithAccountDeletionTTL(5*time.Minute))
+
+	token, issued, err := jwtService.IssueAccountDeletionToken("123")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), issued.ExpiresAt.Time, 5*time.Second)
+
+	claims, err := jwtService.ValidateAccountDeletionToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.Subject)
+	assert.Equal(t, TokenTypeAccountDeletion, claims.Type)
+
+	// Tokens de outros propósitos não confirmam a exclusão, e vice-versa
+	refresh, _ := jwtService.GenerateRefreshToken("123")
+	_, err = jwtService.ValidateAccountDeletionToken(refresh)
+	assert.Error(t, err)
+	reset, _, _ := jwtService.IssuePasswordResetToken(&domain.User{ID: "123", Email: "a@b.com"})
+	_, err = jwtService.ValidateAccountDeletionToken(reset)
+	assert.Error(t, err)
+	_, err = jwtService.ValidatePasswordResetToken(token)
+	assert.Error(t, err)
+
+	assert.Equal(t, DefaultAccountDeletionTTL, NewJWTService("s", 1, "r", 1).AccountDeletionTTL())
+}
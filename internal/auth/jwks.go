@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK representa uma chave pública no formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKSet representa o documento servido em /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// RSAPublicJWK converte uma chave pública RSA em JWK para assinaturas RS256
+func RSAPublicJWK(kid string, pub *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// SigningAlgorithm retorna o algoritmo usado na assinatura dos access tokens
+func (s *JWTService) SigningAlgorithm() string {
+	if s.usesRSA() {
+		return "RS256"
+	}
+	return "HS256"
+}
+
+// PublicJWKs retorna as chaves públicas de verificação dos access tokens.
+// Chaves HMAC são segredos compartilhados e nunca são expostas, então o
+// conjunto fica vazio enquanto apenas HS256 estiver configurado.
+func (s *JWTService) PublicJWKs() []JWK {
+	if s.usesRSA() {
+		return []JWK{RSAPublicJWK(s.keyID, s.publicKey)}
+	}
+	return []JWK{}
+}
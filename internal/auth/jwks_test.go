@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSAPublicJWK(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	jwk := RSAPublicJWK("key-1", &key.PublicKey)
+
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "RS256", jwk.Alg)
+	assert.Equal(t, "key-1", jwk.Kid)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(key.PublicKey.N))
+	assert.Equal(t, "AQAB", jwk.E) // 65537
+}
+
+func TestJWTService_PublicJWKs_HMACNeverExposed(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	assert.Empty(t, jwtService.PublicJWKs())
+	assert.Equal(t, "HS256", jwtService.SigningAlgorithm())
+}
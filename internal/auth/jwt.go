@@ -1,19 +1,144 @@
 package auth
 
 import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
 )
 
+// PasswordChangeTokenTTL é a validade do token restrito emitido a usuários que
+// precisam trocar a senha antes de continuar
+const PasswordChangeTokenTTL = 15 * time.Minute
+
+// MinSecretLength é o tamanho mínimo recomendado (em bytes) para chaves HMAC
+const MinSecretLength = 32
+
+// ErrWeakSecret indica uma chave HMAC menor que o tamanho recomendado
+var ErrWeakSecret = errors.New("chave JWT menor que o tamanho recomendado")
+
+// Valores da claim typ, que distingue access tokens de refresh tokens
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Valores da claim amr (RFC 8176), que registra como o usuário se autenticou
+const (
+	AuthMethodPassword = "pwd"
+	AuthMethodOTP      = "otp"
+	AuthMethodMFA      = "mfa"
+)
+
+// ErrUnexpectedTokenType indica um token de outro tipo, como um refresh token
+// apresentado no lugar de um access token
+var ErrUnexpectedTokenType = errors.New("tipo de token inesperado")
+
+// ErrUnexpectedSigningMethod indica um token assinado com algoritmo diferente de
+// HMAC (ex.: "none" ou RS256), recusado para evitar ataques de confusão de algoritmo
+var ErrUnexpectedSigningMethod = errors.New("método de assinatura inesperado")
+
+// ValidateSecret verifica se a chave tem o tamanho mínimo recomendado para HS256
+func ValidateSecret(secret string) error {
+	if len(secret) < MinSecretLength {
+		return fmt.Errorf("%w: %d bytes (mínimo %d)", ErrWeakSecret, len(secret), MinSecretLength)
+	}
+	return nil
+}
+
 // JWTService é o serviço responsável por gerenciar tokens JWT
 type JWTService struct {
 	secretKey      string
 	expirationTime int
 	refreshKey     string
 	refreshExpTime int
+
+	// maxAccessTTL limita a validade de qualquer access token emitido (0 = sem limite)
+	maxAccessTTL time.Duration
+
+	// previousRefreshKeys ainda validam refresh tokens durante a rotação da chave
+	previousRefreshKeys []string
+
+	enforceTokenType bool
+
+	// issuer e audience são gravados nos access tokens e exigidos na validação
+	// quando configurados
+	issuer   string
+	audience string
+
+	// Com publicKey definida, os access tokens usam RS256; sem privateKey o
+	// serviço apenas verifica tokens emitidos por outra instância
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	keyID      string
+
+	// resetKey assina os tokens de redefinição de senha; vazia, é derivada da
+	// chave de refresh
+	resetKey string
+	resetTTL time.Duration
+
+	deletionTTL     time.Duration
+	verificationTTL time.Duration
+
+	// permissions deriva a claim permissions dos papéis; nil omite a claim
+	permissions domain.RolePermissions
+}
+
+// WithIssuer define a claim iss dos access tokens e passa a exigi-la na validação
+func WithIssuer(issuer string) JWTOption {
+	return func(s *JWTService) {
+		s.issuer = issuer
+	}
+}
+
+// WithAudience define a claim aud dos access tokens e passa a exigi-la na validação
+func WithAudience(audience string) JWTOption {
+	return func(s *JWTService) {
+		s.audience = audience
+	}
+}
+
+// WithMaxAccessTokenTTL impõe uma validade máxima aos access tokens: uma
+// expiração configurada acima do limite é reduzida a ele, evitando que um erro
+// de configuração emita tokens válidos por meses. maxTTL <= 0 remove o limite.
+func WithMaxAccessTokenTTL(maxTTL time.Duration) JWTOption {
+	return func(s *JWTService) {
+		s.maxAccessTTL = maxTTL
+		if configured := time.Duration(s.expirationTime) * time.Hour; maxTTL > 0 && configured > maxTTL {
+			logging.Warning("Expiração do access token (%v) acima do máximo permitido; limitada a %v", configured, maxTTL)
+		}
+	}
+}
+
+// WithRolePermissions inclui nos access tokens a claim permissions, derivada dos
+// papéis do usuário pelo mapeamento informado
+func WithRolePermissions(permissions domain.RolePermissions) JWTOption {
+	return func(s *JWTService) {
+		s.permissions = permissions
+	}
+}
+
+// ErrSigningKeyUnavailable indica um JWTService RS256 configurado apenas com a
+// chave pública, que não pode emitir access tokens
+var ErrSigningKeyUnavailable = errors.New("chave privada de assinatura não configurada")
+
+// JWTOption configura opções opcionais do JWTService
+type JWTOption func(*JWTService)
+
+// WithTokenTypeEnforcement liga (padrão) ou desliga a verificação da claim typ na
+// validação. Desligada, tokens emitidos antes da claim continuam aceitos.
+func WithTokenTypeEnforcement(enabled bool) JWTOption {
+	return func(s *JWTService) {
+		s.enforceTokenType = enabled
+	}
 }
 
 // TokenClaims define as claims customizadas para o token JWT
@@ -21,51 +146,245 @@ type TokenClaims struct {
 	UserID string   `json:"user_id"`
 	Email  string   `json:"email"`
 	Roles  []string `json:"roles"`
+	// Permissions são as permissões efetivas derivadas dos papéis
+	Permissions []string `json:"permissions,omitempty"`
+	// AuthMethods são os métodos usados na autenticação (AuthMethodPassword, ...)
+	AuthMethods []string `json:"amr,omitempty"`
+	// PasswordChangeRequired restringe o token à troca de senha
+	PasswordChangeRequired bool `json:"pwd_change,omitempty"`
+	// Type identifica o propósito do token (TokenTypeAccess)
+	Type string `json:"typ,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// RefreshClaims define as claims do refresh token
+type RefreshClaims struct {
+	// Type identifica o propósito do token (TokenTypeRefresh)
+	Type string `json:"typ,omitempty"`
+	// RevocationMark é o instante, em nanossegundos, da última revogação de todos
+	// os tokens do usuário vigente na emissão (0 quando não havia nenhuma)
+	RevocationMark int64 `json:"rvk,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // NewJWTService cria uma nova instância do serviço JWT
-func NewJWTService(secretKey string, expirationHours int, refreshKey string, refreshExpHours int) *JWTService {
-	return &JWTService{
-		secretKey:      secretKey,
-		expirationTime: expirationHours,
-		refreshKey:     refreshKey,
-		refreshExpTime: refreshExpHours,
+func NewJWTService(secretKey string, expirationHours int, refreshKey string, refreshExpHours int, opts ...JWTOption) *JWTService {
+	// Chaves curtas enfraquecem a assinatura; em produção a validação da configuração as rejeita
+	if err := ValidateSecret(secretKey); err != nil {
+		logging.Warning("Chave do access token fraca: %v", err)
+	}
+	if err := ValidateSecret(refreshKey); err != nil {
+		logging.Warning("Chave do refresh token fraca: %v", err)
 	}
-}
 
-// GenerateToken gera um novo token JWT para o usuário
-func (s *JWTService) GenerateToken(user *domain.User) (string, error) {
-	expirationTime := time.Now().Add(time.Hour * time.Duration(s.expirationTime))
+	s := &JWTService{
+		secretKey:        secretKey,
+		expirationTime:   expirationHours,
+		refreshKey:       refreshKey,
+		refreshExpTime:   refreshExpHours,
+		enforceTokenType: true,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
 
-	claims := &TokenClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Roles:  user.Roles,
+// buildClaims monta as claims de um access token com jti novo, válidas a partir
+// de agora pelo ttl informado
+func buildClaims(userID, email string, roles []string, ttl time.Duration) *TokenClaims {
+	now := time.Now()
+	return &TokenClaims{
+		UserID: userID,
+		Email:  email,
+		Roles:  roles,
+		Type:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Subject:   user.ID,
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Subject:   userID,
 		},
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// NewJWTServiceRSA cria um serviço JWT que assina os access tokens com RS256, para
+// que outros serviços os verifiquem apenas com a chave pública (publicada no
+// JWKS). privateKey pode ser nil em instâncias que só verificam tokens. Refresh
+// tokens continuam assinados com HMAC, pois só esta aplicação os valida.
+func NewJWTServiceRSA(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, expirationHours int, refreshKey string, refreshExpHours int, opts ...JWTOption) *JWTService {
+	if publicKey == nil && privateKey != nil {
+		publicKey = &privateKey.PublicKey
+	}
+	if err := ValidateSecret(refreshKey); err != nil {
+		logging.Warning("Chave do refresh token fraca: %v", err)
+	}
+
+	s := &JWTService{
+		expirationTime:   expirationHours,
+		refreshKey:       refreshKey,
+		refreshExpTime:   refreshExpHours,
+		enforceTokenType: true,
+		privateKey:       privateKey,
+		publicKey:        publicKey,
+		keyID:            rsaKeyID(publicKey),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LoadRSAPrivateKey lê uma chave privada RSA em formato PEM (PKCS#1 ou PKCS#8)
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
+
+// rsaKeyID deriva um kid estável a partir do módulo da chave pública
+func rsaKeyID(pub *rsa.PublicKey) string {
+	if pub == nil {
+		return ""
+	}
+	sum := sha256.Sum256(pub.N.Bytes())
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// usesRSA indica se os access tokens são assinados com RS256
+func (s *JWTService) usesRSA() bool {
+	return s.publicKey != nil
+}
+
+// signAccessToken assina as claims de um access token com o algoritmo configurado,
+// incluindo o emissor e a audiência
+func (s *JWTService) signAccessToken(claims *TokenClaims) (string, error) {
+	claims.Issuer = s.issuer
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
+	}
+
+	if !s.usesRSA() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.secretKey))
+	}
+	if s.privateKey == nil {
+		return "", ErrSigningKeyUnavailable
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.privateKey)
+}
+
+// accessKeyfunc retorna a chave de verificação dos access tokens, aceitando apenas
+// o método de assinatura configurado
+func (s *JWTService) accessKeyfunc() jwt.Keyfunc {
+	if !s.usesRSA() {
+		return hmacKeyfunc(s.secretKey)
+	}
+	return func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
+		}
+		return s.publicKey, nil
+	}
+}
+
+// AccessTTL retorna a validade dos access tokens emitidos, já limitada por
+// WithMaxAccessTokenTTL
+func (s *JWTService) AccessTTL() time.Duration {
+	return s.capAccessTTL(time.Hour * time.Duration(s.expirationTime))
+}
 
-	return token.SignedString([]byte(s.secretKey))
+// capAccessTTL reduz ttl ao máximo configurado para access tokens
+func (s *JWTService) capAccessTTL(ttl time.Duration) time.Duration {
+	if s.maxAccessTTL > 0 && ttl > s.maxAccessTTL {
+		return s.maxAccessTTL
+	}
+	return ttl
+}
+
+// GenerateToken gera um novo token JWT para o usuário. Os métodos de
+// autenticação informados são gravados na claim amr.
+func (s *JWTService) GenerateToken(user *domain.User, methods ...string) (string, error) {
+	token, _, err := s.IssueToken(user, methods...)
+	return token, err
+}
+
+// IssueToken gera um access token como GenerateToken e retorna também as suas
+// claims, cujo ID (jti) identifica o token emitido
+func (s *JWTService) IssueToken(user *domain.User, methods ...string) (string, *TokenClaims, error) {
+	claims := buildClaims(user.ID, user.Email, user.Roles, s.AccessTTL())
+	if len(methods) > 0 {
+		claims.AuthMethods = methods
+	}
+	if s.permissions != nil {
+		claims.Permissions = s.permissions.For(user.Roles)
+	}
+
+	token, err := s.signAccessToken(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
+// GeneratePasswordChangeToken gera um access token de curta duração que só
+// permite a troca de senha (claim pwd_change)
+func (s *JWTService) GeneratePasswordChangeToken(user *domain.User) (string, error) {
+	claims := buildClaims(user.ID, user.Email, user.Roles, s.capAccessTTL(PasswordChangeTokenTTL))
+	claims.PasswordChangeRequired = true
+
+	return s.signAccessToken(claims)
+}
+
+// IsPasswordChangeToken indica, sem verificar a assinatura, se o token é restrito à
+// troca de senha. Use apenas com tokens recém-emitidos pela própria aplicação.
+func IsPasswordChangeToken(tokenString string) bool {
+	claims := &TokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+	return claims.PasswordChangeRequired
+}
+
+// hmacKeyfunc retorna a chave apenas para tokens assinados com HMAC, sem confiar
+// no cabeçalho alg informado pelo cliente
+func hmacKeyfunc(key string) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
+		}
+		return []byte(key), nil
+	}
+}
+
+// accessParserOptions exige o emissor e a audiência configurados
+func (s *JWTService) accessParserOptions() []jwt.ParserOption {
+	var opts []jwt.ParserOption
+	if s.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.issuer))
+	}
+	if s.audience != "" {
+		opts = append(opts, jwt.WithAudience(s.audience))
+	}
+	return opts
 }
 
 // ValidateToken valida um token JWT e retorna as claims se válido
 func (s *JWTService) ValidateToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, s.accessKeyfunc(), s.accessParserOptions()...)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*TokenClaims); ok && token.Valid {
+		if err := s.checkTokenType(claims.Type, TokenTypeAccess); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
@@ -74,35 +393,102 @@ func (s *JWTService) ValidateToken(tokenString string) (*TokenClaims, error) {
 
 // GenerateRefreshToken gera um token de atualização
 func (s *JWTService) GenerateRefreshToken(userID string) (string, error) {
-	expirationTime := time.Now().Add(time.Hour * time.Duration(s.refreshExpTime))
+	token, _, err := s.IssueRefreshToken(userID)
+	return token, err
+}
 
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(expirationTime),
-		Subject:   userID,
+// IssueRefreshToken gera um token de atualização e retorna também as suas claims,
+// cujo ID (jti) identifica a sessão correspondente
+func (s *JWTService) IssueRefreshToken(userID string) (string, *jwt.RegisteredClaims, error) {
+	token, claims, err := s.IssueRefreshTokenAt(userID, time.Now(), 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &claims.RegisteredClaims, nil
+}
+
+// IssueRefreshTokenAt gera um token de atualização emitido em now, registrando a
+// marca da última revogação de todos os tokens do usuário (RevocationMark)
+func (s *JWTService) IssueRefreshTokenAt(userID string, now time.Time, revocationMark int64) (string, *RefreshClaims, error) {
+	expirationTime := now.Add(time.Hour * time.Duration(s.refreshExpTime))
+
+	claims := &RefreshClaims{
+		Type:           TokenTypeRefresh,
+		RevocationMark: revocationMark,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   userID,
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = hmacKeyID(s.refreshKey)
 
-	return token.SignedString([]byte(s.refreshKey))
+	signed, err := token.SignedString([]byte(s.refreshKey))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
-// ValidateRefreshToken valida um refresh token e retorna as claims se válido
-func (s *JWTService) ValidateRefreshToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (any, error) {
-		return []byte(s.refreshKey), nil
-	})
+// ValidateRefreshToken valida um refresh token, assinado com a chave atual ou
+// com uma das anteriores (WithPreviousRefreshKeys), e retorna as claims se válido
+func (s *JWTService) ValidateRefreshToken(tokenString string) (*RefreshClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, s.refreshKeyfunc())
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*RefreshClaims); ok && token.Valid {
+		if err := s.checkTokenType(claims.Type, TokenTypeRefresh); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("refresh token inválido")
 }
 
+// checkTokenType recusa tokens cuja claim typ difere da esperada, quando a
+// verificação está habilitada
+func (s *JWTService) checkTokenType(got, want string) error {
+	if s.enforceTokenType && got != want {
+		return fmt.Errorf("%w: esperado %q, recebido %q", ErrUnexpectedTokenType, want, got)
+	}
+	return nil
+}
+
+// RefreshTTL retorna a validade dos refresh tokens emitidos
+func (s *JWTService) RefreshTTL() time.Duration {
+	return time.Hour * time.Duration(s.refreshExpTime)
+}
+
+// TokenExpiry lê a claim exp de um token sem verificar a assinatura. Use apenas
+// com tokens recém-emitidos pela própria aplicação, nunca para autenticar.
+func TokenExpiry(tokenString string) (time.Time, error) {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.ExpiresAt == nil {
+		return time.Time{}, errors.New("token sem claim exp")
+	}
+	return claims.ExpiresAt.Time, nil
+}
+
+// TokenID lê a claim jti de um token sem verificar a assinatura, retornando vazio
+// se o token for malformado ou não tiver jti
+func TokenID(tokenString string) string {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return ""
+	}
+	return claims.ID
+}
+
 // GetRefreshKey retorna a chave de refresh (uso exclusivo para testes)
 func (s *JWTService) GetRefreshKey() string {
 	return s.refreshKey
@@ -1,13 +1,40 @@
 package auth
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
 	"github.com/stretchr/testify/assert"
 )
 
+// logBuf captura os logs do pacote durante os testes
+var logBuf bytes.Buffer
+
+func TestMain(m *testing.M) {
+	logging.SetupLogger(logging.Config{
+		InfoWriter:    &logBuf,
+		WarningWriter: &logBuf,
+		ErrorWriter:   &logBuf,
+		Flag:          log.LstdFlags,
+	})
+	os.Exit(m.Run())
+}
+
 func TestJWTService_GenerateAndValidateToken(t *testing.T) {
 	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
 	user := &domain.User{ID: "123", Email: "test@example.com", Roles: []string{"admin"}}
@@ -22,6 +49,86 @@ func TestJWTService_GenerateAndValidateToken(t *testing.T) {
 	assert.Equal(t, user.Roles, claims.Roles)
 }
 
+func TestJWTService_RolesClaimRoundTrip(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	token, err := jwtService.GenerateToken(&domain.User{ID: "123", Email: "admin@example.com", Roles: []string{"admin"}})
+	assert.NoError(t, err)
+
+	// O payload carrega as roles sob a chave "roles", lida por outros serviços
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+	var raw map[string]any
+	assert.NoError(t, json.Unmarshal(payload, &raw))
+	assert.Equal(t, []any{"admin"}, raw["roles"])
+
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+}
+
+func TestJWTService_PermissionsClaim_AdminRole(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1,
+		WithRolePermissions(domain.RolePermissions{
+			domain.RoleAdmin: {"users:write", "users:read"},
+			domain.RoleUser:  {"profile:read"},
+		}))
+	token, err := jwtService.GenerateToken(&domain.User{ID: "123", Email: "admin@example.com", Roles: []string{"admin"}})
+	assert.NoError(t, err)
+
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users:read", "users:write"}, claims.Permissions)
+}
+
+func TestJWTService_PermissionsClaim_OmittedWithoutMapping(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	token, err := jwtService.GenerateToken(&domain.User{ID: "123", Email: "admin@example.com", Roles: []string{"admin"}})
+	assert.NoError(t, err)
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(payload), "permissions")
+}
+
+func TestJWTService_AuthMethodsClaim(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	token, err := jwtService.GenerateToken(&domain.User{ID: "123", Email: "a@b.com"}, AuthMethodPassword, AuthMethodOTP)
+	assert.NoError(t, err)
+
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pwd", "otp"}, claims.AuthMethods)
+
+	// Sem métodos informados, a claim é omitida
+	token, _ = jwtService.GenerateToken(&domain.User{ID: "123", Email: "a@b.com"})
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(payload), "amr")
+}
+
+func TestJWTService_MaxAccessTokenTTL_ClampsConfiguredTTL(t *testing.T) {
+	// Um ano de validade configurado por engano é reduzido ao máximo de 12h
+	jwtService := NewJWTService("test-secret", 24*365, "test-refresh", 1, WithMaxAccessTokenTTL(12*time.Hour))
+	assert.Equal(t, 12*time.Hour, jwtService.AccessTTL())
+
+	token, err := jwtService.GenerateToken(&domain.User{ID: "123", Email: "a@b.com"})
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+}
+
+func TestJWTService_MaxAccessTokenTTL_KeepsShorterTTL(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithMaxAccessTokenTTL(12*time.Hour))
+	assert.Equal(t, time.Hour, jwtService.AccessTTL())
+
+	// Sem limite, a validade configurada é usada integralmente
+	unlimited := NewJWTService("test-secret", 24*365, "test-refresh", 1, WithMaxAccessTokenTTL(0))
+	assert.Equal(t, 24*365*time.Hour, unlimited.AccessTTL())
+}
+
 func TestJWTService_ValidateToken_InvalidToken(t *testing.T) {
 	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
 	_, err := jwtService.ValidateToken("tokeninvalido")
@@ -49,6 +156,19 @@ func TestJWTService_GenerateAndValidateRefreshToken(t *testing.T) {
 	assert.Equal(t, "123", claims.Subject)
 }
 
+func TestJWTService_IssueRefreshTokenAt(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	issuedAt := time.Now().Add(-time.Minute)
+	token, _, err := jwtService.IssueRefreshTokenAt("123", issuedAt, 42)
+	assert.NoError(t, err)
+
+	claims, err := jwtService.ValidateRefreshToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), claims.RevocationMark)
+	assert.Equal(t, issuedAt.Unix(), claims.IssuedAt.Unix())
+	assert.Equal(t, issuedAt.Add(time.Hour).Unix(), claims.ExpiresAt.Unix())
+}
+
 func TestJWTService_ValidateRefreshToken_InvalidToken(t *testing.T) {
 	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
 	_, err := jwtService.ValidateRefreshToken("tokeninvalido")
@@ -69,3 +189,212 @@ func TestJWTService_Getters(t *testing.T) {
 	assert.Equal(t, "test-secret", jwtService.GetSecretKey())
 	assert.Equal(t, "test-refresh", jwtService.GetRefreshKey())
 }
+
+func TestNewJWTService_ShortSecretLogsWarning(t *testing.T) {
+	logBuf.Reset()
+	NewJWTService("curta", 1, strings.Repeat("r", MinSecretLength), 1)
+	assert.Contains(t, logBuf.String(), "WARNING: ")
+	assert.Contains(t, logBuf.String(), "access token")
+	assert.NotContains(t, logBuf.String(), "refresh token")
+
+	logBuf.Reset()
+	NewJWTService(strings.Repeat("s", MinSecretLength), 1, strings.Repeat("r", MinSecretLength), 1)
+	assert.Empty(t, logBuf.String())
+}
+
+func TestValidateSecret(t *testing.T) {
+	err := ValidateSecret("curta")
+	assert.True(t, errors.Is(err, ErrWeakSecret))
+	assert.NoError(t, ValidateSecret(strings.Repeat("s", MinSecretLength)))
+}
+
+func TestTokenExpiry_MatchesRefreshTTL(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 3)
+	assert.Equal(t, 3*time.Hour, jwtService.RefreshTTL())
+
+	token, err := jwtService.GenerateRefreshToken("123")
+	assert.NoError(t, err)
+
+	exp, err := TokenExpiry(token)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(jwtService.RefreshTTL()), exp, 2*time.Second)
+
+	_, err = TokenExpiry("tokeninvalido")
+	assert.Error(t, err)
+}
+
+func TestJWTService_TokensHaveDistinctJTI(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	user := &domain.User{ID: "123", Email: "test@example.com"}
+	seen := make(map[string]bool)
+
+	for i := 0; i < 3; i++ {
+		access, err := jwtService.GenerateToken(user)
+		assert.NoError(t, err)
+		restricted, err := jwtService.GeneratePasswordChangeToken(user)
+		assert.NoError(t, err)
+		refresh, err := jwtService.GenerateRefreshToken(user.ID)
+		assert.NoError(t, err)
+
+		for _, token := range []string{access, restricted, refresh} {
+			jti := TokenID(token)
+			assert.NotEmpty(t, jti)
+			assert.False(t, seen[jti], "jti repetido: %s", jti)
+			seen[jti] = true
+		}
+	}
+
+	token, _ := jwtService.GenerateToken(user)
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.Empty(t, TokenID("malformado"))
+}
+
+func TestBuildClaims_MatchesGenerateToken(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 2, "test-refresh", 1)
+	user := &domain.User{ID: "123", Email: "test@example.com", Roles: []string{"user", "admin"}}
+
+	token, err := jwtService.GenerateToken(user)
+	assert.NoError(t, err)
+	generated, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	built := buildClaims(user.ID, user.Email, user.Roles, 2*time.Hour)
+
+	assert.Equal(t, generated.UserID, built.UserID)
+	assert.Equal(t, generated.Email, built.Email)
+	assert.Equal(t, generated.Roles, built.Roles)
+	assert.Equal(t, generated.Subject, built.Subject)
+	assert.False(t, built.PasswordChangeRequired)
+	assert.NotEmpty(t, built.ID)
+	assert.NotEqual(t, generated.ID, built.ID)
+	assert.WithinDuration(t, generated.ExpiresAt.Time, built.ExpiresAt.Time, 2*time.Second)
+	assert.WithinDuration(t, generated.IssuedAt.Time, built.IssuedAt.Time, 2*time.Second)
+	assert.Equal(t, built.IssuedAt, built.NotBefore)
+}
+
+func TestJWTService_RejectsUnexpectedSigningMethod(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	claims := buildClaims("123", "test@example.com", []string{"admin"}, time.Hour)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaSigned, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(rsaKey)
+	assert.NoError(t, err)
+
+	for _, token := range []string{unsigned, rsaSigned} {
+		_, err = jwtService.ValidateToken(token)
+		assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+		_, err = jwtService.ValidateRefreshToken(token)
+		assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+	}
+}
+
+func TestJWTService_EnforcesTokenType(t *testing.T) {
+	// Chaves iguais por erro de configuração não permitem trocar os tipos de token
+	jwtService := NewJWTService("mesma-chave", 1, "mesma-chave", 1)
+	user := &domain.User{ID: "123", Email: "test@example.com"}
+	access, err := jwtService.GenerateToken(user)
+	assert.NoError(t, err)
+	refresh, err := jwtService.GenerateRefreshToken(user.ID)
+	assert.NoError(t, err)
+
+	_, err = jwtService.ValidateToken(refresh)
+	assert.ErrorIs(t, err, ErrUnexpectedTokenType)
+	_, err = jwtService.ValidateRefreshToken(access)
+	assert.ErrorIs(t, err, ErrUnexpectedTokenType)
+
+	claims, err := jwtService.ValidateToken(access)
+	assert.NoError(t, err)
+	assert.Equal(t, TokenTypeAccess, claims.Type)
+	_, err = jwtService.ValidateRefreshToken(refresh)
+	assert.NoError(t, err)
+
+	// Com a verificação desligada, apenas a assinatura é conferida
+	lenient := NewJWTService("mesma-chave", 1, "mesma-chave", 1, WithTokenTypeEnforcement(false))
+	_, err = lenient.ValidateToken(refresh)
+	assert.NoError(t, err)
+}
+
+func TestJWTServiceRSA_VerifyWithPublicKeyOnly(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signer := NewJWTServiceRSA(key, &key.PublicKey, 1, "test-refresh", 1)
+	verifier := NewJWTServiceRSA(nil, &key.PublicKey, 1, "test-refresh", 1)
+	user := &domain.User{ID: "123", Email: "test@example.com", Roles: []string{"user"}}
+
+	token, err := signer.GenerateToken(user)
+	assert.NoError(t, err)
+
+	// A instância sem chave privada valida o token, mas não emite novos
+	claims, err := verifier.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	_, err = verifier.GenerateToken(user)
+	assert.ErrorIs(t, err, ErrSigningKeyUnavailable)
+
+	// O kid do cabeçalho corresponde à chave publicada no JWKS
+	assert.Equal(t, "RS256", signer.SigningAlgorithm())
+	jwks := verifier.PublicJWKs()
+	assert.Len(t, jwks, 1)
+	header, _, err := jwt.NewParser().ParseUnverified(token, &TokenClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, jwks[0].Kid, header.Header["kid"])
+
+	// Tokens HMAC não são aceitos por um serviço RS256, e vice-versa
+	hmacToken, err := NewJWTService("test-secret", 1, "test-refresh", 1).GenerateToken(user)
+	assert.NoError(t, err)
+	_, err = verifier.ValidateToken(hmacToken)
+	assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+	_, err = NewJWTService("test-secret", 1, "test-refresh", 1).ValidateToken(token)
+	assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+
+	// Refresh tokens continuam em HMAC
+	refresh, err := signer.GenerateRefreshToken(user.ID)
+	assert.NoError(t, err)
+	_, err = verifier.ValidateRefreshToken(refresh)
+	assert.NoError(t, err)
+}
+
+func TestLoadRSAPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	assert.NoError(t, os.WriteFile(path, pemBytes, 0o600))
+
+	loaded, err := LoadRSAPrivateKey(path)
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	_, err = LoadRSAPrivateKey(filepath.Join(t.TempDir(), "inexistente.pem"))
+	assert.Error(t, err)
+}
+
+func TestJWTService_IssuerAndAudience(t *testing.T) {
+	user := &domain.User{ID: "123", Email: "test@example.com"}
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithIssuer("https://auth.example.com"), WithAudience("api"))
+
+	token, err := jwtService.GenerateToken(user)
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"api"}, claims.Audience)
+
+	// Mesma chave, mas emissor ou audiência diferentes
+	otherIssuer := NewJWTService("test-secret", 1, "test-refresh", 1, WithIssuer("https://outro.example.com"), WithAudience("api"))
+	_, err = otherIssuer.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+	otherAudience := NewJWTService("test-secret", 1, "test-refresh", 1, WithIssuer("https://auth.example.com"), WithAudience("billing"))
+	_, err = otherAudience.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	// Tokens sem iss/aud não passam por um serviço que os exige
+	plain, err := NewJWTService("test-secret", 1, "test-refresh", 1).GenerateToken(user)
+	assert.NoError(t, err)
+	_, err = jwtService.ValidateToken(plain)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownRefreshKey indica um refresh token cujo kid não corresponde à chave
+// atual nem a nenhuma das anteriores configuradas
+var ErrUnknownRefreshKey = errors.New("chave do refresh token desconhecida")
+
+// WithPreviousRefreshKeys aceita, apenas na validação, refresh tokens assinados
+// com chaves anteriores. Na rotação a chave antiga entra aqui e a nova passa a
+// assinar; após a validade dos refresh tokens (RefreshTTL) ela pode ser removida.
+func WithPreviousRefreshKeys(keys ...string) JWTOption {
+	return func(s *JWTService) {
+		for _, key := range keys {
+			if key != "" {
+				s.previousRefreshKeys = append(s.previousRefreshKeys, key)
+			}
+		}
+	}
+}
+
+// hmacKeyID deriva um kid estável de uma chave HMAC sem expô-la
+func hmacKeyID(key string) string {
+	sum := sha256.Sum256([]byte("refresh-kid:" + key))
+	return base64.RawURLEncoding.EncodeToString(sum[:9])
+}
+
+// refreshKeyfunc escolhe a chave de verificação do refresh token pelo kid. Tokens
+// sem kid, emitidos antes da rotação por chave, são verificados contra todas as
+// chaves aceitas.
+func (s *JWTService) refreshKeyfunc() jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
+		}
+
+		keys := append([]string{s.refreshKey}, s.previousRefreshKeys...)
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			set := jwt.VerificationKeySet{}
+			for _, key := range keys {
+				set.Keys = append(set.Keys, []byte(key))
+			}
+			return set, nil
+		}
+		for _, key := range keys {
+			if hmacKeyID(key) == kid {
+				return []byte(key), nil
+			}
+		}
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownRefreshKey, kid)
+	}
+}
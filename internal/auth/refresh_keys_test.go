@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oldRefreshKey = "refresh-key-antiga-com-32-bytes-ou-mais"
+	newRefreshKey = "refresh-key-nova-com-32-bytes-ou-mais!!"
+)
+
+// tokenKeyID lê o kid do cabeçalho de um token sem verificar a assinatura
+func tokenKeyID(t *testing.T, tokenString string) string {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &RefreshClaims{})
+	assert.NoError(t, err)
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestRefreshKeyRotation_PreviousKeyStillValidates(t *testing.T) {
+	before := NewJWTService("test-secret", 1, oldRefreshKey, 1)
+	oldToken, err := before.GenerateRefreshToken("u1")
+	assert.NoError(t, err)
+
+	// Após a rotação: a chave nova assina e a antiga apenas valida
+	after := NewJWTService("test-secret", 1, newRefreshKey, 1, WithPreviousRefreshKeys(oldRefreshKey))
+
+	claims, err := after.ValidateRefreshToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", claims.Subject)
+
+	newToken, err := after.GenerateRefreshToken("u2")
+	assert.NoError(t, err)
+	assert.Equal(t, hmacKeyID(newRefreshKey), tokenKeyID(t, newToken))
+	assert.NotEqual(t, tokenKeyID(t, oldToken), tokenKeyID(t, newToken))
+
+	// Tokens novos não validam em quem só conhece a chave antiga
+	_, err = before.ValidateRefreshToken(newToken)
+	assert.ErrorIs(t, err, ErrUnknownRefreshKey)
+}
+
+func TestRefreshKeyRotation_RemovedKeyRejected(t *testing.T) {
+	before := NewJWTService("test-secret", 1, oldRefreshKey, 1)
+	oldToken, _ := before.GenerateRefreshToken("u1")
+
+	after := NewJWTService("test-secret", 1, newRefreshKey, 1)
+	_, err := after.ValidateRefreshToken(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownRefreshKey)
+}
+
+func TestRefreshKeyRotation_TokenWithoutKidTriesAllKeys(t *testing.T) {
+	// Refresh token emitido antes do kid, assinado com a chave antiga
+	claims := &RefreshClaims{
+		Type: TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   "u1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(oldRefreshKey))
+	assert.NoError(t, err)
+
+	after := NewJWTService("test-secret", 1, newRefreshKey, 1, WithPreviousRefreshKeys(oldRefreshKey))
+	got, err := after.ValidateRefreshToken(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", got.Subject)
+
+	_, err = NewJWTService("test-secret", 1, newRefreshKey, 1).ValidateRefreshToken(legacy)
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// TokenTypePasswordReset é o valor da claim typ dos tokens de redefinição de senha
+const TokenTypePasswordReset = "password_reset"
+
+// DefaultPasswordResetTTL é a validade padrão de um token de redefinição de senha
+const DefaultPasswordResetTTL = 30 * time.Minute
+
+// PasswordResetClaims define as claims do token de redefinição de senha. O email
+// vincula o token ao endereço que o solicitou: se o email da conta mudar, o
+// token deixa de valer.
+type PasswordResetClaims struct {
+	Email string `json:"email"`
+	// Type identifica o propósito do token (TokenTypePasswordReset)
+	Type string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// WithPasswordReset define a chave e a validade dos tokens de redefinição de
+// senha. Chave vazia usa uma derivada da chave de refresh; ttl <= 0 usa
+// DefaultPasswordResetTTL.
+func WithPasswordReset(key string, ttl time.Duration) JWTOption {
+	return func(s *JWTService) {
+		s.resetKey = key
+		s.resetTTL = ttl
+	}
+}
+
+// PasswordResetTTL retorna a validade dos tokens de redefinição de senha
+func (s *JWTService) PasswordResetTTL() time.Duration {
+	if s.resetTTL <= 0 {
+		return DefaultPasswordResetTTL
+	}
+	return s.resetTTL
+}
+
+// resetSigningKey retorna a chave dos tokens de redefinição. Sem chave própria,
+// deriva uma da chave de refresh, para que um refresh token nunca tenha uma
+// assinatura válida como token de redefinição.
+func (s *JWTService) resetSigningKey() string {
+	if s.resetKey != "" {
+		return s.resetKey
+	}
+	return s.derivedKey(TokenTypePasswordReset)
+}
+
+// derivedKey deriva da chave de refresh uma chave exclusiva para o propósito informado
+func (s *JWTService) derivedKey(purpose string) string {
+	mac := hmac.New(sha256.New, []byte(s.refreshKey))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IssuePasswordResetToken gera um token de redefinição de senha de uso único
+// (identificado pelo jti) para o usuário
+func (s *JWTService) IssuePasswordResetToken(user *domain.User) (string, *PasswordResetClaims, error) {
+	now := time.Now()
+	claims := &PasswordResetClaims{
+		Email: user.Email,
+		Type:  TokenTypePasswordReset,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.PasswordResetTTL())),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.resetSigningKey()))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ValidatePasswordResetToken valida a assinatura, a expiração e o propósito do
+// token de redefinição. O uso único é garantido por quem consome o token.
+func (s *JWTService) ValidatePasswordResetToken(tokenString string) (*PasswordResetClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PasswordResetClaims{}, hmacKeyfunc(s.resetSigningKey()))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*PasswordResetClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token de redefinição inválido")
+	}
+	// Sempre exigida: não há tokens de redefinição antigos sem a claim typ
+	if claims.Type != TokenTypePasswordReset {
+		return nil, ErrUnexpectedTokenType
+	}
+	if claims.ID == "" || claims.Subject == "" {
+		return nil, errors.New("token de redefinição sem jti ou sub")
+	}
+	return claims, nil
+}
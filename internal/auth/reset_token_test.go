@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTService_PasswordResetTokenRoundTrip(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithPasswordReset("", 10*time.Minute))
+	user := &domain.User{ID: "123", Email: "test@example.com"}
+
+	token, issued, err := jwtService.IssuePasswordResetToken(user)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, issued.ID)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), issued.ExpiresAt.Time, 5*time.Second)
+
+	claims, err := jwtService.ValidatePasswordResetToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.Subject)
+	assert.Equal(t, "test@example.com", claims.Email)
+	assert.Equal(t, TokenTypePasswordReset, claims.Type)
+	assert.Equal(t, issued.ID, claims.ID)
+
+	// Nenhum outro tipo de token aceita o token de redefinição
+	_, err = jwtService.ValidateRefreshToken(token)
+	assert.Error(t, err)
+	_, err = jwtService.ValidateToken(token)
+	assert.Error(t, err)
+}
+
+func TestJWTService_PasswordResetTokenRejected(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithPasswordReset("reset-key", 0))
+	assert.Equal(t, DefaultPasswordResetTTL, jwtService.PasswordResetTTL())
+
+	sign := func(claims PasswordResetClaims, key string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		assert.NoError(t, err)
+		return token
+	}
+	valid := jwt.RegisteredClaims{ID: "jti", Subject: "123", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+	expired := jwt.RegisteredClaims{ID: "jti", Subject: "123", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}
+
+	_, err := jwtService.ValidatePasswordResetToken(sign(PasswordResetClaims{Type: TokenTypePasswordReset, RegisteredClaims: expired}, "reset-key"))
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	_, err = jwtService.ValidatePasswordResetToken(sign(PasswordResetClaims{Type: TokenTypeRefresh, RegisteredClaims: valid}, "reset-key"))
+	assert.ErrorIs(t, err, ErrUnexpectedTokenType)
+
+	_, err = jwtService.ValidatePasswordResetToken(sign(PasswordResetClaims{Type: TokenTypePasswordReset, RegisteredClaims: valid}, "test-refresh"))
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+
+	// Um refresh token legítimo não serve como token de redefinição
+	refresh, err := jwtService.GenerateRefreshToken("123")
+	assert.NoError(t, err)
+	_, err = jwtService.ValidatePasswordResetToken(refresh)
+	assert.Error(t, err)
+}
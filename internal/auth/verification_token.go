@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// TokenTypeEmailVerification é o valor da claim typ dos tokens de verificação de email
+const TokenTypeEmailVerification = "email_verification"
+
+// DefaultEmailVerificationTTL é a validade padrão de um token de verificação de email
+const DefaultEmailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationClaims define as claims do token de verificação de email. O
+// email vincula o token ao endereço verificado: se o email da conta mudar, o
+// token deixa de valer.
+type EmailVerificationClaims struct {
+	Email string `json:"email"`
+	// Type identifica o propósito do token (TokenTypeEmailVerification)
+	Type string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// WithEmailVerificationTTL define a validade dos tokens de verificação de email;
+// ttl <= 0 usa DefaultEmailVerificationTTL
+func WithEmailVerificationTTL(ttl time.Duration) JWTOption {
+	return func(s *JWTService) {
+		s.verificationTTL = ttl
+	}
+}
+
+// EmailVerificationTTL retorna a validade dos tokens de verificação de email
+func (s *JWTService) EmailVerificationTTL() time.Duration {
+	if s.verificationTTL <= 0 {
+		return DefaultEmailVerificationTTL
+	}
+	return s.verificationTTL
+}
+
+// IssueEmailVerificationToken gera um token de verificação de email de uso
+// único (identificado pelo jti) para o usuário. A chave é derivada da de
+// refresh, exclusiva para este propósito.
+func (s *JWTService) IssueEmailVerificationToken(user *domain.User) (string, *EmailVerificationClaims, error) {
+	now := time.Now()
+	claims := &EmailVerificationClaims{
+		Email: user.Email,
+		Type:  TokenTypeEmailVerification,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.EmailVerificationTTL())),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.derivedKey(TokenTypeEmailVerification)))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ValidateEmailVerificationToken valida a assinatura, a expiração e o propósito
+// do token de verificação. O uso único é garantido por quem consome o token.
+func (s *JWTService) ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, hmacKeyfunc(s.derivedKey(TokenTypeEmailVerification)))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*EmailVerificationClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token de verificação de email inválido")
+	}
+	if claims.Type != TokenTypeEmailVerification {
+		return nil, ErrUnexpectedTokenType
+	}
+	if claims.ID == "" || claims.Subject == "" {
+		return nil, errors.New("token de verificação de email sem jti ou sub")
+	}
+	return claims, nil
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTService_EmailVerificationTokenRoundTrip(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1, WithEmailVerificationTTL(time.Hour))
+	user := &domain.User{ID: "123", Email: "a@b.com"}
+
+	token, issued, err := jwtService.IssueEmailVerificationToken(user)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, issued.ID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), issued.ExpiresAt.Time, 5*time.Second)
+
+	claims, err := jwtService.ValidateEmailVerificationToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", claims.Subject)
+	assert.Equal(t, "a@b.com", claims.Email)
+	assert.Equal(t, TokenTypeEmailVerification, claims.Type)
+
+	// Tokens de outros propósitos não verificam o email, e vice-versa
+	reset, _, _ := jwtService.IssuePasswordResetToken(user)
+	_, err = jwtService.ValidateEmailVerificationToken(reset)
+	assert.Error(t, err)
+	_, err = jwtService.ValidatePasswordResetToken(token)
+	assert.Error(t, err)
+	_, err = jwtService.ValidateRefreshToken(token)
+	assert.Error(t, err)
+
+	assert.Equal(t, DefaultEmailVerificationTTL, NewJWTService("s", 1, "r", 1).EmailVerificationTTL())
+}
+
+func TestJWTService_EmailVerificationTokenExpired(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 1, "test-refresh", 1)
+	claims := EmailVerificationClaims{
+		Type: TokenTypeEmailVerification,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: "jti", Subject: "123", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtService.derivedKey(TokenTypeEmailVerification)))
+	assert.NoError(t, err)
+
+	_, err = jwtService.ValidateEmailVerificationToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
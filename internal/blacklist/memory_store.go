@@ -0,0 +1,63 @@
+package blacklist
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+)
+
+// MemoryStore é uma implementação em memória de domain.TokenBlacklist.
+// Adequada para uma única instância da aplicação; revogações se perdem ao
+// reiniciar e não são compartilhadas entre instâncias.
+type MemoryStore struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	revoked map[string]time.Time
+}
+
+// Garantir que MemoryStore implementa domain.TokenBlacklist e domain.TokenPurger
+var (
+	_ domain.TokenBlacklist = (*MemoryStore)(nil)
+	_ domain.TokenPurger    = (*MemoryStore)(nil)
+)
+
+// NewMemoryStore cria uma nova blacklist em memória
+func NewMemoryStore(c clock.Clock) *MemoryStore {
+	if c == nil {
+		c = clock.System()
+	}
+	return &MemoryStore{clock: c, revoked: make(map[string]time.Time)}
+}
+
+// Add registra o jti como revogado pelos próximos ttl
+func (s *MemoryStore) Add(jti string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = s.clock.Now().Add(ttl)
+	return nil
+}
+
+// Contains indica se o jti foi revogado e a revogação ainda não expirou
+func (s *MemoryStore) Contains(jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.revoked[jti]
+	return ok && s.clock.Now().Before(expiresAt), nil
+}
+
+// PurgeExpired remove as revogações de tokens já expirados
+func (s *MemoryStore) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	purged := 0
+	for jti, expiresAt := range s.revoked {
+		if !now.Before(expiresAt) {
+			delete(s.revoked, jti)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,40 @@
+package blacklist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_AddUntilTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(clock.Func(func() time.Time { return now }))
+	assert.NoError(t, store.Add("jti-1", time.Hour))
+
+	revoked, err := store.Contains("jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, _ = store.Contains("jti-2")
+	assert.False(t, revoked)
+
+	// Após o TTL, a revogação deixa de importar
+	now = now.Add(time.Hour)
+	revoked, _ = store.Contains("jti-1")
+	assert.False(t, revoked)
+}
+
+func TestMemoryStore_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(clock.Func(func() time.Time { return now }))
+	_ = store.Add("velho", time.Second)
+	_ = store.Add("novo", time.Hour)
+
+	now = now.Add(time.Minute)
+	purged, err := store.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	revoked, _ := store.Contains("novo")
+	assert.True(t, revoked)
+}
@@ -0,0 +1,62 @@
+package blacklist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	t.Cleanup(func() { _ = store.Close() })
+	return store, mr
+}
+
+func TestRedisStore_AddUntilTTL(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	require.NoError(t, store.Add("jti-1", time.Hour))
+
+	revoked, err := store.Contains("jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Equal(t, time.Hour, mr.TTL(DefaultRedisKeyPrefix+"jti-1"))
+	revoked, _ = store.Contains("jti-2")
+	assert.False(t, revoked)
+
+	// A chave expira junto com o token
+	mr.FastForward(time.Hour)
+	revoked, _ = store.Contains("jti-1")
+	assert.False(t, revoked)
+}
+
+func TestRedisStore_SkipsExpiredTokens(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	require.NoError(t, store.Add("jti-1", -time.Second))
+	assert.False(t, mr.Exists(DefaultRedisKeyPrefix+"jti-1"))
+}
+
+func TestRedisStore_SharedBetweenInstances(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	require.NoError(t, store.Add("jti-1", time.Hour))
+
+	// Outra instância conectada ao mesmo Redis enxerga a revogação
+	other, err := NewRedisStoreFromURL("redis://" + mr.Addr())
+	require.NoError(t, err)
+	defer other.Close()
+	revoked, err := other.Contains("jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRedisStore_ConnectionError(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	mr.Close()
+	_, err := store.Contains("jti-1")
+	assert.Error(t, err)
+	assert.Error(t, store.Add("jti-1", time.Hour))
+}
@@ -1,9 +1,16 @@
 package config
 
 import (
+	"errors"
+	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/pkg/hashing"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -360,3 +367,919 @@ func TestGetEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadCORSConfig(t *testing.T) {
+	os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	os.Unsetenv("CORS_MAX_AGE")
+	os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+
+	config := loadCORSConfig()
+
+	if config.MaxAge != 600*time.Second {
+		t.Errorf("MaxAge padrão esperado 600s, mas foi %v", config.MaxAge)
+	}
+
+	if len(config.AllowedOrigins) != 0 {
+		t.Errorf("AllowedOrigins deveria ser vazio por padrão, mas foi %v", config.AllowedOrigins)
+	}
+
+	if config.AllowCredentials {
+		t.Error("AllowCredentials deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://a.com, https://b.com")
+	os.Setenv("CORS_MAX_AGE", "3600")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	defer os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	defer os.Unsetenv("CORS_MAX_AGE")
+	defer os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+
+	config = loadCORSConfig()
+
+	if !config.AllowCredentials {
+		t.Error("AllowCredentials deveria estar habilitado com CORS_ALLOW_CREDENTIALS=true")
+	}
+
+	if config.MaxAge != time.Hour {
+		t.Errorf("MaxAge esperado 1h, mas foi %v", config.MaxAge)
+	}
+
+	if len(config.AllowedOrigins) != 2 || config.AllowedOrigins[1] != "https://b.com" {
+		t.Errorf("AllowedOrigins esperado [https://a.com https://b.com], mas foi %v", config.AllowedOrigins)
+	}
+}
+
+func TestLoadArgon2Config(t *testing.T) {
+	os.Unsetenv("ARGON2_MEMORY_KB")
+	os.Unsetenv("ARGON2_ITERATIONS")
+	os.Unsetenv("ARGON2_PARALLELISM")
+
+	config := loadArgon2Config()
+
+	if config.MemoryKB != 65536 || config.Iterations != 3 || config.Parallelism != 2 {
+		t.Errorf("Valores padrão inesperados: %+v", config)
+	}
+
+	os.Setenv("ARGON2_MEMORY_KB", "32768")
+	os.Setenv("ARGON2_ITERATIONS", "4")
+	os.Setenv("ARGON2_PARALLELISM", "-1")
+	defer os.Unsetenv("ARGON2_MEMORY_KB")
+	defer os.Unsetenv("ARGON2_ITERATIONS")
+	defer os.Unsetenv("ARGON2_PARALLELISM")
+
+	config = loadArgon2Config()
+
+	if config.MemoryKB != 32768 || config.Iterations != 4 {
+		t.Errorf("Valores customizados inesperados: %+v", config)
+	}
+
+	if config.Parallelism != 0 {
+		t.Errorf("Paralelismo negativo deveria virar 0 (rejeitado pelo hasher), mas foi %d", config.Parallelism)
+	}
+
+	// Valores acima do tipo ficam no máximo em vez de dar a volta
+	os.Setenv("ARGON2_MEMORY_KB", "4294967297")
+	os.Setenv("ARGON2_ITERATIONS", "4294967299")
+	config = loadArgon2Config()
+	if config.MemoryKB != math.MaxUint32 || config.Iterations != math.MaxUint32 {
+		t.Errorf("Valores acima de uint32 deveriam ficar no máximo, mas foram %+v", config)
+	}
+}
+
+func TestArgon2Config_Params(t *testing.T) {
+	params := Argon2Config{MemoryKB: 16384, Iterations: 2, Parallelism: 1}.Params()
+
+	if params.Memory != 16384 || params.Iterations != 2 || params.Parallelism != 1 {
+		t.Errorf("Parâmetros não refletem a configuração: %+v", params)
+	}
+
+	if _, err := hashing.NewArgon2Hasher(params); err != nil {
+		t.Errorf("Parâmetros válidos não deveriam ser rejeitados: %v", err)
+	}
+
+	if _, err := hashing.NewArgon2Hasher(Argon2Config{MemoryKB: 1, Iterations: 2, Parallelism: 1}.Params()); err == nil {
+		t.Error("Memória absurda deveria ser rejeitada pelo hasher")
+	}
+}
+
+func TestLoadDebugConfig(t *testing.T) {
+	os.Unsetenv("DEBUG_EXPOSE_USER_HEADER")
+
+	if !loadDebugConfig(AppConfig{Environment: "development"}).ExposeUserHeader {
+		t.Error("ExposeUserHeader deveria estar habilitado por padrão fora de produção")
+	}
+
+	if loadDebugConfig(AppConfig{Environment: "production"}).ExposeUserHeader {
+		t.Error("ExposeUserHeader deveria estar desabilitado por padrão em produção")
+	}
+
+	os.Setenv("DEBUG_EXPOSE_USER_HEADER", "true")
+	defer os.Unsetenv("DEBUG_EXPOSE_USER_HEADER")
+
+	if !loadDebugConfig(AppConfig{Environment: "production"}).ExposeUserHeader {
+		t.Error("ExposeUserHeader deveria respeitar a configuração explícita")
+	}
+}
+
+func TestAppConfig_IsProduction(t *testing.T) {
+	os.Unsetenv("APP_ENV")
+
+	if loadAppConfig().IsProduction() {
+		t.Error("Ambiente padrão não deveria ser produção")
+	}
+
+	if !(AppConfig{Environment: "production"}).IsProduction() {
+		t.Error("Ambiente 'production' deveria ser produção")
+	}
+}
+
+func TestLoadAppConfig_Maintenance(t *testing.T) {
+	os.Unsetenv("MAINTENANCE_MODE")
+	if loadAppConfig().Maintenance {
+		t.Error("Maintenance deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("MAINTENANCE_MODE", "true")
+	defer os.Unsetenv("MAINTENANCE_MODE")
+	if !loadAppConfig().Maintenance {
+		t.Error("Maintenance deveria estar habilitado com MAINTENANCE_MODE=true")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	strong := strings.Repeat("s", auth.MinSecretLength)
+
+	dev := &Config{App: AppConfig{Environment: "development"}, JWT: JWTConfig{Secret: "curta", RefreshSecret: "curta"}}
+	if err := dev.Validate(); err != nil {
+		t.Errorf("Fora de produção chaves curtas só geram aviso, mas retornou %v", err)
+	}
+
+	prod := &Config{App: AppConfig{Environment: "production"}, JWT: JWTConfig{Secret: "curta", RefreshSecret: strong}}
+	if err := prod.Validate(); !errors.Is(err, auth.ErrWeakSecret) {
+		t.Errorf("Em produção JWT_SECRET curto deveria ser rejeitado, mas foi %v", err)
+	}
+
+	prod.JWT = JWTConfig{Secret: strong, RefreshSecret: "curta"}
+	if err := prod.Validate(); !errors.Is(err, auth.ErrWeakSecret) {
+		t.Errorf("Em produção JWT_REFRESH_SECRET curto deveria ser rejeitado, mas foi %v", err)
+	}
+
+	prod.JWT = JWTConfig{Secret: strong, RefreshSecret: strong}
+	if err := prod.Validate(); err != nil {
+		t.Errorf("Chaves fortes não deveriam ser rejeitadas: %v", err)
+	}
+
+	prod.Signing = SigningConfig{Secret: "curta"}
+	if err := prod.Validate(); !errors.Is(err, auth.ErrWeakSecret) {
+		t.Errorf("Em produção REQUEST_SIGNING_SECRET curto deveria ser rejeitado, mas foi %v", err)
+	}
+
+	prod.Signing = SigningConfig{}
+	prod.CORS = CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	if err := prod.Validate(); err == nil {
+		t.Error("Em produção CORS com \"*\" e credenciais deveria ser rejeitado")
+	}
+}
+
+func TestLoadSigningConfig(t *testing.T) {
+	os.Unsetenv("REQUEST_SIGNING_SECRET")
+	if loadSigningConfig().Secret != "" {
+		t.Error("Secret deveria estar vazio por padrão")
+	}
+
+	os.Setenv("REQUEST_SIGNING_SECRET", "segredo")
+	defer os.Unsetenv("REQUEST_SIGNING_SECRET")
+	if got := loadSigningConfig().Secret; got != "segredo" {
+		t.Errorf("Secret esperado segredo, mas foi %q", got)
+	}
+}
+
+func TestLoadPasswordResetConfig(t *testing.T) {
+	os.Setenv("PASSWORD_RESET_ALLOWED_REDIRECTS", "https://app.example.com/reset,https://admin.example.com/reset")
+	defer os.Unsetenv("PASSWORD_RESET_ALLOWED_REDIRECTS")
+
+	config := loadPasswordResetConfig()
+
+	if len(config.AllowedRedirectURIs) != 2 || config.AllowedRedirectURIs[0] != "https://app.example.com/reset" {
+		t.Errorf("AllowedRedirectURIs inesperado: %v", config.AllowedRedirectURIs)
+	}
+	if config.TokenTTL != 30*time.Minute {
+		t.Errorf("TokenTTL padrão esperado 30m, mas foi %v", config.TokenTTL)
+	}
+
+	os.Setenv("PASSWORD_RESET_TOKEN_TTL", "900")
+	defer os.Unsetenv("PASSWORD_RESET_TOKEN_TTL")
+	if got := loadPasswordResetConfig().TokenTTL; got != 15*time.Minute {
+		t.Errorf("TokenTTL esperado 15m, mas foi %v", got)
+	}
+}
+
+func TestLoadSessionConfig(t *testing.T) {
+	if got := loadSessionConfig().MaxPerUser; got != 5 {
+		t.Errorf("MaxPerUser padrão esperado 5, mas foi %d", got)
+	}
+
+	os.Setenv("SESSION_MAX_PER_USER", "2")
+	defer os.Unsetenv("SESSION_MAX_PER_USER")
+	if got := loadSessionConfig().MaxPerUser; got != 2 {
+		t.Errorf("MaxPerUser esperado 2, mas foi %d", got)
+	}
+
+	if loadSessionConfig().RevokeOnPasswordChange {
+		t.Error("RevokeOnPasswordChange deveria ser false por padrão")
+	}
+	os.Setenv("SESSION_REVOKE_ON_PASSWORD_CHANGE", "true")
+	defer os.Unsetenv("SESSION_REVOKE_ON_PASSWORD_CHANGE")
+	if !loadSessionConfig().RevokeOnPasswordChange {
+		t.Error("RevokeOnPasswordChange esperado true")
+	}
+
+	if loadSessionConfig().Enabled {
+		t.Error("Enabled deveria ser false por padrão")
+	}
+	os.Setenv("SESSION_ENABLED", "true")
+	defer os.Unsetenv("SESSION_ENABLED")
+	if !loadSessionConfig().Enabled {
+		t.Error("Enabled esperado true")
+	}
+}
+
+func TestLoadServerConfig_MaxInFlight(t *testing.T) {
+	if got := loadServerConfig().MaxInFlight; got != 0 {
+		t.Errorf("MaxInFlight padrão esperado 0, mas foi %d", got)
+	}
+
+	os.Setenv("SERVER_MAX_IN_FLIGHT", "50")
+	defer os.Unsetenv("SERVER_MAX_IN_FLIGHT")
+	if got := loadServerConfig().MaxInFlight; got != 50 {
+		t.Errorf("MaxInFlight esperado 50, mas foi %d", got)
+	}
+}
+
+func TestLoadServerConfig_ShutdownTimeout(t *testing.T) {
+	os.Unsetenv("SERVER_SHUTDOWN_TIMEOUT")
+	if got := loadServerConfig().ShutdownTimeout; got != 15*time.Second {
+		t.Errorf("ShutdownTimeout padrão esperado 15s, mas foi %v", got)
+	}
+
+	defer os.Unsetenv("SERVER_SHUTDOWN_TIMEOUT")
+	os.Setenv("SERVER_SHUTDOWN_TIMEOUT", "30")
+	if got := loadServerConfig().ShutdownTimeout; got != 30*time.Second {
+		t.Errorf("ShutdownTimeout esperado 30s, mas foi %v", got)
+	}
+
+	// Valores não positivos voltam ao padrão
+	os.Setenv("SERVER_SHUTDOWN_TIMEOUT", "0")
+	if got := loadServerConfig().ShutdownTimeout; got != 15*time.Second {
+		t.Errorf("ShutdownTimeout esperado 15s para 0, mas foi %v", got)
+	}
+}
+
+func TestLoadServerConfig_TrustedProxies(t *testing.T) {
+	os.Unsetenv("TRUSTED_PROXIES")
+	if got := loadServerConfig().TrustedProxies; len(got) != 0 {
+		t.Errorf("TrustedProxies deveria estar vazio por padrão, mas foi %v", got)
+	}
+
+	defer os.Unsetenv("TRUSTED_PROXIES")
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.1, 172.16.0.0/12")
+	got := loadServerConfig().TrustedProxies
+	if len(got) != 2 || got[0] != "10.0.0.1" || got[1] != "172.16.0.0/12" {
+		t.Errorf("TrustedProxies esperado [10.0.0.1 172.16.0.0/12], mas foi %v", got)
+	}
+}
+
+func TestConfig_Validate_TrustedProxies(t *testing.T) {
+	cfg := &Config{Server: ServerConfig{TrustedProxies: []string{"10.0.0.1", "172.16.0.0/12", "::1"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("IPs e CIDRs válidos deveriam ser aceitos: %v", err)
+	}
+
+	cfg.Server.TrustedProxies = []string{"proxy.interno"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("TRUSTED_PROXIES com nome de host deveria ser rejeitado")
+	}
+}
+
+func TestLoadCookieConfig(t *testing.T) {
+	if loadCookieConfig(AppConfig{Environment: "development"}).ForceSecure {
+		t.Error("ForceSecure deveria ser falso em desenvolvimento por padrão")
+	}
+	if !loadCookieConfig(AppConfig{Environment: "production"}).ForceSecure {
+		t.Error("ForceSecure deveria ser verdadeiro em produção por padrão")
+	}
+
+	os.Setenv("COOKIE_FORCE_SECURE", "true")
+	defer os.Unsetenv("COOKIE_FORCE_SECURE")
+	if !loadCookieConfig(AppConfig{Environment: "development"}).ForceSecure {
+		t.Error("ForceSecure deveria respeitar a configuração explícita")
+	}
+}
+
+func TestLoadCookieConfig_RefreshToken(t *testing.T) {
+	os.Unsetenv("COOKIE_REFRESH_TOKEN")
+	if loadCookieConfig(AppConfig{}).RefreshToken {
+		t.Error("RefreshToken deveria ser falso por padrão")
+	}
+
+	os.Setenv("COOKIE_REFRESH_TOKEN", "true")
+	defer os.Unsetenv("COOKIE_REFRESH_TOKEN")
+	if !loadCookieConfig(AppConfig{}).RefreshToken {
+		t.Error("RefreshToken deveria respeitar COOKIE_REFRESH_TOKEN")
+	}
+}
+
+func TestLoadRegistrationConfig(t *testing.T) {
+	os.Unsetenv("REGISTRATION_RESERVED_LOCAL_PARTS")
+	if got := loadRegistrationConfig().ReservedLocalParts; len(got) == 0 || got[0] != "admin" {
+		t.Errorf("ReservedLocalParts padrão deveria começar por admin, mas foi %v", got)
+	}
+
+	os.Setenv("REGISTRATION_RESERVED_LOCAL_PARTS", "suporte, vendas")
+	defer os.Unsetenv("REGISTRATION_RESERVED_LOCAL_PARTS")
+	got := loadRegistrationConfig().ReservedLocalParts
+	if len(got) != 2 || got[0] != "suporte" || got[1] != "vendas" {
+		t.Errorf("ReservedLocalParts esperado [suporte vendas], mas foi %v", got)
+	}
+}
+
+func TestLoadRegistrationConfig_EmailVerification(t *testing.T) {
+	os.Unsetenv("REQUIRE_EMAIL_VERIFICATION")
+	os.Unsetenv("EMAIL_VERIFICATION_TOKEN_TTL")
+	cfg := loadRegistrationConfig()
+	if cfg.RequireEmailVerification {
+		t.Error("RequireEmailVerification deveria estar desabilitado por padrão")
+	}
+	if cfg.VerificationTokenTTL != 24*time.Hour {
+		t.Errorf("VerificationTokenTTL padrão esperado 24h, mas foi %v", cfg.VerificationTokenTTL)
+	}
+
+	os.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+	os.Setenv("EMAIL_VERIFICATION_TOKEN_TTL", "3600")
+	defer os.Unsetenv("REQUIRE_EMAIL_VERIFICATION")
+	defer os.Unsetenv("EMAIL_VERIFICATION_TOKEN_TTL")
+	cfg = loadRegistrationConfig()
+	if !cfg.RequireEmailVerification || cfg.VerificationTokenTTL != time.Hour {
+		t.Errorf("Configuração explícita esperada true/1h, obtida %v/%v", cfg.RequireEmailVerification, cfg.VerificationTokenTTL)
+	}
+}
+
+func TestLoadRegistrationConfig_PasswordConfirmation(t *testing.T) {
+	os.Unsetenv("REGISTRATION_REQUIRE_PASSWORD_CONFIRMATION")
+	if loadRegistrationConfig().RequirePasswordConfirmation {
+		t.Error("RequirePasswordConfirmation deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("REGISTRATION_REQUIRE_PASSWORD_CONFIRMATION", "true")
+	defer os.Unsetenv("REGISTRATION_REQUIRE_PASSWORD_CONFIRMATION")
+	if !loadRegistrationConfig().RequirePasswordConfirmation {
+		t.Error("RequirePasswordConfirmation deveria respeitar a configuração explícita")
+	}
+}
+
+func TestLoadRegistrationConfig_StrictEmail(t *testing.T) {
+	os.Unsetenv("EMAIL_STRICT_VALIDATION")
+	if loadRegistrationConfig().StrictEmail {
+		t.Error("StrictEmail deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("EMAIL_STRICT_VALIDATION", "true")
+	defer os.Unsetenv("EMAIL_STRICT_VALIDATION")
+	if !loadRegistrationConfig().StrictEmail {
+		t.Error("StrictEmail deveria estar habilitado com EMAIL_STRICT_VALIDATION=true")
+	}
+}
+
+func TestLoadRegistrationConfig_PasswordMinLength(t *testing.T) {
+	if got := loadRegistrationConfig().PasswordMinLength; got != 8 {
+		t.Errorf("PasswordMinLength padrão esperado 8, mas foi %d", got)
+	}
+
+	os.Setenv("PASSWORD_MIN_LENGTH", "12")
+	defer os.Unsetenv("PASSWORD_MIN_LENGTH")
+	if got := loadRegistrationConfig().PasswordMinLength; got != 12 {
+		t.Errorf("PasswordMinLength esperado 12, mas foi %d", got)
+	}
+}
+
+func TestLoadResponseConfig(t *testing.T) {
+	os.Unsetenv("RESPONSE_CAMEL_CASE_KEYS")
+	if loadResponseConfig().CamelCaseKeys {
+		t.Error("CamelCaseKeys deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("RESPONSE_CAMEL_CASE_KEYS", "true")
+	defer os.Unsetenv("RESPONSE_CAMEL_CASE_KEYS")
+	if !loadResponseConfig().CamelCaseKeys {
+		t.Error("CamelCaseKeys deveria respeitar a configuração explícita")
+	}
+
+	os.Unsetenv("RESPONSE_PROBLEM_JSON")
+	if loadResponseConfig().ProblemJSON {
+		t.Error("ProblemJSON deveria estar desabilitado por padrão")
+	}
+	os.Setenv("RESPONSE_PROBLEM_JSON", "true")
+	defer os.Unsetenv("RESPONSE_PROBLEM_JSON")
+	if !loadResponseConfig().ProblemJSON {
+		t.Error("ProblemJSON deveria respeitar a configuração explícita")
+	}
+}
+
+func TestLoadNotificationConfig(t *testing.T) {
+	os.Unsetenv("NOTIFY_EMAIL_CHANGE")
+	if !loadNotificationConfig().EmailChange {
+		t.Error("EmailChange deveria estar habilitado por padrão")
+	}
+
+	os.Setenv("NOTIFY_EMAIL_CHANGE", "false")
+	defer os.Unsetenv("NOTIFY_EMAIL_CHANGE")
+	if loadNotificationConfig().EmailChange {
+		t.Error("EmailChange deveria respeitar a configuração explícita")
+	}
+
+	os.Unsetenv("NOTIFY_WEBHOOK_URL")
+	if got := loadNotificationConfig().WebhookURL; got != "" {
+		t.Errorf("WebhookURL deveria estar vazia por padrão, mas foi %q", got)
+	}
+	os.Setenv("NOTIFY_WEBHOOK_URL", "https://mailer.internal/events")
+	defer os.Unsetenv("NOTIFY_WEBHOOK_URL")
+	if got := loadNotificationConfig().WebhookURL; got != "https://mailer.internal/events" {
+		t.Errorf("WebhookURL esperada https://mailer.internal/events, mas foi %q", got)
+	}
+}
+
+func TestLoadRevocationConfig(t *testing.T) {
+	os.Unsetenv("REVOKED_TOKEN_PURGE_INTERVAL")
+	if got := loadRevocationConfig().PurgeInterval; got != time.Hour {
+		t.Errorf("PurgeInterval padrão esperado 1h, mas foi %v", got)
+	}
+
+	os.Setenv("REVOKED_TOKEN_PURGE_INTERVAL", "0")
+	defer os.Unsetenv("REVOKED_TOKEN_PURGE_INTERVAL")
+	if got := loadRevocationConfig().PurgeInterval; got != 0 {
+		t.Errorf("PurgeInterval esperado 0, mas foi %v", got)
+	}
+}
+
+func TestLoadRevocationConfig_Backend(t *testing.T) {
+	os.Unsetenv("REVOKED_TOKEN_BACKEND")
+	if got := loadRevocationConfig().Backend; got != RevocationBackendDatabase {
+		t.Errorf("Backend padrão esperado database, mas foi %q", got)
+	}
+
+	os.Setenv("REVOKED_TOKEN_BACKEND", "Redis")
+	os.Setenv("REDIS_URL", "redis://localhost:6379/0")
+	defer os.Unsetenv("REVOKED_TOKEN_BACKEND")
+	defer os.Unsetenv("REDIS_URL")
+	cfg := loadRevocationConfig()
+	if cfg.Backend != RevocationBackendRedis || cfg.RedisURL != "redis://localhost:6379/0" {
+		t.Errorf("Configuração explícita esperada redis, obtida %q / %q", cfg.Backend, cfg.RedisURL)
+	}
+}
+
+func TestValidate_RevocationBackend(t *testing.T) {
+	cfg := &Config{Revoke: RevocationConfig{Backend: RevocationBackendRedis}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Backend redis sem REDIS_URL deveria ser rejeitado")
+	}
+	cfg.Revoke.RedisURL = "redis://localhost:6379/0"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Backend redis com REDIS_URL deveria ser aceito: %v", err)
+	}
+	cfg.Revoke.Backend = "cassandra"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Backend desconhecido deveria ser rejeitado")
+	}
+}
+
+func TestLoadLoginConfig(t *testing.T) {
+	os.Unsetenv("LOGIN_ALLOW_USERNAME")
+	if loadLoginConfig().AllowUsername {
+		t.Error("AllowUsername deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("LOGIN_ALLOW_USERNAME", "true")
+	defer os.Unsetenv("LOGIN_ALLOW_USERNAME")
+	if !loadLoginConfig().AllowUsername {
+		t.Error("AllowUsername deveria respeitar a configuração explícita")
+	}
+}
+
+func TestLoadLoginConfig_DeferRefreshToken(t *testing.T) {
+	os.Unsetenv("LOGIN_DEFER_REFRESH_TOKEN")
+	if loadLoginConfig().DeferRefreshToken {
+		t.Error("DeferRefreshToken deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("LOGIN_DEFER_REFRESH_TOKEN", "true")
+	defer os.Unsetenv("LOGIN_DEFER_REFRESH_TOKEN")
+	if !loadLoginConfig().DeferRefreshToken {
+		t.Error("DeferRefreshToken deveria respeitar LOGIN_DEFER_REFRESH_TOKEN")
+	}
+}
+
+func TestLoadLoginConfig_Lockout(t *testing.T) {
+	os.Unsetenv("LOGIN_LOCKOUT_MAX_FAILURES")
+	os.Unsetenv("LOGIN_LOCKOUT_DURATION")
+	cfg := loadLoginConfig()
+	if cfg.LockoutThreshold != 5 || cfg.LockoutDuration != 15*time.Minute {
+		t.Errorf("Padrão esperado 5 falhas / 15m, obtido %d / %s", cfg.LockoutThreshold, cfg.LockoutDuration)
+	}
+
+	os.Setenv("LOGIN_LOCKOUT_MAX_FAILURES", "3")
+	os.Setenv("LOGIN_LOCKOUT_DURATION", "60")
+	defer os.Unsetenv("LOGIN_LOCKOUT_MAX_FAILURES")
+	defer os.Unsetenv("LOGIN_LOCKOUT_DURATION")
+	cfg = loadLoginConfig()
+	if cfg.LockoutThreshold != 3 || cfg.LockoutDuration != time.Minute {
+		t.Errorf("Configuração explícita esperada 3 / 1m, obtida %d / %s", cfg.LockoutThreshold, cfg.LockoutDuration)
+	}
+}
+
+func TestLoadLoginConfig_RateLimit(t *testing.T) {
+	os.Unsetenv("LOGIN_RATE_LIMIT_RPS")
+	os.Unsetenv("LOGIN_RATE_LIMIT_BURST")
+	cfg := loadLoginConfig()
+	if cfg.RateLimitRPS != 1 || cfg.RateLimitBurst != 10 {
+		t.Errorf("Padrão esperado 1 rps / rajada 10, obtido %v / %d", cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+
+	os.Setenv("LOGIN_RATE_LIMIT_RPS", "0.5")
+	os.Setenv("LOGIN_RATE_LIMIT_BURST", "-3")
+	defer os.Unsetenv("LOGIN_RATE_LIMIT_RPS")
+	defer os.Unsetenv("LOGIN_RATE_LIMIT_BURST")
+	cfg = loadLoginConfig()
+	if cfg.RateLimitRPS != 0.5 || cfg.RateLimitBurst != 0 {
+		t.Errorf("Configuração esperada 0.5 rps / rajada 0, obtida %v / %d", cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+}
+
+func TestLoadPasswordConfig(t *testing.T) {
+	os.Unsetenv("PASSWORD_MIN_AGE")
+	if got := loadPasswordConfig().MinAge; got != 0 {
+		t.Errorf("MinAge padrão esperado 0, mas foi %v", got)
+	}
+
+	os.Setenv("PASSWORD_MIN_AGE", "86400")
+	defer os.Unsetenv("PASSWORD_MIN_AGE")
+	if got := loadPasswordConfig().MinAge; got != 24*time.Hour {
+		t.Errorf("MinAge esperado 24h, mas foi %v", got)
+	}
+
+	if got := loadPasswordConfig().HashAlgorithm; got != HashAlgorithmBcrypt {
+		t.Errorf("HashAlgorithm padrão esperado bcrypt, mas foi %q", got)
+	}
+	os.Setenv("PASSWORD_HASH_ALGORITHM", "Argon2id")
+	defer os.Unsetenv("PASSWORD_HASH_ALGORITHM")
+	if got := loadPasswordConfig().HashAlgorithm; got != HashAlgorithmArgon2id {
+		t.Errorf("HashAlgorithm esperado argon2id, mas foi %q", got)
+	}
+}
+
+func TestConfig_Validate_HashAlgorithm(t *testing.T) {
+	cfg := &Config{Password: PasswordConfig{HashAlgorithm: "md5"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("PASSWORD_HASH_ALGORITHM desconhecido deveria ser rejeitado")
+	}
+
+	// Com Argon2id, os parâmetros passam pelos limites do hasher
+	cfg.Password.HashAlgorithm = HashAlgorithmArgon2id
+	cfg.Argon2 = Argon2Config{MemoryKB: math.MaxUint32, Iterations: 3, Parallelism: 2}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Memória do Argon2 acima do limite deveria ser rejeitada")
+	}
+
+	cfg.Argon2.MemoryKB = 65536
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Parâmetros válidos do Argon2 deveriam ser aceitos: %v", err)
+	}
+}
+
+func TestLoadJWTConfig_EnforceTokenType(t *testing.T) {
+	os.Unsetenv("JWT_ENFORCE_TOKEN_TYPE")
+	if !loadJWTConfig().EnforceTokenType {
+		t.Error("EnforceTokenType deveria estar habilitado por padrão")
+	}
+
+	os.Setenv("JWT_ENFORCE_TOKEN_TYPE", "false")
+	defer os.Unsetenv("JWT_ENFORCE_TOKEN_TYPE")
+	if loadJWTConfig().EnforceTokenType {
+		t.Error("EnforceTokenType deveria respeitar a configuração explícita")
+	}
+}
+
+func TestLoadJWTConfig_PreviousRefreshSecrets(t *testing.T) {
+	os.Unsetenv("JWT_REFRESH_PREVIOUS_SECRETS")
+	if got := loadJWTConfig().PreviousRefreshSecrets; len(got) != 0 {
+		t.Errorf("PreviousRefreshSecrets deveria ser vazio por padrão, mas foi %v", got)
+	}
+
+	os.Setenv("JWT_REFRESH_PREVIOUS_SECRETS", "antiga-1, antiga-2")
+	defer os.Unsetenv("JWT_REFRESH_PREVIOUS_SECRETS")
+	got := loadJWTConfig().PreviousRefreshSecrets
+	if len(got) != 2 || got[0] != "antiga-1" || got[1] != "antiga-2" {
+		t.Errorf("PreviousRefreshSecrets esperado [antiga-1 antiga-2], mas foi %v", got)
+	}
+}
+
+func TestLoadJWTConfig_PrivateKeyFile(t *testing.T) {
+	os.Unsetenv("JWT_PRIVATE_KEY_FILE")
+	if got := loadJWTConfig().PrivateKeyFile; got != "" {
+		t.Errorf("PrivateKeyFile deveria ser vazio por padrão, mas foi %q", got)
+	}
+
+	os.Setenv("JWT_PRIVATE_KEY_FILE", "/etc/auth/jwt.pem")
+	defer os.Unsetenv("JWT_PRIVATE_KEY_FILE")
+	if got := loadJWTConfig().PrivateKeyFile; got != "/etc/auth/jwt.pem" {
+		t.Errorf("PrivateKeyFile esperado /etc/auth/jwt.pem, mas foi %q", got)
+	}
+}
+
+func TestLoadAccountConfig(t *testing.T) {
+	os.Unsetenv("ACCOUNT_STATUS_CACHE_TTL")
+	if got := loadAccountConfig().StatusCacheTTL; got != 30*time.Second {
+		t.Errorf("StatusCacheTTL padrão esperado 30s, mas foi %v", got)
+	}
+
+	os.Setenv("ACCOUNT_STATUS_CACHE_TTL", "0")
+	defer os.Unsetenv("ACCOUNT_STATUS_CACHE_TTL")
+	if got := loadAccountConfig().StatusCacheTTL; got != 0 {
+		t.Errorf("StatusCacheTTL esperado 0, mas foi %v", got)
+	}
+}
+
+func TestLoadAccountConfig_StrictClaims(t *testing.T) {
+	os.Unsetenv("AUTH_STRICT_CLAIMS")
+	if loadAccountConfig().StrictClaims {
+		t.Error("StrictClaims deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("AUTH_STRICT_CLAIMS", "true")
+	defer os.Unsetenv("AUTH_STRICT_CLAIMS")
+	if !loadAccountConfig().StrictClaims {
+		t.Error("StrictClaims deveria estar habilitado com AUTH_STRICT_CLAIMS=true")
+	}
+}
+
+func TestLoadAccountConfig_RejectDuplicateRoles(t *testing.T) {
+	os.Unsetenv("ADMIN_REJECT_DUPLICATE_ROLES")
+	if loadAccountConfig().RejectDuplicateRoles {
+		t.Error("RejectDuplicateRoles deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("ADMIN_REJECT_DUPLICATE_ROLES", "true")
+	defer os.Unsetenv("ADMIN_REJECT_DUPLICATE_ROLES")
+	if !loadAccountConfig().RejectDuplicateRoles {
+		t.Error("RejectDuplicateRoles deveria estar habilitado com ADMIN_REJECT_DUPLICATE_ROLES=true")
+	}
+}
+
+func TestLoadAccountConfig_DeletionTokenTTL(t *testing.T) {
+	os.Unsetenv("ACCOUNT_DELETION_TOKEN_TTL")
+	if got := loadAccountConfig().DeletionTokenTTL; got != 15*time.Minute {
+		t.Errorf("DeletionTokenTTL padrão esperado 15m, mas foi %v", got)
+	}
+
+	os.Setenv("ACCOUNT_DELETION_TOKEN_TTL", "120")
+	defer os.Unsetenv("ACCOUNT_DELETION_TOKEN_TTL")
+	if got := loadAccountConfig().DeletionTokenTTL; got != 2*time.Minute {
+		t.Errorf("DeletionTokenTTL esperado 2m, mas foi %v", got)
+	}
+}
+
+func TestLoadAccountConfig_TenantScopedEmail(t *testing.T) {
+	os.Unsetenv("ACCOUNT_TENANT_SCOPED_EMAIL")
+	if loadAccountConfig().TenantScopedEmail {
+		t.Error("TenantScopedEmail deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("ACCOUNT_TENANT_SCOPED_EMAIL", "true")
+	defer os.Unsetenv("ACCOUNT_TENANT_SCOPED_EMAIL")
+	if !loadAccountConfig().TenantScopedEmail {
+		t.Error("TenantScopedEmail deveria estar habilitado com ACCOUNT_TENANT_SCOPED_EMAIL=true")
+	}
+}
+
+func TestLoadJWTConfig_MaxAccessTTL(t *testing.T) {
+	os.Unsetenv("JWT_MAX_ACCESS_TTL_HOURS")
+	if got := loadJWTConfig().MaxAccessTTL; got != 24*time.Hour {
+		t.Errorf("MaxAccessTTL padrão esperado 24h, mas foi %v", got)
+	}
+
+	defer os.Unsetenv("JWT_MAX_ACCESS_TTL_HOURS")
+	os.Setenv("JWT_MAX_ACCESS_TTL_HOURS", "0")
+	if got := loadJWTConfig().MaxAccessTTL; got != 0 {
+		t.Errorf("MaxAccessTTL esperado 0 (sem limite), mas foi %v", got)
+	}
+
+	os.Setenv("JWT_MAX_ACCESS_TTL_HOURS", "2")
+	if got := loadJWTConfig().MaxAccessTTL; got != 2*time.Hour {
+		t.Errorf("MaxAccessTTL esperado 2h, mas foi %v", got)
+	}
+}
+
+func TestLoadJWTConfig_Audience(t *testing.T) {
+	os.Unsetenv("JWT_AUDIENCE")
+	if got := loadJWTConfig().Audience; got != "" {
+		t.Errorf("Audience deveria ser vazia por padrão, mas foi %q", got)
+	}
+
+	os.Setenv("JWT_AUDIENCE", "api")
+	defer os.Unsetenv("JWT_AUDIENCE")
+	if got := loadJWTConfig().Audience; got != "api" {
+		t.Errorf("Audience esperada api, mas foi %q", got)
+	}
+}
+
+func TestLoadNonceConfig(t *testing.T) {
+	os.Unsetenv("NONCE_REQUIRED")
+	os.Unsetenv("NONCE_TTL")
+	cfg := loadNonceConfig()
+	if cfg.Required {
+		t.Error("Nonce não deveria ser obrigatório por padrão")
+	}
+	if cfg.TTL != 10*time.Minute {
+		t.Errorf("TTL padrão esperado 10m, mas foi %v", cfg.TTL)
+	}
+
+	os.Setenv("NONCE_REQUIRED", "true")
+	os.Setenv("NONCE_TTL", "0")
+	defer os.Unsetenv("NONCE_REQUIRED")
+	defer os.Unsetenv("NONCE_TTL")
+	cfg = loadNonceConfig()
+	if !cfg.Required {
+		t.Error("Nonce deveria ser obrigatório com NONCE_REQUIRED=true")
+	}
+	if cfg.TTL != 10*time.Minute {
+		t.Errorf("TTL inválido deveria cair no padrão de 10m, mas foi %v", cfg.TTL)
+	}
+}
+
+func TestLoadBcryptConfig(t *testing.T) {
+	os.Unsetenv("BCRYPT_MAX_CONCURRENT")
+	os.Unsetenv("BCRYPT_QUEUE_TIMEOUT_MS")
+	cfg := loadBcryptConfig()
+	if cfg.MaxConcurrent != 0 {
+		t.Errorf("MaxConcurrent padrão esperado 0, mas foi %d", cfg.MaxConcurrent)
+	}
+	if cfg.QueueTimeout != 500*time.Millisecond {
+		t.Errorf("QueueTimeout padrão esperado 500ms, mas foi %v", cfg.QueueTimeout)
+	}
+
+	os.Setenv("BCRYPT_MAX_CONCURRENT", "4")
+	os.Setenv("BCRYPT_QUEUE_TIMEOUT_MS", "0")
+	defer os.Unsetenv("BCRYPT_MAX_CONCURRENT")
+	defer os.Unsetenv("BCRYPT_QUEUE_TIMEOUT_MS")
+	cfg = loadBcryptConfig()
+	if cfg.MaxConcurrent != 4 || cfg.QueueTimeout != 0 {
+		t.Errorf("esperado 4 e 0, mas foi %d e %v", cfg.MaxConcurrent, cfg.QueueTimeout)
+	}
+}
+
+func TestLoadBcryptConfig_Cost(t *testing.T) {
+	os.Unsetenv("BCRYPT_COST")
+	if got := loadBcryptConfig().Cost; got != bcrypt.DefaultCost {
+		t.Errorf("Cost padrão esperado %d, mas foi %d", bcrypt.DefaultCost, got)
+	}
+
+	os.Setenv("BCRYPT_COST", "12")
+	defer os.Unsetenv("BCRYPT_COST")
+	if got := loadBcryptConfig().Cost; got != 12 {
+		t.Errorf("Cost esperado 12, mas foi %d", got)
+	}
+}
+
+func TestConfig_Validate_BcryptCost(t *testing.T) {
+	for _, cost := range []int{bcrypt.MinCost - 1, bcrypt.MaxCost + 1} {
+		cfg := &Config{Bcrypt: BcryptConfig{Cost: cost}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Custo %d fora dos limites deveria ser rejeitado", cost)
+		}
+	}
+
+	for _, cost := range []int{0, bcrypt.MinCost, 12, bcrypt.MaxCost} {
+		cfg := &Config{Bcrypt: BcryptConfig{Cost: cost}}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Custo %d deveria ser aceito, mas retornou %v", cost, err)
+		}
+	}
+}
+
+func TestLoadAuthzConfig(t *testing.T) {
+	os.Unsetenv("ROLE_PERMISSIONS")
+	if got := loadAuthzConfig().RolePermissions; got != nil {
+		t.Errorf("RolePermissions deveria ser vazio por padrão, mas foi %v", got)
+	}
+
+	os.Setenv("ROLE_PERMISSIONS", "admin=users:read|users:write, user = profile:read ,invalida,=sem-papel")
+	defer os.Unsetenv("ROLE_PERMISSIONS")
+	got := loadAuthzConfig().RolePermissions
+	if len(got) != 2 {
+		t.Fatalf("2 papéis esperados, mas foi %v", got)
+	}
+	if len(got["admin"]) != 2 || got["admin"][1] != "users:write" {
+		t.Errorf("permissões de admin inesperadas: %v", got["admin"])
+	}
+	if len(got["user"]) != 1 || got["user"][0] != "profile:read" {
+		t.Errorf("permissões de user inesperadas: %v", got["user"])
+	}
+}
+
+func TestLoadAuditConfig(t *testing.T) {
+	os.Unsetenv("AUDIT_DENIED_ACCESS")
+	if loadAuditConfig().DeniedAccess {
+		t.Error("DeniedAccess deveria estar desabilitado por padrão")
+	}
+
+	os.Setenv("AUDIT_DENIED_ACCESS", "true")
+	defer os.Unsetenv("AUDIT_DENIED_ACCESS")
+	if !loadAuditConfig().DeniedAccess {
+		t.Error("DeniedAccess deveria estar habilitado com AUDIT_DENIED_ACCESS=true")
+	}
+}
+
+func TestLoadHeadersConfig(t *testing.T) {
+	for _, key := range []string{"SECURITY_FRAME_OPTIONS", "SECURITY_REFERRER_POLICY", "SECURITY_HSTS_MAX_AGE", "SECURITY_HSTS_INCLUDE_SUBDOMAINS"} {
+		os.Unsetenv(key)
+	}
+
+	config := loadHeadersConfig(AppConfig{Environment: "development"})
+	if config.FrameOptions != "DENY" || config.ReferrerPolicy != "no-referrer" {
+		t.Errorf("padrões inesperados: %+v", config)
+	}
+	if config.HSTSMaxAge != 0 {
+		t.Errorf("HSTS deveria estar desabilitado fora de produção, mas foi %v", config.HSTSMaxAge)
+	}
+
+	if got := loadHeadersConfig(AppConfig{Environment: "production"}).HSTSMaxAge; got != 365*24*time.Hour {
+		t.Errorf("HSTS em produção esperado 1 ano, mas foi %v", got)
+	}
+
+	os.Setenv("SECURITY_FRAME_OPTIONS", "SAMEORIGIN")
+	os.Setenv("SECURITY_HSTS_MAX_AGE", "0")
+	os.Setenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS", "true")
+	defer os.Unsetenv("SECURITY_FRAME_OPTIONS")
+	defer os.Unsetenv("SECURITY_HSTS_MAX_AGE")
+	defer os.Unsetenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS")
+
+	config = loadHeadersConfig(AppConfig{Environment: "production"})
+	if config.FrameOptions != "SAMEORIGIN" || config.HSTSMaxAge != 0 || !config.HSTSIncludeSubdomains {
+		t.Errorf("configuração explícita ignorada: %+v", config)
+	}
+}
+
+func TestLoadLogConfig(t *testing.T) {
+	os.Unsetenv("LOG_FORMAT")
+	if got := loadLogConfig().Format; got != "text" {
+		t.Errorf("Format padrão esperado text, mas foi %q", got)
+	}
+
+	os.Setenv("LOG_FORMAT", " JSON ")
+	defer os.Unsetenv("LOG_FORMAT")
+	if got := loadLogConfig().Format; got != "json" {
+		t.Errorf("Format esperado json, mas foi %q", got)
+	}
+}
+
+func TestConfig_Validate_LogFormat(t *testing.T) {
+	cfg := &Config{Log: LogConfig{Format: "xml"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("LOG_FORMAT inválido deveria ser rejeitado")
+	}
+
+	cfg.Log.Format = "json"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("LOG_FORMAT=json deveria ser aceito: %v", err)
+	}
+}
+
+func TestConfig_Validate_SessionRevokeRequiresSessions(t *testing.T) {
+	cfg := &Config{Session: SessionConfig{RevokeOnPasswordChange: true}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("SESSION_REVOKE_ON_PASSWORD_CHANGE sem SESSION_ENABLED deveria ser rejeitado")
+	}
+
+	cfg.Session.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("SESSION_REVOKE_ON_PASSWORD_CHANGE com sessões deveria ser aceito: %v", err)
+	}
+}
+
+func TestConfig_Validate_EmailVerificationRequiresWebhook(t *testing.T) {
+	cfg := &Config{Register: RegistrationConfig{RequireEmailVerification: true}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("REQUIRE_EMAIL_VERIFICATION sem NOTIFY_WEBHOOK_URL deveria ser rejeitado")
+	}
+
+	cfg.Notify.WebhookURL = "https://mailer.internal/events"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("REQUIRE_EMAIL_VERIFICATION com webhook deveria ser aceito: %v", err)
+	}
+
+	for _, invalid := range []string{"mailer.internal/events", "ftp://mailer.internal", "https://"} {
+		cfg.Notify.WebhookURL = invalid
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("NOTIFY_WEBHOOK_URL %q deveria ser rejeitada", invalid)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"reflect"
+	"time"
+	"unicode"
+)
+
+// RedactedValue substitui o valor de campos marcados com `secret:"true"`
+const RedactedValue = "********"
+
+// Redacted retorna a configuração efetiva como um mapa aninhado, com chaves em
+// snake_case, durações legíveis ("10s") e os campos secretos mascarados. Segredos
+// vazios continuam vazios, para que a ausência de configuração seja visível.
+func (c *Config) Redacted() map[string]any {
+	return redactStruct(reflect.ValueOf(*c))
+}
+
+func redactStruct(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		out[snakeCase(field.Name)] = redactValue(v.Field(i), field.Tag.Get("secret") == "true")
+	}
+	return out
+}
+
+func redactValue(v reflect.Value, secret bool) any {
+	if secret {
+		if v.IsZero() {
+			return ""
+		}
+		return RedactedValue
+	}
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	if v.Kind() == reflect.Struct {
+		return redactStruct(v)
+	}
+	return v.Interface()
+}
+
+// snakeCase converte nomes de campos Go (IssuerURL, MaxInFlight) para snake_case
+// (issuer_url, max_in_flight), mantendo siglas juntas
+func snakeCase(name string) string {
+	runes := []rune(name)
+	out := make([]rune, 0, len(runes)+4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, unicode.ToLower(r))
+	}
+	return string(out)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigRedacted(t *testing.T) {
+	cfg := &Config{
+		Database: DatabaseConfig{Host: "db", Password: "super-secreta"},
+		JWT:      JWTConfig{Secret: "chave", ExpirationHours: 24},
+		Server:   ServerConfig{ReadTimeout: 5 * time.Second},
+		Reset:    PasswordResetConfig{TokenSecret: "chave-reset", TokenTTL: 30 * time.Minute},
+		Notify:   NotificationConfig{EmailChange: true, WebhookURL: "https://mailer.internal/events?token=abc"},
+	}
+
+	got := cfg.Redacted()
+
+	database := got["database"].(map[string]any)
+	if database["password"] != RedactedValue {
+		t.Errorf("password deveria estar mascarada, mas foi %v", database["password"])
+	}
+	if database["host"] != "db" {
+		t.Errorf("host esperado db, mas foi %v", database["host"])
+	}
+	jwtCfg := got["jwt"].(map[string]any)
+	if jwtCfg["secret"] != RedactedValue {
+		t.Errorf("secret deveria estar mascarado, mas foi %v", jwtCfg["secret"])
+	}
+	if jwtCfg["refresh_secret"] != "" {
+		t.Errorf("refresh_secret vazio deveria continuar vazio, mas foi %v", jwtCfg["refresh_secret"])
+	}
+	if jwtCfg["expiration_hours"] != 24 {
+		t.Errorf("expiration_hours esperado 24, mas foi %v", jwtCfg["expiration_hours"])
+	}
+	reset := got["reset"].(map[string]any)
+	if reset["token_secret"] != RedactedValue {
+		t.Errorf("token_secret deveria estar mascarado, mas foi %v", reset["token_secret"])
+	}
+	if reset["token_ttl"] != "30m0s" {
+		t.Errorf("token_ttl esperado 30m0s, mas foi %v", reset["token_ttl"])
+	}
+	notify := got["notify"].(map[string]any)
+	if notify["webhook_url"] != RedactedValue {
+		t.Errorf("webhook_url deveria estar mascarada, mas foi %v", notify["webhook_url"])
+	}
+	if notify["email_change"] != true {
+		t.Errorf("email_change esperado true, mas foi %v", notify["email_change"])
+	}
+	if got["server"].(map[string]any)["read_timeout"] != "5s" {
+		t.Errorf("read_timeout esperado 5s, mas foi %v", got["server"].(map[string]any)["read_timeout"])
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"IssuerURL":       "issuer_url",
+		"MaxInFlight":     "max_in_flight",
+		"SSLMode":         "ssl_mode",
+		"MemoryKB":        "memory_kb",
+		"CORS":            "cors",
+		"RefreshExpHours": "refresh_exp_hours",
+	}
+	for in, want := range cases {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) esperado %q, mas foi %q", in, want, got)
+		}
+	}
+}
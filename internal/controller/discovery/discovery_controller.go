@@ -0,0 +1,49 @@
+package discovery
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// JWKSPath é o caminho do documento com as chaves públicas de verificação
+const JWKSPath = "/.well-known/jwks.json"
+
+// KeyProvider expõe as chaves públicas usadas para verificar os tokens emitidos
+type KeyProvider interface {
+	SigningAlgorithm() string
+	PublicJWKs() []auth.JWK
+}
+
+// DiscoveryController serve um documento de descoberta mínimo, no estilo OIDC,
+// para que servidores de recursos localizem o emissor e as chaves públicas
+type DiscoveryController struct {
+	issuer string
+	keys   KeyProvider
+}
+
+// NewDiscoveryController cria um novo controller de descoberta para o emissor informado
+func NewDiscoveryController(issuer string, keys KeyProvider) *DiscoveryController {
+	return &DiscoveryController{
+		issuer: strings.TrimSuffix(issuer, "/"),
+		keys:   keys,
+	}
+}
+
+// OpenIDConfiguration serve /.well-known/openid-configuration
+func (dc *DiscoveryController) OpenIDConfiguration(ctx *gin.Context) {
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"issuer":                                dc.issuer,
+		"jwks_uri":                              dc.issuer + JWKSPath,
+		"id_token_signing_alg_values_supported": []string{dc.keys.SigningAlgorithm()},
+		"subject_types_supported":               []string{"public"},
+	})
+}
+
+// JWKS serve /.well-known/jwks.json com as chaves públicas no formato JWK
+func (dc *DiscoveryController) JWKS(ctx *gin.Context) {
+	errors.GinRespondWithJSON(ctx, http.StatusOK, auth.JWKSet{Keys: dc.keys.PublicJWKs()})
+}
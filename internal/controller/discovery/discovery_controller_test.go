@@ -0,0 +1,79 @@
+package discovery
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticKeys struct {
+	keys []auth.JWK
+}
+
+func (s staticKeys) SigningAlgorithm() string { return "RS256" }
+func (s staticKeys) PublicJWKs() []auth.JWK   { return s.keys }
+
+func setupRouter(t *testing.T) (*gin.Engine, *rsa.PrivateKey) {
+	gin.SetMode(gin.TestMode)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	dc := NewDiscoveryController("https://auth.example.com/", staticKeys{
+		keys: []auth.JWK{auth.RSAPublicJWK("key-1", &key.PublicKey)},
+	})
+	r := gin.New()
+	r.GET("/.well-known/openid-configuration", dc.OpenIDConfiguration)
+	r.GET(JWKSPath, dc.JWKS)
+	return r, key
+}
+
+// Testa que o JWKS retorna a chave pública no formato JWK
+func TestDiscoveryController_JWKS(t *testing.T) {
+	t.Log("[INICIO] TestDiscoveryController_JWKS")
+
+	// Arrange
+	r, key := setupRouter(t)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", JWKSPath, nil)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var set auth.JWKSet
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	if assert.Len(t, set.Keys, 1) {
+		assert.Equal(t, auth.RSAPublicJWK("key-1", &key.PublicKey), set.Keys[0])
+	}
+	t.Log("[FIM] TestDiscoveryController_JWKS")
+}
+
+// Testa que o documento de descoberta referencia o emissor e o JWKS
+func TestDiscoveryController_OpenIDConfiguration(t *testing.T) {
+	t.Log("[INICIO] TestDiscoveryController_OpenIDConfiguration")
+
+	// Arrange
+	r, _ := setupRouter(t)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/.well-known/openid-configuration", nil)
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "https://auth.example.com", doc["issuer"])
+	assert.Equal(t, "https://auth.example.com/.well-known/jwks.json", doc["jwks_uri"])
+	assert.Equal(t, []any{"RS256"}, doc["id_token_signing_alg_values_supported"])
+	t.Log("[FIM] TestDiscoveryController_OpenIDConfiguration")
+}
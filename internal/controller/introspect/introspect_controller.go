@@ -0,0 +1,109 @@
+package introspect
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// DefaultMaxBatchSize é o número máximo de tokens aceitos por requisição
+const DefaultMaxBatchSize = 100
+
+// TokenValidator valida access tokens e retorna as suas claims
+type TokenValidator interface {
+	ValidateToken(token string) (*auth.TokenClaims, error)
+}
+
+// UserLookup busca o usuário dono do token, para conferir o estado atual da conta
+type UserLookup interface {
+	GetByID(id string) (*domain.User, error)
+}
+
+// IntrospectController permite que gateways validem tokens em lote
+type IntrospectController struct {
+	validator    TokenValidator
+	users        UserLookup
+	maxBatchSize int
+}
+
+// Result representa o resultado da validação de um token do lote
+type Result struct {
+	Active bool              `json:"active"`
+	Claims *auth.TokenClaims `json:"claims,omitempty"`
+}
+
+// NewIntrospectController cria o controller; maxBatchSize <= 0 usa DefaultMaxBatchSize
+func NewIntrospectController(validator TokenValidator, users UserLookup, maxBatchSize int) *IntrospectController {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultMaxBatchSize
+	}
+	return &IntrospectController{validator: validator, users: users, maxBatchSize: maxBatchSize}
+}
+
+// IntrospectBatch valida cada token recebido e retorna os resultados na mesma
+// ordem. Além da assinatura e da validade, um token só é ativo se a conta
+// existir, estiver ativa e não tiver troca de senha pendente; tokens restritos à
+// troca de senha também são inativos.
+func (ic *IntrospectController) IntrospectBatch(ctx *gin.Context) {
+	var req struct {
+		Tokens []string `json:"tokens"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de introspecção: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	if len(req.Tokens) == 0 {
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", []errors.ValidationDetail{
+			{Field: "tokens", Message: "Informe ao menos um token"},
+		}))
+		return
+	}
+
+	if len(req.Tokens) > ic.maxBatchSize {
+		logging.With(ctx).Warning("Lote de introspecção excede o limite: %d tokens (máximo %d)", len(req.Tokens), ic.maxBatchSize)
+		errors.GinHandleError(ctx, errors.NewValidationError("Lote de tokens excede o limite", []errors.ValidationDetail{
+			{Field: "tokens", Message: "Máximo de tokens por requisição excedido"},
+		}))
+		return
+	}
+
+	results := make([]Result, len(req.Tokens))
+	// Cada conta é consultada uma única vez por lote
+	allowed := make(map[string]bool)
+	for i, token := range req.Tokens {
+		claims, err := ic.validator.ValidateToken(token)
+		if err != nil || claims.PasswordChangeRequired {
+			continue
+		}
+		ok, seen := allowed[claims.UserID]
+		if !seen {
+			ok = ic.accountAllowed(ctx, claims.UserID)
+			allowed[claims.UserID] = ok
+		}
+		if !ok {
+			continue
+		}
+		results[i] = Result{Active: true, Claims: claims}
+	}
+
+	logging.With(ctx).Info("Introspecção em lote de %d tokens", len(req.Tokens))
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{"results": results})
+}
+
+// accountAllowed indica se a conta do token pode usá-lo: existente, ativa e sem
+// troca de senha pendente. Falhas na consulta tornam o token inativo.
+func (ic *IntrospectController) accountAllowed(ctx *gin.Context, userID string) bool {
+	user, err := ic.users.GetByID(userID)
+	if err != nil && !errors.Is(err, errors.ErrUserNotFound) {
+		logging.With(ctx).Error("Erro ao consultar a conta %s na introspecção: %v", userID, err)
+		return false
+	}
+	return user != nil && user.IsActive() && !user.MustChangePassword
+}
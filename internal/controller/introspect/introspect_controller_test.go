@@ -0,0 +1,130 @@
+package introspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubUsers implementa UserLookup com um mapa fixo de usuários
+type stubUsers map[string]*domain.User
+
+func (s stubUsers) GetByID(id string) (*domain.User, error) {
+	return s[id], nil
+}
+
+func setupRouter(maxBatch int) (*gin.Engine, *auth.JWTService) {
+	return setupRouterWithUsers(maxBatch, stubUsers{"u1": {ID: "u1", Email: "a@b.com", Status: domain.UserStatusActive}})
+}
+
+func setupRouterWithUsers(maxBatch int, users UserLookup) (*gin.Engine, *auth.JWTService) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret", 1, "test-refresh", 1)
+	ic := NewIntrospectController(jwtService, users, maxBatch)
+	r := gin.New()
+	r.POST("/auth/introspect/batch", ic.IntrospectBatch)
+	return r, jwtService
+}
+
+func postBatch(r *gin.Engine, tokens []string) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(map[string]any{"tokens": tokens})
+	req := httptest.NewRequest("POST", "/auth/introspect/batch", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// Testa que um lote com tokens válido, expirado e inválido retorna resultados por token
+func TestIntrospectController_IntrospectBatch(t *testing.T) {
+	t.Log("[INICIO] TestIntrospectController_IntrospectBatch")
+
+	// Arrange
+	r, jwtService := setupRouter(0)
+	valid, _ := jwtService.GenerateToken(&domain.User{ID: "u1", Email: "a@b.com", Roles: []string{domain.RoleUser}})
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.TokenClaims{
+		UserID:           "u2",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+	}).SignedString([]byte(jwtService.GetSecretKey()))
+
+	// Act
+	w := postBatch(r, []string{valid, expired, "lixo"})
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Results []struct {
+			Active bool              `json:"active"`
+			Claims *auth.TokenClaims `json:"claims"`
+		} `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Results, 3) {
+		assert.True(t, response.Results[0].Active)
+		assert.Equal(t, "u1", response.Results[0].Claims.UserID)
+		assert.False(t, response.Results[1].Active)
+		assert.Nil(t, response.Results[1].Claims)
+		assert.False(t, response.Results[2].Active)
+	}
+	t.Log("[FIM] TestIntrospectController_IntrospectBatch")
+}
+
+// Testa que lotes vazios ou acima do limite são rejeitados
+func TestIntrospectController_IntrospectBatch_Limits(t *testing.T) {
+	t.Log("[INICIO] TestIntrospectController_IntrospectBatch_Limits")
+
+	r, _ := setupRouter(2)
+
+	assert.Equal(t, http.StatusBadRequest, postBatch(r, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, postBatch(r, []string{"a", "b", "c"}).Code)
+	assert.Equal(t, http.StatusOK, postBatch(r, []string{"a", "b"}).Code)
+	t.Log("[FIM] TestIntrospectController_IntrospectBatch_Limits")
+}
+
+// Testa que tokens válidos de contas inativas, removidas ou com troca de senha
+// pendente, e tokens restritos à troca de senha, não são ativos
+func TestIntrospectController_IntrospectBatch_AccountState(t *testing.T) {
+	t.Log("[INICIO] TestIntrospectController_IntrospectBatch_AccountState")
+
+	// Arrange
+	users := stubUsers{
+		"ativo":    {ID: "ativo", Status: domain.UserStatusActive},
+		"inativo":  {ID: "inativo", Status: domain.UserStatusDisabled},
+		"trocar":   {ID: "trocar", Status: domain.UserStatusActive, MustChangePassword: true},
+		"restrito": {ID: "restrito", Status: domain.UserStatusActive},
+	}
+	r, jwtService := setupRouterWithUsers(0, users)
+	tokenFor := func(id string) string {
+		token, _ := jwtService.GenerateToken(&domain.User{ID: id})
+		return token
+	}
+	restricted, _ := jwtService.GeneratePasswordChangeToken(&domain.User{ID: "restrito"})
+
+	// Act
+	w := postBatch(r, []string{tokenFor("ativo"), tokenFor("inativo"), tokenFor("trocar"), tokenFor("removido"), restricted})
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Results []struct {
+			Active bool `json:"active"`
+		} `json:"results"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Results, 5) {
+		assert.True(t, response.Results[0].Active)
+		for _, result := range response.Results[1:] {
+			assert.False(t, result.Active)
+		}
+	}
+	t.Log("[FIM] TestIntrospectController_IntrospectBatch_AccountState")
+}
@@ -0,0 +1,68 @@
+package nonce
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// DefaultTTL é a validade padrão de um nonce emitido
+const DefaultTTL = 10 * time.Minute
+
+// NonceController emite nonces de uso único para formulários sensíveis
+type NonceController struct {
+	store domain.NonceStore
+	ttl   time.Duration
+	clock clock.Clock
+}
+
+// NewNonceController cria um novo controller de nonces; ttl <= 0 usa DefaultTTL
+func NewNonceController(store domain.NonceStore, ttl time.Duration, c clock.Clock) *NonceController {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if c == nil {
+		c = clock.System()
+	}
+	return &NonceController{store: store, ttl: ttl, clock: c}
+}
+
+// Issue gera um nonce aleatório, válido por um único envio até expirar
+func (nc *NonceController) Issue(ctx *gin.Context) {
+	value, err := newNonce()
+	if err != nil {
+		logging.Error("Erro ao gerar nonce: %v", err)
+		errors.GinHandleError(ctx, errors.ErrInternalServer.WithError(err))
+		return
+	}
+
+	expiresAt := nc.clock.Now().Add(nc.ttl)
+	if err := nc.store.Save(value, expiresAt); err != nil {
+		logging.Error("Erro ao salvar nonce: %v", err)
+		errors.GinHandleError(ctx, errors.ErrInternalServer.WithError(err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"nonce":      value,
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+		"expires_in": int64(nc.ttl / time.Second),
+	})
+}
+
+// newNonce gera 32 bytes aleatórios codificados em base64 URL-safe
+func newNonce() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
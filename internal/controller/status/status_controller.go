@@ -0,0 +1,76 @@
+package status
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/scheduler"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// SweeperSource fornece o estado das varreduras em segundo plano
+type SweeperSource interface {
+	Statuses() []scheduler.Status
+}
+
+// sweeperResponse é a representação JSON do estado de uma varredura
+type sweeperResponse struct {
+	Name        string     `json:"name"`
+	Interval    string     `json:"interval"`
+	Running     bool       `json:"running"`
+	LastRun     *time.Time `json:"last_run"`
+	LastRemoved int        `json:"last_removed"`
+	LastError   string     `json:"last_error,omitempty"`
+	Runs        int64      `json:"runs"`
+	Stalled     bool       `json:"stalled"`
+}
+
+// StatusController expõe a saúde dos componentes em segundo plano
+type StatusController struct {
+	sweepers SweeperSource
+}
+
+// NewStatusController cria um novo controller de status
+func NewStatusController(sweepers SweeperSource) *StatusController {
+	return &StatusController{sweepers: sweepers}
+}
+
+// Health serve /health, respondendo 200 enquanto o processo atende requisições
+func (sc *StatusController) Health(ctx *gin.Context) {
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Status serve /status com a última execução de cada varredura. Responde 503
+// quando alguma varredura em andamento está travada, para uso como probe.
+func (sc *StatusController) Status(ctx *gin.Context) {
+	statuses := sc.sweepers.Statuses()
+	sweepers := make([]sweeperResponse, 0, len(statuses))
+	stalled := false
+	for _, s := range statuses {
+		resp := sweeperResponse{
+			Name:        s.Name,
+			Interval:    s.Interval.String(),
+			Running:     s.Running,
+			LastRemoved: s.LastRemoved,
+			LastError:   s.LastError,
+			Runs:        s.Runs,
+			Stalled:     s.Stalled,
+		}
+		if !s.LastRun.IsZero() {
+			lastRun := s.LastRun.UTC()
+			resp.LastRun = &lastRun
+		}
+		stalled = stalled || s.Stalled
+		sweepers = append(sweepers, resp)
+	}
+
+	code, overall := http.StatusOK, "ok"
+	if stalled {
+		code, overall = http.StatusServiceUnavailable, "degraded"
+	}
+	errors.GinRespondWithJSON(ctx, code, gin.H{
+		"status":   overall,
+		"sweepers": sweepers,
+	})
+}
@@ -0,0 +1,101 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/scheduler"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+type statusBody struct {
+	Status   string            `json:"status"`
+	Sweepers []json.RawMessage `json:"sweepers"`
+}
+
+func getStatus(t *testing.T, source SweeperSource) (*httptest.ResponseRecorder, statusBody) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/status", NewStatusController(source).Status)
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var body statusBody
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w, body
+}
+
+// Testa que a última execução reportada avança após um ciclo de varredura
+func TestStatusController_ReportsLastRun(t *testing.T) {
+	t.Log("[INICIO] TestStatusController_ReportsLastRun")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sweeper := scheduler.NewSweeper("revoked_tokens", time.Minute, func() (int, error) { return 2, nil },
+		scheduler.WithClock(clock.Func(func() time.Time { return now })))
+	registry := scheduler.NewRegistry()
+	registry.Register(sweeper)
+
+	w, body := getStatus(t, registry)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", body.Status)
+	assert.Len(t, body.Sweepers, 1)
+	assert.Contains(t, string(body.Sweepers[0]), `"last_run":null`)
+
+	sweeper.RunOnce()
+	_, body = getStatus(t, registry)
+	assert.Contains(t, string(body.Sweepers[0]), `"last_run":"2024-01-01T12:00:00Z"`)
+	assert.Contains(t, string(body.Sweepers[0]), `"last_removed":2`)
+
+	now = now.Add(time.Minute)
+	sweeper.RunOnce()
+	_, body = getStatus(t, registry)
+	assert.Contains(t, string(body.Sweepers[0]), `"last_run":"2024-01-01T12:01:00Z"`)
+	assert.Contains(t, string(body.Sweepers[0]), `"runs":2`)
+	t.Log("[FIM] TestStatusController_ReportsLastRun")
+}
+
+// Testa que uma varredura travada torna o status degradado com 503
+func TestStatusController_StalledSweeper(t *testing.T) {
+	t.Log("[INICIO] TestStatusController_StalledSweeper")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sweeper := scheduler.NewSweeper("nonces", time.Hour, func() (int, error) { return 0, nil },
+		scheduler.WithClock(clock.Func(func() time.Time { return now })))
+	sweeper.Start()
+	defer sweeper.Stop()
+	registry := scheduler.NewRegistry()
+	registry.Register(sweeper)
+
+	now = now.Add(3 * time.Hour)
+	w, body := getStatus(t, registry)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "degraded", body.Status)
+	assert.Contains(t, string(body.Sweepers[0]), `"stalled":true`)
+	t.Log("[FIM] TestStatusController_StalledSweeper")
+}
+
+// Testa que /health responde 200 sem depender das varreduras
+func TestStatusController_Health(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/health", NewStatusController(scheduler.NewRegistry()).Health)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+}
+
+// Testa que sem varreduras a lista é vazia, e não null
+func TestStatusController_NoSweepers(t *testing.T) {
+	w, _ := getStatus(t, scheduler.NewRegistry())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"sweepers":[]`)
+}
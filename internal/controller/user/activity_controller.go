@@ -0,0 +1,42 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// ActivityController expõe o histórico de atividade da conta
+type ActivityController struct {
+	activity domain.ActivityStore
+}
+
+func NewActivityController(activity domain.ActivityStore) *ActivityController {
+	return &ActivityController{activity: activity}
+}
+
+// List retorna, paginado, o histórico de atividade do usuário (próprio ou admin)
+func (ac *ActivityController) List(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	if !requireSelfOrAdmin(ctx, userID) {
+		return
+	}
+
+	page, pageSize := parsePagination(ctx)
+	events, total, err := ac.activity.ListByUser(userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logging.With(ctx).Error("Falha ao listar atividade do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, errors.ErrInternalServer.WithError(err))
+		return
+	}
+
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"items":     listOf(events),
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
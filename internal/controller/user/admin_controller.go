@@ -2,6 +2,8 @@ package user
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
@@ -11,25 +13,152 @@ import (
 
 type AdminController struct {
 	userService domain.UserService
+
+	configSnapshot       map[string]any
+	rejectDuplicateRoles bool
+}
+
+// AdminControllerOption configura opções opcionais do AdminController
+type AdminControllerOption func(*AdminController)
+
+// WithDuplicateRolesRejected faz Update responder 400 quando roles contém
+// papéis repetidos; por padrão as repetições são descartadas em silêncio
+func WithDuplicateRolesRejected(reject bool) AdminControllerOption {
+	return func(ac *AdminController) {
+		ac.rejectDuplicateRoles = reject
+	}
 }
 
-func NewAdminController(userService domain.UserService) *AdminController {
-	return &AdminController{userService: userService}
+// WithConfigSnapshot expõe a configuração efetiva, já com os segredos mascarados,
+// em GET /admin/config
+func WithConfigSnapshot(snapshot map[string]any) AdminControllerOption {
+	return func(ac *AdminController) {
+		ac.configSnapshot = snapshot
+	}
 }
 
-// ListAll lista todos os usuários
+func NewAdminController(userService domain.UserService, opts ...AdminControllerOption) *AdminController {
+	ac := &AdminController{userService: userService}
+	for _, opt := range opts {
+		opt(ac)
+	}
+	return ac
+}
+
+// Config retorna a configuração efetiva da aplicação, sem segredos, para depurar
+// divergências entre ambientes
+func (ac *AdminController) Config(ctx *gin.Context) {
+	if ac.configSnapshot == nil {
+		errors.GinHandleError(ctx, errors.ErrRouteNotFound)
+		return
+	}
+	errors.GinRespondWithJSON(ctx, http.StatusOK, ac.configSnapshot)
+}
+
+// Cabeçalhos com os metadados da página retornada em GET /admin/users
+const (
+	TotalCountHeader = "X-Total-Count"
+	PageHeader       = "X-Page"
+	PageSizeHeader   = "X-Page-Size"
+)
+
+// ListAll lista os usuários de forma paginada (?page=, a partir de 1, e
+// ?page_size=, limitado a MaxPageSize). Com created_from/created_to (RFC3339 ou
+// AAAA-MM-DD), restringe a listagem pela data de criação. O corpo continua sendo
+// a lista de usuários; o total segue em X-Total-Count e páginas além do fim
+// retornam lista vazia.
 func (ac *AdminController) ListAll(ctx *gin.Context) {
-	users, err := ac.userService.List()
+	page, pageSize := parsePagination(ctx)
+	offset := (page - 1) * pageSize
+
+	var users []*domain.User
+	var total int
+	var err error
+	if ctx.Query("created_from") != "" || ctx.Query("created_to") != "" {
+		from, to, details := parseCreatedRange(ctx.Query("created_from"), ctx.Query("created_to"))
+		if len(details) > 0 {
+			errors.GinHandleError(ctx, errors.NewValidationError("Intervalo de datas inválido", details))
+			return
+		}
+		users, err = ac.userService.ListCreatedBetween(from, to)
+		total = len(users)
+		users = pageOf(users, offset, pageSize)
+	} else {
+		users, total, err = ac.userService.ListPaginated(offset, pageSize)
+	}
 	if err != nil {
 		logging.Error("Erro ao listar usuários: %v", err)
 		errors.GinHandleError(ctx, errors.ErrInternalServer.WithError(err))
 		return
 	}
-	responses := make([]*domain.UserResponse, 0, len(users))
+
+	ctx.Header(TotalCountHeader, strconv.Itoa(total))
+	ctx.Header(PageHeader, strconv.Itoa(page))
+	ctx.Header(PageSizeHeader, strconv.Itoa(pageSize))
+	var responses []*domain.AdminUserResponse
 	for _, u := range users {
-		responses = append(responses, u.ToUserResponse())
+		responses = append(responses, u.ToAdminUserResponse())
+	}
+	errors.GinRespondWithJSON(ctx, http.StatusOK, listOf(responses))
+}
+
+// Stats retorna o total de usuários, os verificados e os administradores
+func (ac *AdminController) Stats(ctx *gin.Context) {
+	stats, err := ac.userService.Stats()
+	if err != nil {
+		logging.Error("Erro ao obter estatísticas de usuários: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+	errors.GinRespondWithJSON(ctx, http.StatusOK, stats)
+}
+
+// pageOf recorta a página [offset, offset+limit) de uma listagem já carregada
+func pageOf(users []*domain.User, offset, limit int) []*domain.User {
+	if offset >= len(users) {
+		return nil
+	}
+	return users[offset:min(offset+limit, len(users))]
+}
+
+// dateOnlyLayout é o formato aceito para datas sem horário
+const dateOnlyLayout = "2006-01-02"
+
+// parseCreatedRange converte os limites do filtro de criação no intervalo [from, to).
+// Um created_to apenas com data inclui o dia inteiro.
+func parseCreatedRange(rawFrom, rawTo string) (time.Time, time.Time, []errors.ValidationDetail) {
+	var details []errors.ValidationDetail
+	from, fromOK := parseDateBound(rawFrom, false)
+	if !fromOK {
+		details = append(details, errors.ValidationDetail{Field: "created_from", Message: "Data inválida; use RFC3339 ou AAAA-MM-DD"})
+	}
+	to, toOK := parseDateBound(rawTo, true)
+	if !toOK {
+		details = append(details, errors.ValidationDetail{Field: "created_to", Message: "Data inválida; use RFC3339 ou AAAA-MM-DD"})
+	}
+	if fromOK && toOK && !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		details = append(details, errors.ValidationDetail{Field: "created_to", Message: "Deve ser posterior a created_from"})
 	}
-	errors.GinRespondWithJSON(ctx, http.StatusOK, responses)
+	return from, to, details
+}
+
+// parseDateBound interpreta um limite vazio como ausente; endOfDay avança datas
+// sem horário para o início do dia seguinte
+func parseDateBound(raw string, endOfDay bool) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	t, err := time.Parse(dateOnlyLayout, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
 }
 
 // GetByID busca um usuário pelo ID
@@ -45,7 +174,26 @@ func (ac *AdminController) GetByID(ctx *gin.Context) {
 		errors.GinHandleError(ctx, err)
 		return
 	}
-	errors.GinRespondWithJSON(ctx, http.StatusOK, user.ToUserResponse())
+	errors.GinRespondWithJSON(ctx, http.StatusOK, user.ToAdminUserResponse())
+}
+
+// GetByEmail busca um usuário pelo email informado em ?email=, normalizado como
+// no cadastro; no modo multi-tenant, dentro da organização de ?org_id=
+func (ac *AdminController) GetByEmail(ctx *gin.Context) {
+	email := domain.NormalizeEmail(ctx.Query("email"))
+	if email == "" {
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", []errors.ValidationDetail{
+			{Field: "email", Message: "Email é obrigatório"},
+		}))
+		return
+	}
+	user, err := ac.userService.GetByOrgAndEmail(ctx.Query("org_id"), email)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário por email: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+	errors.GinRespondWithJSON(ctx, http.StatusOK, user.ToAdminUserResponse())
 }
 
 // Update atualiza os dados de um usuário (incluindo roles)
@@ -59,12 +207,22 @@ func (ac *AdminController) Update(ctx *gin.Context) {
 		Email string   `json:"email,omitempty"`
 		Name  string   `json:"name,omitempty"`
 		Roles []string `json:"roles,omitempty"`
+		// MustChangePassword exige que o usuário troque a senha no próximo login
+		MustChangePassword *bool `json:"must_change_password,omitempty"`
+		// Status desativa ("disabled") ou reativa ("active") a conta
+		Status string `json:"status,omitempty"`
 	}
 	if err := ctx.ShouldBindJSON(&updateData); err != nil {
 		logging.Error("Erro ao decodificar corpo da requisição: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
+	if updateData.Status != "" && !domain.IsValidUserStatus(updateData.Status) {
+		errors.GinHandleError(ctx, errors.NewValidationError("Status inválido", []errors.ValidationDetail{
+			{Field: "status", Message: "Use active ou disabled"},
+		}))
+		return
+	}
 	currentUser, err := ac.userService.GetByID(userID)
 	if err != nil {
 		logging.Error("Erro ao buscar usuário para atualização: %v", err)
@@ -72,21 +230,34 @@ func (ac *AdminController) Update(ctx *gin.Context) {
 		return
 	}
 	if updateData.Email != "" {
-		currentUser.Email = updateData.Email
+		currentUser.Email = domain.NormalizeEmail(updateData.Email)
 	}
 	if updateData.Name != "" {
 		currentUser.Name = updateData.Name
 	}
 	if updateData.Roles != nil {
-		currentUser.Roles = updateData.Roles
+		if duplicates := currentUser.SetRoles(updateData.Roles); duplicates > 0 && ac.rejectDuplicateRoles {
+			errors.GinHandleError(ctx, errors.NewValidationError("Roles inválidas", []errors.ValidationDetail{
+				{Field: "roles", Message: "Papéis repetidos não são permitidos"},
+			}))
+			return
+		}
+	}
+	if updateData.MustChangePassword != nil {
+		currentUser.MustChangePassword = *updateData.MustChangePassword
 	}
+	if updateData.Status != "" {
+		currentUser.Status = updateData.Status
+	}
+	currentUser.UpdatedBy = actorID(ctx)
 	err = ac.userService.Update(currentUser)
 	if err != nil {
 		logging.Error("Erro ao atualizar usuário: %v", err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
-	errors.GinRespondWithJSON(ctx, http.StatusOK, currentUser.ToUserResponse())
+	ac.recordAudit(ctx, domain.AuditActionUserUpdated, currentUser.ID)
+	errors.GinRespondWithJSON(ctx, http.StatusOK, currentUser.ToAdminUserResponse())
 }
 
 // Delete remove um usuário
@@ -102,5 +273,26 @@ func (ac *AdminController) Delete(ctx *gin.Context) {
 		errors.GinHandleError(ctx, err)
 		return
 	}
+	ac.recordAudit(ctx, domain.AuditActionUserDeleted, userID)
 	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{"message": "Usuário deletado com sucesso"})
 }
+
+// recordAudit registra a ação na trilha de auditoria. A alteração já foi
+// aplicada, então uma falha é apenas logada e não muda a resposta.
+func (ac *AdminController) recordAudit(ctx *gin.Context, action, targetID string) {
+	actor := actorID(ctx)
+	if err := ac.userService.RecordAuditEvent(actor, action, targetID); err != nil {
+		logging.With(ctx).Error("Falha ao registrar auditoria %s de %s sobre %s: %v", action, actor, targetID, err)
+	}
+}
+
+// actorID retorna o ID do usuário autenticado que executa a ação,
+// ou "system" quando a rota não passou pelo middleware de autenticação
+func actorID(ctx *gin.Context) string {
+	if id, ok := ctx.Get("user_id"); ok {
+		if s, ok := id.(string); ok && s != "" {
+			return s
+		}
+	}
+	return domain.ActorSystem
+}
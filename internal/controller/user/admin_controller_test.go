@@ -5,31 +5,99 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/audit"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockAdminUserService struct {
-	ListFn    func() ([]*domain.User, error)
-	GetByIDFn func(string) (*domain.User, error)
-	UpdateFn  func(*domain.User) error
-	DeleteFn  func(string) error
+	ListFn       func() ([]*domain.User, error)
+	GetByIDFn    func(string) (*domain.User, error)
+	GetByEmailFn func(string) (*domain.User, error)
+	UpdateFn     func(*domain.User) error
+	DeleteFn     func(string) error
+
+	ListCreatedBetweenFn func(from, to time.Time) ([]*domain.User, error)
+	StatsFn              func() (*domain.UserStats, error)
+	ListPaginatedFn      func(offset, limit int) ([]*domain.User, int, error)
+	RecordAuditEventFn   func(actorID, action, targetID string) error
 }
 
 func (m *mockAdminUserService) List() ([]*domain.User, error)           { return m.ListFn() }
+func (m *mockAdminUserService) ListAll() ([]*domain.User, error)        { return m.ListFn() }
 func (m *mockAdminUserService) GetByID(id string) (*domain.User, error) { return m.GetByIDFn(id) }
-func (m *mockAdminUserService) Update(u *domain.User) error             { return m.UpdateFn(u) }
-func (m *mockAdminUserService) Delete(id string) error                  { return m.DeleteFn(id) }
+func (m *mockAdminUserService) GetByEmail(email string) (*domain.User, error) {
+	return m.GetByEmailFn(email)
+}
+func (m *mockAdminUserService) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	return m.GetByEmailFn(email)
+}
+func (m *mockAdminUserService) Update(u *domain.User) error { return m.UpdateFn(u) }
+func (m *mockAdminUserService) Delete(id string) error      { return m.DeleteFn(id) }
+func (m *mockAdminUserService) RecordAuditEvent(actorID, action, targetID string) error {
+	if m.RecordAuditEventFn != nil {
+		return m.RecordAuditEventFn(actorID, action, targetID)
+	}
+	return nil
+}
 
 // Métodos não usados
 func (m *mockAdminUserService) Create(u *domain.User) error                      { return nil }
-func (m *mockAdminUserService) GetByEmail(email string) (*domain.User, error)    { return nil, nil }
 func (m *mockAdminUserService) Authenticate(e, p string) (string, string, error) { return "", "", nil }
 func (m *mockAdminUserService) RefreshTokens(t string) (string, string, error)   { return "", "", nil }
+func (m *mockAdminUserService) UpdateFields(id string, f map[string]any) error   { return nil }
+func (m *mockAdminUserService) ChangePassword(id, current, next string) error    { return nil }
+func (m *mockAdminUserService) RevokeRefreshToken(t string) error                { return nil }
+func (m *mockAdminUserService) RotateSessions(id, t string) (string, string, error) {
+	return "", "", nil
+}
+func (m *mockAdminUserService) RevokeAllTokens(id string) error { return nil }
+func (m *mockAdminUserService) RegisterAndLogin(u *domain.User) (domain.TokenPair, error) {
+	return domain.TokenPair{}, nil
+}
+func (m *mockAdminUserService) IssueInitialRefreshToken(id, jti string) (string, error) {
+	return "", nil
+}
+func (m *mockAdminUserService) ListSessions(userID string) ([]*domain.Session, error) {
+	return nil, nil
+}
+func (m *mockAdminUserService) AuthenticateInOrg(orgID, e, p string) (string, string, error) {
+	return "", "", nil
+}
+func (m *mockAdminUserService) RequestPasswordReset(req domain.PasswordResetRequest) error {
+	return nil
+}
+func (m *mockAdminUserService) ConfirmPasswordReset(token, newPassword string) error {
+	return nil
+}
+func (m *mockAdminUserService) VerifyEmail(token string) error                { return nil }
+func (m *mockAdminUserService) RequestDeletion(userID string) (string, error) { return "", nil }
+func (m *mockAdminUserService) ConfirmDeletion(userID, token string) error    { return nil }
+func (m *mockAdminUserService) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	return m.ListCreatedBetweenFn(from, to)
+}
+func (m *mockAdminUserService) Stats() (*domain.UserStats, error) { return m.StatsFn() }
+
+// ListPaginated usa ListPaginatedFn ou, na ausência, pagina o resultado de ListFn
+func (m *mockAdminUserService) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	if m.ListPaginatedFn != nil {
+		return m.ListPaginatedFn(offset, limit)
+	}
+	users, err := m.ListFn()
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset >= len(users) {
+		return []*domain.User{}, len(users), nil
+	}
+	return users[offset:min(offset+limit, len(users))], len(users), nil
+}
 
 func setupGinAdmin() *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -78,6 +146,68 @@ func TestAdminController_ListAll_Error(t *testing.T) {
 	t.Log("[FIM] TestAdminController_ListAll_Error")
 }
 
+func TestAdminController_ListAll_EmptyIsArray(t *testing.T) {
+	// Arrange: O serviço retorna nil para um conjunto vazio
+	ms := &mockAdminUserService{ListPaginatedFn: func(offset, limit int) ([]*domain.User, int, error) {
+		return nil, 0, nil
+	}}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/users", ac.ListAll)
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/admin/users", nil))
+
+	// Assert: A lista vazia é serializada como [] e não como null
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+}
+
+func TestAdminController_ListAll_Pagination(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_ListAll_Pagination")
+
+	// Arrange: 25 usuários, paginados pelo mock a partir de ListFn
+	users := make([]*domain.User, 0, 25)
+	for i := range 25 {
+		users = append(users, &domain.User{ID: strconv.Itoa(i), Email: "u" + strconv.Itoa(i) + "@b.com"})
+	}
+	ms := &mockAdminUserService{ListFn: func() ([]*domain.User, error) { return users, nil }}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/users", ac.ListAll)
+
+	cases := []struct {
+		query    string
+		wantLen  int
+		wantPage string
+		wantSize string
+	}{
+		{"", 20, "1", "20"},                       // primeira página com o tamanho padrão
+		{"?page=3&page_size=10", 5, "3", "10"},    // última página parcial
+		{"?page=9&page_size=10", 0, "9", "10"},    // página além do fim
+		{"?page_size=1000", 25, "1", "100"},       // tamanho limitado a MaxPageSize
+		{"?page=abc&page_size=-1", 20, "1", "20"}, // valores inválidos assumem o padrão
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/admin/users"+tc.query, nil)
+		w := httptest.NewRecorder()
+
+		// Act: Executa a listagem paginada
+		r.ServeHTTP(w, req)
+
+		// Assert: Verifica o tamanho da página e os cabeçalhos de paginação
+		assert.Equal(t, http.StatusOK, w.Code, tc.query)
+		var body []map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), tc.query)
+		assert.Len(t, body, tc.wantLen, tc.query)
+		assert.Equal(t, "25", w.Header().Get(TotalCountHeader), tc.query)
+		assert.Equal(t, tc.wantPage, w.Header().Get(PageHeader), tc.query)
+		assert.Equal(t, tc.wantSize, w.Header().Get(PageSizeHeader), tc.query)
+	}
+	t.Log("[FIM] TestAdminController_ListAll_Pagination")
+}
+
 func TestAdminController_GetByID_Success(t *testing.T) {
 	t.Log("[INICIO] TestAdminController_GetByID_Success")
 
@@ -120,6 +250,46 @@ func TestAdminController_GetByID_NotFound(t *testing.T) {
 	t.Log("[FIM] TestAdminController_GetByID_NotFound")
 }
 
+func TestAdminController_GetByEmail(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_GetByEmail")
+
+	// Arrange: Configura o mock com um único usuário cadastrado
+	var lookups []string
+	ms := &mockAdminUserService{GetByEmailFn: func(email string) (*domain.User, error) {
+		lookups = append(lookups, email)
+		if email != "a@b.com" {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		return &domain.User{ID: "1", Email: email}, nil
+	}}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/users/by-email", ac.GetByEmail)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	// Act + Assert: encontrado, com o email normalizado como no cadastro
+	w := get("/admin/users/by-email?email=%20A@B.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "1", response["id"])
+
+	// Não encontrado
+	assert.Equal(t, http.StatusNotFound, get("/admin/users/by-email?email=x@y.com").Code)
+
+	// Parâmetro ausente não chega ao serviço
+	w = get("/admin/users/by-email")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "email")
+	assert.Equal(t, []string{"a@b.com", "x@y.com"}, lookups)
+
+	t.Log("[FIM] TestAdminController_GetByEmail")
+}
+
 func TestAdminController_Update_Success(t *testing.T) {
 	t.Log("[INICIO] TestAdminController_Update_Success")
 
@@ -188,6 +358,63 @@ func TestAdminController_Delete_Success(t *testing.T) {
 	t.Log("[FIM] TestAdminController_Delete_Success")
 }
 
+func TestAdminController_Delete_RecordsAudit(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Delete_RecordsAudit")
+
+	// Arrange: Mock que guarda a trilha e o ID do admin no contexto
+	trail := audit.NewMemoryStore(0)
+	ms := &mockAdminUserService{
+		DeleteFn: func(id string) error { return nil },
+		RecordAuditEventFn: func(actor, action, target string) error {
+			return trail.Record(&domain.AuditEvent{ActorID: actor, Action: action, TargetID: target, At: time.Now()})
+		},
+	}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.DELETE("/admin/users/:id", func(c *gin.Context) { c.Set("user_id", "admin-1") }, ac.Delete)
+	req := httptest.NewRequest("DELETE", "/admin/users/1", nil)
+	w := httptest.NewRecorder()
+
+	// Act: Executa a deleção
+	r.ServeHTTP(w, req)
+
+	// Assert: A deleção foi registrada com autor, ação e alvo
+	assert.Equal(t, http.StatusOK, w.Code)
+	events, total, err := trail.List(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "admin-1", events[0].ActorID)
+		assert.Equal(t, domain.AuditActionUserDeleted, events[0].Action)
+		assert.Equal(t, "1", events[0].TargetID)
+	}
+	t.Log("[FIM] TestAdminController_Delete_RecordsAudit")
+}
+
+func TestAdminController_Delete_NotFound_NoAudit(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Delete_NotFound_NoAudit")
+
+	// Arrange: Falha na deleção não deve gerar registro de auditoria
+	audited := false
+	ms := &mockAdminUserService{
+		DeleteFn:           func(id string) error { return pkgerrors.ErrUserNotFound },
+		RecordAuditEventFn: func(a, action, target string) error { audited = true; return nil },
+	}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.DELETE("/admin/users/:id", ac.Delete)
+	req := httptest.NewRequest("DELETE", "/admin/users/1", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.False(t, audited)
+	t.Log("[FIM] TestAdminController_Delete_NotFound_NoAudit")
+}
+
 func TestAdminController_Delete_NotFound(t *testing.T) {
 	t.Log("[INICIO] TestAdminController_Delete_NotFound")
 
@@ -206,3 +433,188 @@ func TestAdminController_Delete_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	t.Log("[FIM] TestAdminController_Delete_NotFound")
 }
+
+func TestAdminController_Update_StampsUpdatedBy(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Update_StampsUpdatedBy")
+
+	// Arrange: Simula o middleware de autenticação com o ID do admin no contexto
+	var updated *domain.User
+	ms := &mockAdminUserService{
+		GetByIDFn: func(id string) (*domain.User, error) {
+			return &domain.User{ID: id, Email: "a@b.com", CreatedBy: domain.ActorSelf}, nil
+		},
+		UpdateFn: func(u *domain.User) error { updated = u; return nil },
+	}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.PUT("/admin/users/:id", func(c *gin.Context) { c.Set("user_id", "admin-1") }, ac.Update)
+	body := map[string]interface{}{"name": "Novo"}
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest("PUT", "/admin/users/1", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição de atualização
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica que o ator foi registrado e exposto na resposta
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin-1", updated.UpdatedBy)
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "admin-1", response["updated_by"])
+	assert.Equal(t, domain.ActorSelf, response["created_by"])
+	t.Log("[FIM] TestAdminController_Update_StampsUpdatedBy")
+}
+
+func TestAdminController_Update_DeduplicatesRoles(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Update_DeduplicatesRoles")
+
+	// Arrange: Captura o usuário enviado ao serviço
+	var updated *domain.User
+	ms := &mockAdminUserService{
+		GetByIDFn: func(id string) (*domain.User, error) {
+			return &domain.User{ID: id, Email: "a@b.com", Roles: []string{domain.RoleUser}}, nil
+		},
+		UpdateFn: func(u *domain.User) error { updated = u; return nil },
+	}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.PUT("/admin/users/:id", ac.Update)
+	b, _ := json.Marshal(map[string]interface{}{"roles": []string{"admin", "admin", "user", "admin"}})
+	req := httptest.NewRequest("PUT", "/admin/users/1", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a atualização com papéis repetidos
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica que as repetições foram descartadas antes de persistir
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"admin", "user"}, updated.Roles)
+	t.Log("[FIM] TestAdminController_Update_DeduplicatesRoles")
+}
+
+func TestAdminController_Update_RejectsDuplicateRoles(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Update_RejectsDuplicateRoles")
+
+	// Arrange: Habilita a rejeição de papéis repetidos
+	updateCalled := false
+	ms := &mockAdminUserService{
+		GetByIDFn: func(id string) (*domain.User, error) { return &domain.User{ID: id, Email: "a@b.com"}, nil },
+		UpdateFn:  func(u *domain.User) error { updateCalled = true; return nil },
+	}
+	ac := NewAdminController(ms, WithDuplicateRolesRejected(true))
+	r := setupGinAdmin()
+	r.PUT("/admin/users/:id", ac.Update)
+	b, _ := json.Marshal(map[string]interface{}{"roles": []string{"admin", "admin"}})
+	req := httptest.NewRequest("PUT", "/admin/users/1", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a atualização com papéis repetidos
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica que a requisição foi recusada sem persistir
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "roles")
+	assert.False(t, updateCalled)
+	t.Log("[FIM] TestAdminController_Update_RejectsDuplicateRoles")
+}
+
+func TestAdminController_ListAll_CreatedRange(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_ListAll_CreatedRange")
+
+	// Arrange: Captura o intervalo repassado ao serviço
+	var gotFrom, gotTo time.Time
+	ms := &mockAdminUserService{ListCreatedBetweenFn: func(from, to time.Time) ([]*domain.User, error) {
+		gotFrom, gotTo = from, to
+		return []*domain.User{{ID: "1", Email: "a@b.com"}}, nil
+	}}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/users", ac.ListAll)
+	req := httptest.NewRequest("GET", "/admin/users?created_from=2024-01-01T10:00:00Z&created_to=2024-01-31", nil)
+	w := httptest.NewRecorder()
+
+	// Act: Executa a listagem filtrada
+	r.ServeHTTP(w, req)
+
+	// Assert: O created_to apenas com data inclui o dia inteiro
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), gotFrom)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), gotTo)
+	t.Log("[FIM] TestAdminController_ListAll_CreatedRange")
+}
+
+func TestAdminController_ListAll_InvalidCreatedRange(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_ListAll_InvalidCreatedRange")
+
+	// Arrange: Nenhuma chamada ao serviço é esperada
+	ms := &mockAdminUserService{}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/users", ac.ListAll)
+	req := httptest.NewRequest("GET", "/admin/users?created_from=01/02/2024", nil)
+	w := httptest.NewRecorder()
+
+	// Act: Executa a listagem com data em formato inválido
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica erro de validação no campo
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "created_from")
+	t.Log("[FIM] TestAdminController_ListAll_InvalidCreatedRange")
+}
+
+func TestAdminController_Stats_Error(t *testing.T) {
+	t.Log("[INICIO] TestAdminController_Stats_Error")
+
+	// Arrange: Configura o mock para falhar na contagem
+	ms := &mockAdminUserService{StatsFn: func() (*domain.UserStats, error) {
+		return nil, pkgerrors.ErrInternalServer
+	}}
+	ac := NewAdminController(ms)
+	r := setupGinAdmin()
+	r.GET("/admin/stats", ac.Stats)
+	req := httptest.NewRequest("GET", "/admin/stats", nil)
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição de estatísticas
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica que retorna erro 500
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	t.Log("[FIM] TestAdminController_Stats_Error")
+}
+
+func TestAuditController_List_Paginated(t *testing.T) {
+	t.Log("[INICIO] TestAuditController_List_Paginated")
+
+	// Arrange: Trilha com três eventos
+	trail := audit.NewMemoryStore(0)
+	for _, target := range []string{"u1", "u2", "u3"} {
+		_ = trail.Record(&domain.AuditEvent{ActorID: "admin-1", Action: domain.AuditActionUserUpdated, TargetID: target})
+	}
+	r := setupGinAdmin()
+	r.GET("/admin/audit", NewAuditController(trail).List)
+	req := httptest.NewRequest("GET", "/admin/audit?page=1&page_size=2", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert: Página com os dois mais recentes e o total
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Items []domain.AuditEvent `json:"items"`
+		Total int                 `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Total)
+	if assert.Len(t, resp.Items, 2) {
+		assert.Equal(t, "u3", resp.Items[0].TargetID)
+		assert.Equal(t, "u2", resp.Items[1].TargetID)
+	}
+	t.Log("[FIM] TestAuditController_List_Paginated")
+}
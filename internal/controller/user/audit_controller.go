@@ -0,0 +1,37 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// AuditController expõe a trilha de auditoria das ações administrativas
+type AuditController struct {
+	audit domain.AuditStore
+}
+
+func NewAuditController(audit domain.AuditStore) *AuditController {
+	return &AuditController{audit: audit}
+}
+
+// List retorna, paginados, os eventos de auditoria mais recentes
+func (ac *AuditController) List(ctx *gin.Context) {
+	page, pageSize := parsePagination(ctx)
+	events, total, err := ac.audit.List((page-1)*pageSize, pageSize)
+	if err != nil {
+		logging.With(ctx).Error("Falha ao listar trilha de auditoria: %v", err)
+		errors.GinHandleError(ctx, errors.ErrInternalServer.WithError(err))
+		return
+	}
+
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"items":     listOf(events),
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// requireUserID retorna o ID do usuário autenticado definido pelo middleware de
+// autenticação. Quando ausente, responde 401 e retorna false; o handler deve
+// apenas retornar em seguida.
+func requireUserID(ctx *gin.Context) (string, bool) {
+	if id, ok := ctx.Get("user_id"); ok {
+		if s, ok := id.(string); ok && s != "" {
+			return s, true
+		}
+	}
+
+	logging.With(ctx).Warning("Acesso sem usuário autenticado")
+	errors.GinHandleError(ctx, errors.ErrUnauthorized.WithMessage("Usuário não autenticado"))
+	ctx.Abort()
+	return "", false
+}
+
+// requireSelfOrAdmin permite o acesso quando o usuário autenticado é o próprio
+// targetID ou um administrador. Caso contrário responde 401/403 e retorna false.
+func requireSelfOrAdmin(ctx *gin.Context, targetID string) bool {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return false
+	}
+	if userID == targetID {
+		return true
+	}
+
+	roles, _ := ctx.Get("roles")
+	if r, ok := roles.([]string); ok && (&domain.User{Roles: r}).IsAdmin() {
+		return true
+	}
+
+	logging.With(ctx).Warning("Usuário %s tentou acessar recurso do usuário %s", userID, targetID)
+	errors.GinHandleError(ctx, errors.ErrForbidden.WithMessage("Acesso negado: recurso de outro usuário"))
+	ctx.Abort()
+	return false
+}
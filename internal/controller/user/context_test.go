@@ -0,0 +1,63 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Testa que o helper retorna o ID quando o middleware definiu o usuário
+func TestRequireUserID_Present(t *testing.T) {
+	t.Log("[INICIO] TestRequireUserID_Present")
+
+	// Arrange
+	r := setupGin()
+	var gotID string
+	r.GET("/me", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		id, ok := requireUserID(c)
+		if !ok {
+			return
+		}
+		gotID = id
+		c.Status(http.StatusNoContent)
+	})
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "user-1", gotID)
+	t.Log("[FIM] TestRequireUserID_Present")
+}
+
+// Testa que o helper responde 401 e interrompe o handler sem usuário no contexto
+func TestRequireUserID_Missing(t *testing.T) {
+	t.Log("[INICIO] TestRequireUserID_Missing")
+
+	// Arrange
+	r := setupGin()
+	reached := false
+	r.GET("/me", func(c *gin.Context) {
+		if _, ok := requireUserID(c); !ok {
+			return
+		}
+		reached = true
+	})
+
+	// Act
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/me", nil)
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.False(t, reached)
+	t.Log("[FIM] TestRequireUserID_Missing")
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// RefreshCookieName é o nome do cookie que transporta o refresh token
+const RefreshCookieName = "refresh_token"
+
+// CookieConfig define como os cookies de autenticação são emitidos
+type CookieConfig struct {
+	// ForceSecure marca os cookies como Secure em qualquer requisição (produção).
+	// Desabilitado, o flag é definido apenas quando a requisição chegou via HTTPS,
+	// diretamente ou segundo o cabeçalho X-Forwarded-Proto, permitindo dev em HTTP.
+	ForceSecure bool
+	Path        string // padrão "/users"
+
+	// RefreshToken entrega o refresh token apenas no cookie HttpOnly, fora do
+	// alcance de scripts da página; o corpo JSON traz só o access token
+	RefreshToken bool
+}
+
+// UserControllerOption configura opções do UserController
+type UserControllerOption func(*UserController)
+
+// WithCookieConfig define a configuração dos cookies de autenticação
+func WithCookieConfig(cfg CookieConfig) UserControllerOption {
+	return func(uc *UserController) {
+		uc.cookies = cfg
+	}
+}
+
+// isSecureRequest indica se a requisição chegou ao cliente via HTTPS
+func isSecureRequest(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")), "https")
+}
+
+// setRefreshCookie grava o refresh token em um cookie HttpOnly com SameSite=Strict
+func (uc *UserController) setRefreshCookie(ctx *gin.Context, token string, maxAge time.Duration) {
+	path := uc.cookies.Path
+	if path == "" {
+		path = "/users"
+	}
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     RefreshCookieName,
+		Value:    token,
+		Path:     path,
+		MaxAge:   int(maxAge / time.Second),
+		HttpOnly: true,
+		Secure:   uc.cookies.ForceSecure || isSecureRequest(ctx.Request),
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// clearRefreshCookie expira o cookie do refresh token no cliente
+func (uc *UserController) clearRefreshCookie(ctx *gin.Context) {
+	uc.setRefreshCookie(ctx, "", -time.Second)
+}
+
+// refreshTokenFrom retorna o refresh token enviado no corpo ou, na sua
+// ausência, o do cookie
+func refreshTokenFrom(ctx *gin.Context, bodyToken string) string {
+	if bodyToken != "" {
+		return bodyToken
+	}
+	token, _ := ctx.Cookie(RefreshCookieName)
+	return token
+}
+
+// bindRefreshRequest decodifica o corpo de refresh/logout, aceitando corpo
+// vazio para clientes que enviam o refresh token apenas no cookie
+func bindRefreshRequest(ctx *gin.Context, req any) error {
+	if err := ctx.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// respondWithTokens responde com o par de tokens. Com cookies habilitados, o
+// refresh token segue no cookie, com a mesma validade, e sai do corpo.
+func (uc *UserController) respondWithTokens(ctx *gin.Context, status int, accessToken, refreshToken string, extra gin.H) {
+	response := tokenResponse(accessToken, refreshToken)
+	if uc.cookies.RefreshToken && refreshToken != "" {
+		var maxAge time.Duration
+		if exp, err := auth.TokenExpiry(refreshToken); err == nil {
+			maxAge = time.Until(exp)
+		}
+		uc.setRefreshCookie(ctx, refreshToken, maxAge)
+		delete(response, "refresh_token")
+	}
+	for k, v := range extra {
+		response[k] = v
+	}
+	errors.GinRespondWithJSON(ctx, status, response)
+}
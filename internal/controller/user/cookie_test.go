@@ -0,0 +1,218 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func refreshCookieFor(t *testing.T, cfg CookieConfig, forwardedProto string) *http.Cookie {
+	uc := NewUserController(&mockUserService{}, WithCookieConfig(cfg))
+	r := setupGin()
+	r.GET("/cookie", func(c *gin.Context) {
+		uc.setRefreshCookie(c, "refresh", time.Hour)
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "http://localhost/cookie", nil)
+	if forwardedProto != "" {
+		req.Header.Set("X-Forwarded-Proto", forwardedProto)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	cookies := w.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		t.FailNow()
+	}
+	return cookies[0]
+}
+
+// Testa que em produção o cookie é sempre Secure, mesmo em HTTP
+func TestSetRefreshCookie_ForceSecure(t *testing.T) {
+	t.Log("[INICIO] TestSetRefreshCookie_ForceSecure")
+
+	cookie := refreshCookieFor(t, CookieConfig{ForceSecure: true}, "")
+
+	assert.True(t, cookie.Secure)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	assert.Equal(t, "/users", cookie.Path)
+	t.Log("[FIM] TestSetRefreshCookie_ForceSecure")
+}
+
+// Testa que em dev o flag Secure acompanha o protocolo da requisição
+func TestSetRefreshCookie_AutoDetect(t *testing.T) {
+	t.Log("[INICIO] TestSetRefreshCookie_AutoDetect")
+
+	assert.False(t, refreshCookieFor(t, CookieConfig{}, "").Secure, "HTTP em dev não deve marcar Secure")
+	assert.True(t, refreshCookieFor(t, CookieConfig{}, "https").Secure, "HTTPS via proxy deve marcar Secure")
+	t.Log("[FIM] TestSetRefreshCookie_AutoDetect")
+}
+
+func newTokenRouter(cfg CookieConfig, ms *mockUserService) *gin.Engine {
+	uc := NewUserController(ms, WithCookieConfig(cfg))
+	r := setupGin()
+	r.POST("/users/login", uc.Login)
+	r.POST("/users/refresh", uc.RefreshToken)
+	r.POST("/users/logout", uc.Logout)
+	return r
+}
+
+func postJSON(r *gin.Engine, path string, body any, cookie *http.Cookie) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest("POST", path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func findRefreshCookie(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == RefreshCookieName {
+			return c
+		}
+	}
+	return nil
+}
+
+// Testa que, no modo padrão, os tokens seguem no corpo e nenhum cookie é emitido
+func TestLogin_BodyMode(t *testing.T) {
+	t.Log("[INICIO] TestLogin_BodyMode")
+
+	ms := &mockUserService{
+		AuthenticateFn: func(string, string) (string, string, error) { return "access", "refresh", nil },
+	}
+	r := newTokenRouter(CookieConfig{}, ms)
+
+	w := postJSON(r, "/users/login", gin.H{"email": "a@b.com", "password": "123"}, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "access", resp["token"])
+	assert.Equal(t, "refresh", resp["refresh_token"])
+	assert.Nil(t, findRefreshCookie(w))
+	t.Log("[FIM] TestLogin_BodyMode")
+}
+
+// Testa que, no modo cookie, o refresh token sai do corpo e vai para um cookie HttpOnly
+func TestLogin_CookieMode(t *testing.T) {
+	t.Log("[INICIO] TestLogin_CookieMode")
+
+	ms := &mockUserService{
+		AuthenticateFn: func(string, string) (string, string, error) { return "access", "refresh", nil },
+	}
+	r := newTokenRouter(CookieConfig{RefreshToken: true, ForceSecure: true}, ms)
+
+	w := postJSON(r, "/users/login", gin.H{"email": "a@b.com", "password": "123"}, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "access", resp["token"])
+	assert.NotContains(t, resp, "refresh_token")
+
+	cookie := findRefreshCookie(w)
+	if assert.NotNil(t, cookie) {
+		assert.Equal(t, "refresh", cookie.Value)
+		assert.True(t, cookie.HttpOnly)
+		assert.True(t, cookie.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	}
+	t.Log("[FIM] TestLogin_CookieMode")
+}
+
+// Testa que o refresh usa o cookie quando o corpo não traz o refresh token
+func TestRefreshToken_FromCookie(t *testing.T) {
+	t.Log("[INICIO] TestRefreshToken_FromCookie")
+
+	var received string
+	ms := &mockUserService{
+		RefreshTokensFn: func(token string) (string, string, error) {
+			received = token
+			return "new-access", "new-refresh", nil
+		},
+	}
+	r := newTokenRouter(CookieConfig{RefreshToken: true}, ms)
+
+	// Sem corpo algum, apenas o cookie
+	w := postJSON(r, "/users/refresh", nil, &http.Cookie{Name: RefreshCookieName, Value: "old-refresh"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "old-refresh", received)
+	assert.NotContains(t, w.Body.String(), "new-refresh")
+	if cookie := findRefreshCookie(w); assert.NotNil(t, cookie) {
+		assert.Equal(t, "new-refresh", cookie.Value)
+	}
+
+	// O campo do corpo tem precedência sobre o cookie
+	postJSON(r, "/users/refresh", gin.H{"refresh_token": "body-refresh"}, &http.Cookie{Name: RefreshCookieName, Value: "old-refresh"})
+	assert.Equal(t, "body-refresh", received)
+
+	// Sem corpo e sem cookie, a requisição continua inválida
+	w = postJSON(r, "/users/refresh", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	t.Log("[FIM] TestRefreshToken_FromCookie")
+}
+
+// Testa que o logout revoga o refresh token do cookie e expira o cookie
+func TestLogout_FromCookie(t *testing.T) {
+	t.Log("[INICIO] TestLogout_FromCookie")
+
+	var revoked string
+	ms := &mockUserService{
+		RevokeRefreshTokenFn: func(token string) error {
+			revoked = token
+			return nil
+		},
+	}
+	r := newTokenRouter(CookieConfig{RefreshToken: true}, ms)
+
+	w := postJSON(r, "/users/logout", nil, &http.Cookie{Name: RefreshCookieName, Value: "refresh"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "refresh", revoked)
+	if cookie := findRefreshCookie(w); assert.NotNil(t, cookie) {
+		assert.Empty(t, cookie.Value)
+		assert.Less(t, cookie.MaxAge, 0)
+	}
+	t.Log("[FIM] TestLogout_FromCookie")
+}
+
+// Testa que o registro com login automático também respeita o modo cookie
+func TestRegister_AutoLogin_CookieMode(t *testing.T) {
+	t.Log("[INICIO] TestRegister_AutoLogin_CookieMode")
+
+	ms := &mockUserService{
+		RegisterAndLoginFn: func(*domain.User) (domain.TokenPair, error) {
+			return domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+		},
+	}
+	uc := NewUserController(ms, WithCookieConfig(CookieConfig{RefreshToken: true}))
+	r := setupGin()
+	r.POST("/users/register", uc.Register)
+
+	w := postJSON(r, "/users/register", gin.H{
+		"email": "a@b.com", "password": "Senha@123", "name": "Ana", "auto_login": true,
+	}, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), `"refresh_token"`)
+	assert.Contains(t, w.Body.String(), `"user"`)
+	if cookie := findRefreshCookie(w); assert.NotNil(t, cookie) {
+		assert.Equal(t, "refresh", cookie.Value)
+	}
+	t.Log("[FIM] TestRegister_AutoLogin_CookieMode")
+}
@@ -0,0 +1,29 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// userETag deriva a ETag do ID e do UpdatedAt do usuário; qualquer atualização
+// gera uma nova ETag
+func userETag(u *domain.User) string {
+	sum := sha256.Sum256([]byte(u.ID + ":" + strconv.FormatInt(u.UpdatedAt.UnixNano(), 10)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches indica se o cabeçalho If-None-Match contém a ETag informada.
+// Aceita listas separadas por vírgula, o curinga "*" e ETags fracas (W/).
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package user
+
+// listOf garante que uma listagem vazia seja serializada como [] e não como
+// null, independentemente de o serviço ou o repositório retornarem nil ou um
+// slice vazio
+func listOf[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultPageSize é o tamanho de página usado quando ?page_size= não é informado
+	DefaultPageSize = 20
+	// MaxPageSize é o maior tamanho de página aceito
+	MaxPageSize = 100
+)
+
+// parsePagination lê ?page= (a partir de 1) e ?page_size= da query, aplicando os
+// padrões a valores ausentes ou inválidos e limitando o tamanho a MaxPageSize
+func parsePagination(ctx *gin.Context) (page, pageSize int) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err = strconv.Atoi(ctx.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	return page, min(pageSize, MaxPageSize)
+}
@@ -2,85 +2,184 @@ package user
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
-	"github.com/lucas-de-lima/go-auth-system/internal/service"
 	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/lucas-de-lima/go-auth-system/pkg/validator"
 )
 
 type UserController struct {
 	userService domain.UserService
+	cookies     CookieConfig
+
+	reservedLocalParts []string
+
+	requirePasswordConfirmation bool
+}
+
+func NewUserController(userService domain.UserService, opts ...UserControllerOption) *UserController {
+	uc := &UserController{userService: userService}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// WithReservedLocalParts impede o auto-registro de emails cuja parte local
+// (ex.: "admin" em admin@dominio) pertença à lista informada
+func WithReservedLocalParts(parts []string) UserControllerOption {
+	return func(uc *UserController) {
+		uc.reservedLocalParts = parts
+	}
 }
 
-func NewUserController(userService domain.UserService) *UserController {
-	return &UserController{userService: userService}
+// WithPasswordConfirmation exige password_confirmation no registro. Sem a
+// opção, o campo é opcional e só é comparado com a senha quando enviado.
+func WithPasswordConfirmation(required bool) UserControllerOption {
+	return func(uc *UserController) {
+		uc.requirePasswordConfirmation = required
+	}
 }
 
 func (uc *UserController) Register(ctx *gin.Context) {
 	var user domain.UserRequest
 
 	if err := ctx.ShouldBindJSON(&user); err != nil {
-		logging.Error("[%s] Falha ao decodificar corpo da requisição de registro: %v", ctx.ClientIP(), err)
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de registro: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
 
-	// Validação básica
-	if user.Email == "" || user.Password == "" {
-		details := []errors.ValidationDetail{}
-
-		if user.Email == "" {
-			details = append(details, errors.ValidationDetail{Field: "email", Message: "Email é obrigatório"})
+	fieldErrs := validator.ValidateStruct(user)
+	if uc.requirePasswordConfirmation && user.PasswordConfirmation == "" {
+		fieldErrs = append(fieldErrs, validator.ValidationError{Field: "password_confirmation", Message: "Confirmação de senha é obrigatória"})
+	}
+	if len(fieldErrs) > 0 {
+		details := make([]errors.ValidationDetail, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			details = append(details, errors.ValidationDetail{Field: fe.Field, Message: fe.Message})
 		}
+		logging.With(ctx).Warning("Tentativa de registro com dados inválidos: %+v", details)
+		errors.GinHandleError(ctx, errors.NewValidationError("Dados de registro inválidos", details))
+		return
+	}
 
-		if user.Password == "" {
-			details = append(details, errors.ValidationDetail{Field: "password", Message: "Senha é obrigatória"})
-		}
+	if validator.IsReservedEmail(user.Email, uc.reservedLocalParts) {
+		logging.With(ctx).Warning("Tentativa de registro com email reservado: %s", user.Email)
+		errors.GinHandleError(ctx, errors.NewValidationError("Email indisponível para registro", []errors.ValidationDetail{
+			{Field: "email", Message: "Este email é reservado"},
+		}))
+		return
+	}
 
-		logging.Warning("[%s] Tentativa de registro com campos obrigatórios faltando: %+v", ctx.ClientIP(), details)
-		validationErr := errors.NewValidationError("Campos obrigatórios não preenchidos", details)
-		errors.GinHandleError(ctx, validationErr)
+	if user.Username != "" && !validator.IsUsername(user.Username) {
+		logging.With(ctx).Warning("Tentativa de registro com username inválido: %s", user.Username)
+		errors.GinHandleError(ctx, errors.NewValidationError("Username inválido", []errors.ValidationDetail{
+			{Field: "username", Message: "Username deve ter de 3 a 32 caracteres entre letras, números, '.', '_' e '-'"},
+		}))
 		return
 	}
 
-	newUser := user.FromUserRequest()
-	err := uc.userService.Create(newUser)
+	newUser, err := domain.NewUser(user.Email, user.Password, user.Name, nil)
+	if err != nil {
+		var invalid *domain.UserValidationError
+		if errors.As(err, &invalid) {
+			logging.With(ctx).Warning("Tentativa de registro com dados inválidos: %v", err)
+			errors.GinHandleError(ctx, errors.NewValidationError("Dados de registro inválidos", []errors.ValidationDetail{
+				{Field: invalid.Field, Message: invalid.Message},
+			}))
+			return
+		}
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+	newUser.Username = user.Username
+	newUser.OrgID = user.OrgID
+	newUser.CreatedBy = domain.ActorSelf
+
+	var tokens domain.TokenPair
+	if user.AutoLogin {
+		tokens, err = uc.userService.RegisterAndLogin(newUser)
+	} else {
+		err = uc.userService.Create(newUser)
+	}
 	if err != nil {
-		logging.Error("[%s] Falha ao registrar usuário %s: %v", ctx.ClientIP(), newUser.Email, err)
+		logging.With(ctx).Error("Falha ao registrar usuário %s: %v", newUser.Email, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Novo usuário registrado: %s (id: %s)", ctx.ClientIP(), newUser.Email, newUser.ID)
-	errors.GinRespondWithJSON(ctx, http.StatusCreated, newUser.ToUserResponse())
+	logging.With(ctx).Info("Novo usuário registrado: %s (id: %s)", newUser.Email, newUser.ID)
+	if tokens.AccessToken == "" {
+		errors.GinRespondWithJSON(ctx, http.StatusCreated, newUser.ToUserResponse())
+		return
+	}
+
+	// Com login automático, os tokens acompanham o usuário criado
+	uc.respondWithTokens(ctx, http.StatusCreated, tokens.AccessToken, tokens.RefreshToken, gin.H{
+		"user": newUser.ToUserResponse(),
+	})
 }
 
 func (uc *UserController) Login(ctx *gin.Context) {
 	var req struct {
 		Email    string `json:"email"`
+		Username string `json:"username"` // alternativa ao email, quando habilitada
 		Password string `json:"password"`
+		OrgID    string `json:"org_id"` // organização da conta, no modo multi-tenant
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		logging.Error("[%s] Falha ao decodificar corpo da requisição de login: %v", ctx.ClientIP(), err)
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de login: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
 
-	accessToken, refreshToken, err := uc.userService.Authenticate(req.Email, req.Password)
+	identifier := req.Email
+	if identifier == "" {
+		identifier = req.Username
+	}
+
+	// Validação básica
+	if identifier == "" || req.Password == "" {
+		details := []errors.ValidationDetail{}
+
+		if identifier == "" {
+			details = append(details, errors.ValidationDetail{Field: "email", Message: "Email é obrigatório"})
+		}
+
+		if req.Password == "" {
+			details = append(details, errors.ValidationDetail{Field: "password", Message: "Senha é obrigatória"})
+		}
+
+		logging.With(ctx).Warning("Tentativa de login com campos obrigatórios faltando: %+v", details)
+		validationErr := errors.NewValidationError("Campos obrigatórios não preenchidos", details)
+		errors.GinHandleError(ctx, validationErr)
+		return
+	}
+
+	accessToken, refreshToken, err := uc.userService.AuthenticateInOrg(req.OrgID, identifier, req.Password)
 	if err != nil {
-		logging.Warning("[%s] Tentativa de login falhou para: %s (%v)", ctx.ClientIP(), req.Email, err)
+		logging.With(ctx).Warning("Tentativa de login falhou para: %s (%v)", identifier, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Login realizado: %s", ctx.ClientIP(), req.Email)
-	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
-		"token":         accessToken,
-		"refresh_token": refreshToken,
-	})
+	if auth.IsPasswordChangeToken(accessToken) {
+		logging.With(ctx).Info("Login realizado com troca de senha obrigatória: %s", identifier)
+		errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+			"token":                accessToken,
+			"must_change_password": true,
+		})
+		return
+	}
+
+	logging.With(ctx).Info("Login realizado: %s", identifier)
+	uc.respondWithTokens(ctx, http.StatusOK, accessToken, refreshToken, nil)
 }
 
 func (uc *UserController) Logout(ctx *gin.Context) {
@@ -88,20 +187,28 @@ func (uc *UserController) Logout(ctx *gin.Context) {
 		RefreshToken string `json:"refresh_token"`
 	}
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		logging.Error("[%s] Falha ao decodificar corpo da requisição de logout: %v", ctx.ClientIP(), err)
+	if err := bindRefreshRequest(ctx, &req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de logout: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
 
+	req.RefreshToken = refreshTokenFrom(ctx, req.RefreshToken)
 	if req.RefreshToken == "" {
-		logging.Warning("[%s] Tentativa de logout sem refresh token", ctx.ClientIP())
+		logging.With(ctx).Warning("Tentativa de logout sem refresh token")
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("Token de atualização não fornecido"))
 		return
 	}
 
-	service.BlacklistRefreshToken(req.RefreshToken)
-	logging.Info("[%s] Logout realizado (rota: %s)", ctx.ClientIP(), ctx.FullPath())
+	if err := uc.userService.RevokeRefreshToken(req.RefreshToken); err != nil {
+		logging.With(ctx).Error("Falha ao revogar refresh token no logout: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+	if uc.cookies.RefreshToken {
+		uc.clearRefreshCookie(ctx)
+	}
+	logging.With(ctx).Info("Logout realizado")
 	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
 		"message": "Logout realizado com sucesso",
 	})
@@ -112,49 +219,404 @@ func (uc *UserController) RefreshToken(ctx *gin.Context) {
 		RefreshToken string `json:"refresh_token"`
 	}
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		logging.Error("[%s] Falha ao decodificar corpo da requisição de refresh: %v", ctx.ClientIP(), err)
+	if err := bindRefreshRequest(ctx, &req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de refresh: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
 
+	req.RefreshToken = refreshTokenFrom(ctx, req.RefreshToken)
 	if req.RefreshToken == "" {
-		logging.Warning("[%s] Tentativa de refresh sem refresh token", ctx.ClientIP())
+		logging.With(ctx).Warning("Tentativa de refresh sem refresh token")
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("Token de atualização não fornecido"))
 		return
 	}
 
 	accessToken, newRefreshToken, err := uc.userService.RefreshTokens(req.RefreshToken)
 	if err != nil {
-		logging.Warning("[%s] Tentativa de refresh token falhou: %v", ctx.ClientIP(), err)
+		logging.With(ctx).Warning("Tentativa de refresh token falhou: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Refresh token bem-sucedido")
+	uc.respondWithTokens(ctx, http.StatusOK, accessToken, newRefreshToken, nil)
+}
+
+// InitialRefreshToken emite o primeiro refresh token ao usuário autenticado,
+// quando o login entrega apenas o access token
+func (uc *UserController) InitialRefreshToken(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	tokenID := ctx.GetString("token_id")
+	refreshToken, err := uc.userService.IssueInitialRefreshToken(userID, tokenID)
+	if err != nil {
+		logging.With(ctx).Warning("Falha ao emitir o refresh token inicial do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Refresh token inicial emitido para o usuário %s", userID)
+	uc.respondWithTokens(ctx, http.StatusOK, "", refreshToken, nil)
+}
+
+// ListSessions lista as sessões ativas do usuário autenticado
+func (uc *UserController) ListSessions(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	sessions, err := uc.userService.ListSessions(userID)
+	if err != nil {
+		logging.With(ctx).Warning("Falha ao listar sessões do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	errors.GinRespondWithJSON(ctx, http.StatusOK, listOf(sessions))
+}
+
+// RotateSessions encerra as demais sessões do usuário autenticado e emite um
+// novo par de tokens para a sessão atual, identificada pelo refresh token
+func (uc *UserController) RotateSessions(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := bindRefreshRequest(ctx, &req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de rotação de sessões: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+	req.RefreshToken = refreshTokenFrom(ctx, req.RefreshToken)
+	if req.RefreshToken == "" {
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("Token de atualização não fornecido"))
+		return
+	}
+
+	accessToken, refreshToken, err := uc.userService.RotateSessions(userID, req.RefreshToken)
+	if err != nil {
+		logging.With(ctx).Warning("Rotação de sessões do usuário %s falhou: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Sessões rotacionadas para o usuário %s", userID)
+	uc.respondWithTokens(ctx, http.StatusOK, accessToken, refreshToken, nil)
+}
+
+// LogoutAll revoga todos os refresh tokens do usuário ("sair de todos os
+// dispositivos"); permitido ao próprio usuário e a administradores
+func (uc *UserController) LogoutAll(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	if !requireSelfOrAdmin(ctx, userID) {
+		return
+	}
+
+	if err := uc.userService.RevokeAllTokens(userID); err != nil {
+		logging.With(ctx).Warning("Falha ao revogar os tokens do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Todos os refresh tokens do usuário %s revogados", userID)
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{"message": "Todos os dispositivos foram desconectados"})
+}
+
+// tokenResponse monta a resposta de login/refresh, incluindo a validade do novo
+// refresh token para que o cliente agende a próxima renovação. Tokens não
+// emitidos, como o refresh token adiado do login, ficam fora da resposta.
+func tokenResponse(accessToken, refreshToken string) gin.H {
+	response := gin.H{}
+	if accessToken != "" {
+		response["token"] = accessToken
+	}
+	if refreshToken == "" {
+		return response
+	}
+	response["refresh_token"] = refreshToken
+	if exp, err := auth.TokenExpiry(refreshToken); err == nil {
+		response["refresh_expires_at"] = exp.UTC().Format(time.RFC3339)
+		response["refresh_expires_in"] = max(int64(time.Until(exp)/time.Second), 0)
+	}
+	return response
+}
+
+// ChangePassword troca a senha do próprio usuário mediante a senha atual
+func (uc *UserController) ChangePassword(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	callerID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	if callerID != userID {
+		logging.With(ctx).Warning("Usuário %s tentou trocar a senha do usuário %s", callerID, userID)
+		errors.GinHandleError(ctx, errors.ErrForbidden.WithMessage("Acesso negado: recurso de outro usuário"))
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de troca de senha: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		details := []errors.ValidationDetail{}
+
+		if req.CurrentPassword == "" {
+			details = append(details, errors.ValidationDetail{Field: "current_password", Message: "Senha atual é obrigatória"})
+		}
+
+		if req.NewPassword == "" {
+			details = append(details, errors.ValidationDetail{Field: "new_password", Message: "Nova senha é obrigatória"})
+		}
+
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", details))
+		return
+	}
+
+	if err := uc.userService.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		logging.With(ctx).Warning("Falha ao trocar senha do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Senha alterada: id=%s", userID)
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"message": "Senha alterada com sucesso",
+	})
+}
+
+// RequestDeletion emite o token que confirma a exclusão da própria conta
+func (uc *UserController) RequestDeletion(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	callerID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	if callerID != userID {
+		logging.With(ctx).Warning("Usuário %s tentou solicitar a exclusão do usuário %s", callerID, userID)
+		errors.GinHandleError(ctx, errors.ErrForbidden.WithMessage("Acesso negado: recurso de outro usuário"))
+		return
+	}
+
+	token, err := uc.userService.RequestDeletion(userID)
+	if err != nil {
+		logging.With(ctx).Warning("Falha ao solicitar exclusão do usuário %s: %v", userID, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Refresh token bem-sucedido (rota: %s)", ctx.ClientIP(), ctx.FullPath())
 	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
-		"token":         accessToken,
-		"refresh_token": newRefreshToken,
+		"message":            "Confirme a exclusão enviando o token em /users/" + userID + "/delete/confirm",
+		"confirmation_token": token,
 	})
 }
 
-// GetByID busca um usuário pelo ID
+// ConfirmDeletion exclui a própria conta mediante o token emitido por RequestDeletion
+func (uc *UserController) ConfirmDeletion(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	callerID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+	if callerID != userID {
+		logging.With(ctx).Warning("Usuário %s tentou confirmar a exclusão do usuário %s", callerID, userID)
+		errors.GinHandleError(ctx, errors.ErrForbidden.WithMessage("Acesso negado: recurso de outro usuário"))
+		return
+	}
+
+	var req struct {
+		Token string `json:"confirmation_token"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da confirmação de exclusão: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+	if req.Token == "" {
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", []errors.ValidationDetail{
+			{Field: "confirmation_token", Message: "Token de confirmação é obrigatório"},
+		}))
+		return
+	}
+
+	if err := uc.userService.ConfirmDeletion(userID, req.Token); err != nil {
+		logging.With(ctx).Warning("Falha ao confirmar exclusão do usuário %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Conta excluída: id=%s", userID)
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"message": "Conta excluída com sucesso",
+	})
+}
+
+// RequestPasswordReset inicia a redefinição de senha para o email informado. A
+// resposta é sempre a mesma, exista ou não uma conta com o email.
+func (uc *UserController) RequestPasswordReset(ctx *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+		OrgID string `json:"org_id"` // organização da conta, no modo multi-tenant
+
+		RedirectURI string `json:"redirect_uri"` // destino do link, dentro da allowlist
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de redefinição de senha: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	if req.Email == "" {
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", []errors.ValidationDetail{
+			{Field: "email", Message: "Email é obrigatório"},
+		}))
+		return
+	}
+
+	if err := uc.userService.RequestPasswordReset(domain.PasswordResetRequest{
+		OrgID:       req.OrgID,
+		Email:       req.Email,
+		RedirectURI: req.RedirectURI,
+	}); err != nil {
+		logging.With(ctx).Error("Falha ao solicitar redefinição de senha: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Redefinição de senha solicitada")
+	errors.GinRespondWithJSON(ctx, http.StatusAccepted, gin.H{
+		"message": "Se o email estiver cadastrado, as instruções de redefinição serão enviadas",
+	})
+}
+
+// ConfirmPasswordReset troca a senha mediante um token de redefinição válido e
+// ainda não utilizado
+func (uc *UserController) ConfirmPasswordReset(ctx *gin.Context) {
+	var req struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		logging.With(ctx).Error("Falha ao decodificar corpo da confirmação de redefinição de senha: %v", err)
+		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
+		return
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		details := []errors.ValidationDetail{}
+
+		if req.Token == "" {
+			details = append(details, errors.ValidationDetail{Field: "token", Message: "Token de redefinição é obrigatório"})
+		}
+
+		if req.NewPassword == "" {
+			details = append(details, errors.ValidationDetail{Field: "new_password", Message: "Nova senha é obrigatória"})
+		}
+
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", details))
+		return
+	}
+
+	if err := uc.userService.ConfirmPasswordReset(req.Token, req.NewPassword); err != nil {
+		logging.With(ctx).Warning("Falha ao confirmar redefinição de senha: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Senha redefinida por token")
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"message": "Senha redefinida com sucesso",
+	})
+}
+
+// VerifyEmail confirma o email da conta com o token de verificação recebido no
+// link de cadastro (GET /users/verify?token=)
+func (uc *UserController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		errors.GinHandleError(ctx, errors.NewValidationError("Campos obrigatórios não preenchidos", []errors.ValidationDetail{
+			{Field: "token", Message: "Token de verificação é obrigatório"},
+		}))
+		return
+	}
+
+	if err := uc.userService.VerifyEmail(token); err != nil {
+		logging.With(ctx).Warning("Falha ao verificar email: %v", err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	logging.With(ctx).Info("Email verificado por token")
+	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
+		"message": "Email verificado com sucesso",
+	})
+}
+
+// Me retorna o usuário autenticado, identificado pelo user_id do token
+func (uc *UserController) Me(ctx *gin.Context) {
+	userID, ok := requireUserID(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := uc.userService.GetByID(userID)
+	if err != nil {
+		logging.With(ctx).Warning("Falha ao buscar o usuário autenticado %s: %v", userID, err)
+		errors.GinHandleError(ctx, err)
+		return
+	}
+
+	errors.GinRespondWithJSON(ctx, http.StatusOK, user.ToUserResponse())
+}
+
+// GetByID busca um usuário pelo ID, respondendo com ETag e 304 quando o
+// If-None-Match corresponde à versão atual. Apenas o próprio usuário ou um
+// administrador pode consultá-lo.
 func (uc *UserController) GetByID(ctx *gin.Context) {
 	userID := ctx.Param("id")
 	if userID == "" {
-		logging.Warning("[%s] Tentativa de busca de usuário sem ID", ctx.ClientIP())
+		logging.With(ctx).Warning("Tentativa de busca de usuário sem ID")
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("ID do usuário não fornecido"))
 		return
 	}
+	if !requireSelfOrAdmin(ctx, userID) {
+		return
+	}
 
 	user, err := uc.userService.GetByID(userID)
 	if err != nil {
-		logging.Warning("[%s] Falha ao buscar usuário por ID %s: %v", ctx.ClientIP(), userID, err)
+		logging.With(ctx).Warning("Falha ao buscar usuário por ID %s: %v", userID, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Usuário consultado: id=%s", ctx.ClientIP(), userID)
+	// Clientes que fazem polling reenviam a ETag e recebem 304 sem corpo
+	etag := userETag(user)
+	ctx.Header("ETag", etag)
+	if match := ctx.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	logging.With(ctx).Info("Usuário consultado: id=%s", userID)
 	errors.GinRespondWithJSON(ctx, http.StatusOK, user.ToUserResponse())
 }
 
@@ -162,7 +624,7 @@ func (uc *UserController) GetByID(ctx *gin.Context) {
 func (uc *UserController) Update(ctx *gin.Context) {
 	userID := ctx.Param("id")
 	if userID == "" {
-		logging.Warning("[%s] Tentativa de atualização sem ID", ctx.ClientIP())
+		logging.With(ctx).Warning("Tentativa de atualização sem ID")
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("ID do usuário não fornecido"))
 		return
 	}
@@ -173,33 +635,34 @@ func (uc *UserController) Update(ctx *gin.Context) {
 	}
 
 	if err := ctx.ShouldBindJSON(&updateData); err != nil {
-		logging.Error("[%s] Falha ao decodificar corpo da requisição de update: %v", ctx.ClientIP(), err)
+		logging.With(ctx).Error("Falha ao decodificar corpo da requisição de update: %v", err)
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithError(err))
 		return
 	}
 
 	currentUser, err := uc.userService.GetByID(userID)
 	if err != nil {
-		logging.Warning("[%s] Falha ao buscar usuário para atualização: %v", ctx.ClientIP(), err)
+		logging.With(ctx).Warning("Falha ao buscar usuário para atualização: %v", err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
 	if updateData.Email != "" {
-		currentUser.Email = updateData.Email
+		currentUser.Email = domain.NormalizeEmail(updateData.Email)
 	}
 	if updateData.Name != "" {
 		currentUser.Name = updateData.Name
 	}
+	currentUser.UpdatedBy = domain.ActorSelf
 
 	err = uc.userService.Update(currentUser)
 	if err != nil {
-		logging.Error("[%s] Falha ao atualizar usuário %s: %v", ctx.ClientIP(), userID, err)
+		logging.With(ctx).Error("Falha ao atualizar usuário %s: %v", userID, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Usuário atualizado: id=%s", ctx.ClientIP(), userID)
+	logging.With(ctx).Info("Usuário atualizado: id=%s", userID)
 	errors.GinRespondWithJSON(ctx, http.StatusOK, currentUser.ToUserResponse())
 }
 
@@ -207,19 +670,19 @@ func (uc *UserController) Update(ctx *gin.Context) {
 func (uc *UserController) Delete(ctx *gin.Context) {
 	userID := ctx.Param("id")
 	if userID == "" {
-		logging.Warning("[%s] Tentativa de deleção sem ID", ctx.ClientIP())
+		logging.With(ctx).Warning("Tentativa de deleção sem ID")
 		errors.GinHandleError(ctx, errors.ErrBadRequest.WithMessage("ID do usuário não fornecido"))
 		return
 	}
 
 	err := uc.userService.Delete(userID)
 	if err != nil {
-		logging.Error("[%s] Falha ao deletar usuário %s: %v", ctx.ClientIP(), userID, err)
+		logging.With(ctx).Error("Falha ao deletar usuário %s: %v", userID, err)
 		errors.GinHandleError(ctx, err)
 		return
 	}
 
-	logging.Info("[%s] Usuário deletado: id=%s", ctx.ClientIP(), userID)
+	logging.With(ctx).Info("Usuário deletado: id=%s", userID)
 	errors.GinRespondWithJSON(ctx, http.StatusOK, gin.H{
 		"message": "Usuário deletado com sucesso",
 	})
@@ -6,46 +6,176 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockUserService struct {
-	CreateFn        func(*domain.User) error
-	AuthenticateFn  func(string, string) (string, string, error)
-	RefreshTokensFn func(string) (string, string, error)
-	GetByIDFn       func(string) (*domain.User, error)
-	UpdateFn        func(*domain.User) error
-	DeleteFn        func(string) error
-	GetByEmailFn    func(string) (*domain.User, error)
-	ListFn          func() ([]*domain.User, error)
+	CreateFn           func(*domain.User) error
+	RegisterAndLoginFn func(*domain.User) (domain.TokenPair, error)
+	AuthenticateFn     func(string, string) (string, string, error)
+	RefreshTokensFn    func(string) (string, string, error)
+	GetByIDFn          func(string) (*domain.User, error)
+	UpdateFn           func(*domain.User) error
+	UpdateFieldsFn     func(string, map[string]any) error
+	ChangePasswordFn   func(string, string, string) error
+	DeleteFn           func(string) error
+	GetByEmailFn       func(string) (*domain.User, error)
+	ListFn             func() ([]*domain.User, error)
+
+	RevokeRefreshTokenFn func(string) error
+	RotateSessionsFn     func(string, string) (string, string, error)
+	RevokeAllTokensFn    func(string) error
+
+	RequestPasswordResetFn func(domain.PasswordResetRequest) error
+	ConfirmPasswordResetFn func(string, string) error
+	VerifyEmailFn          func(string) error
+	ListSessionsFn         func(string) ([]*domain.Session, error)
+
+	RequestDeletionFn func(string) (string, error)
+	ConfirmDeletionFn func(string, string) error
+
+	IssueInitialRefreshTokenFn func(string, string) (string, error)
+}
+
+func (m *mockUserService) RequestDeletion(userID string) (string, error) {
+	if m.RequestDeletionFn != nil {
+		return m.RequestDeletionFn(userID)
+	}
+	return "", nil
+}
+
+func (m *mockUserService) RecordAuditEvent(actorID, action, targetID string) error {
+	return nil
+}
+
+func (m *mockUserService) ConfirmDeletion(userID, token string) error {
+	if m.ConfirmDeletionFn != nil {
+		return m.ConfirmDeletionFn(userID, token)
+	}
+	return nil
+}
+
+func (m *mockUserService) ListSessions(userID string) ([]*domain.Session, error) {
+	if m.ListSessionsFn != nil {
+		return m.ListSessionsFn(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserService) RotateSessions(id, t string) (string, string, error) {
+	if m.RotateSessionsFn != nil {
+		return m.RotateSessionsFn(id, t)
+	}
+	return "", "", nil
+}
+
+func (m *mockUserService) RevokeAllTokens(id string) error {
+	if m.RevokeAllTokensFn != nil {
+		return m.RevokeAllTokensFn(id)
+	}
+	return nil
+}
+
+func (m *mockUserService) RequestPasswordReset(req domain.PasswordResetRequest) error {
+	if m.RequestPasswordResetFn != nil {
+		return m.RequestPasswordResetFn(req)
+	}
+	return nil
+}
+
+func (m *mockUserService) ConfirmPasswordReset(token, newPassword string) error {
+	if m.ConfirmPasswordResetFn != nil {
+		return m.ConfirmPasswordResetFn(token, newPassword)
+	}
+	return nil
+}
+
+func (m *mockUserService) VerifyEmail(token string) error {
+	if m.VerifyEmailFn != nil {
+		return m.VerifyEmailFn(token)
+	}
+	return nil
+}
+
+func (m *mockUserService) RevokeRefreshToken(t string) error {
+	if m.RevokeRefreshTokenFn != nil {
+		return m.RevokeRefreshTokenFn(t)
+	}
+	return nil
+}
+
+func (m *mockUserService) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	return nil, nil
+}
+
+func (m *mockUserService) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	return nil, 0, nil
+}
+func (m *mockUserService) Stats() (*domain.UserStats, error) {
+	return &domain.UserStats{}, nil
 }
 
 func (m *mockUserService) Create(u *domain.User) error { return m.CreateFn(u) }
+func (m *mockUserService) RegisterAndLogin(u *domain.User) (domain.TokenPair, error) {
+	if m.RegisterAndLoginFn != nil {
+		return m.RegisterAndLoginFn(u)
+	}
+	return domain.TokenPair{}, nil
+}
+func (m *mockUserService) IssueInitialRefreshToken(id, jti string) (string, error) {
+	if m.IssueInitialRefreshTokenFn != nil {
+		return m.IssueInitialRefreshTokenFn(id, jti)
+	}
+	return "", nil
+}
 func (m *mockUserService) Authenticate(e, p string) (string, string, error) {
 	return m.AuthenticateFn(e, p)
 }
+func (m *mockUserService) AuthenticateInOrg(orgID, e, p string) (string, string, error) {
+	return m.AuthenticateFn(e, p)
+}
 func (m *mockUserService) RefreshTokens(t string) (string, string, error) {
 	return m.RefreshTokensFn(t)
 }
 func (m *mockUserService) GetByID(id string) (*domain.User, error) { return m.GetByIDFn(id) }
 func (m *mockUserService) Update(u *domain.User) error             { return m.UpdateFn(u) }
 func (m *mockUserService) Delete(id string) error                  { return m.DeleteFn(id) }
+func (m *mockUserService) ChangePassword(id, current, next string) error {
+	if m.ChangePasswordFn != nil {
+		return m.ChangePasswordFn(id, current, next)
+	}
+	return nil
+}
+func (m *mockUserService) UpdateFields(id string, fields map[string]any) error {
+	if m.UpdateFieldsFn != nil {
+		return m.UpdateFieldsFn(id, fields)
+	}
+	return nil
+}
 func (m *mockUserService) GetByEmail(email string) (*domain.User, error) {
 	if m.GetByEmailFn != nil {
 		return m.GetByEmailFn(email)
 	}
 	return nil, nil
 }
+func (m *mockUserService) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	return m.GetByEmail(email)
+}
 func (m *mockUserService) List() ([]*domain.User, error) {
 	if m.ListFn != nil {
 		return m.ListFn()
 	}
 	return nil, nil
 }
+func (m *mockUserService) ListAll() ([]*domain.User, error) {
+	return m.List()
+}
 
 func setupGin() *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -70,7 +200,7 @@ func TestUserController_Register_Success(t *testing.T) {
 	uc := NewUserController(ms)
 	r := setupGin()
 	r.POST("/register", uc.Register)
-	body := map[string]interface{}{"email": "a@b.com", "password": "123", "name": "Lucas"}
+	body := map[string]interface{}{"email": "a@b.com", "password": "senha123", "name": "Lucas"}
 	b, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
 	req.Header.Set("Content-Type", "application/json")
@@ -84,6 +214,70 @@ func TestUserController_Register_Success(t *testing.T) {
 	t.Log("[FIM] TestUserController_Register_Success")
 }
 
+// Testa o registro com auto_login, espera os tokens junto ao usuário criado
+func TestUserController_Register_AutoLogin(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_AutoLogin")
+
+	// Arrange: Create não deve ser chamado quando o login automático é pedido
+	ms := &mockUserService{
+		CreateFn: func(u *domain.User) error {
+			t.Fatal("Create chamado com auto_login")
+			return nil
+		},
+		RegisterAndLoginFn: func(u *domain.User) (domain.TokenPair, error) {
+			u.ID = "1"
+			return domain.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/register", uc.Register)
+	b, _ := json.Marshal(map[string]interface{}{"email": "a@b.com", "password": "senha123", "auto_login": true})
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição
+	r.ServeHTTP(w, req)
+
+	// Assert: Tokens e usuário no corpo
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "access", body["token"])
+	assert.Equal(t, "refresh", body["refresh_token"])
+	assert.Equal(t, "a@b.com", body["user"].(map[string]interface{})["email"])
+	t.Log("[FIM] TestUserController_Register_AutoLogin")
+}
+
+// Testa que o cabeçalho de formato de chaves produz UserResponse em camelCase
+func TestUserController_Register_CamelCaseKeys(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_CamelCaseKeys")
+
+	// Arrange: Configura o mock e solicita chaves camelCase
+	ms := &mockUserService{CreateFn: func(u *domain.User) error { return nil }}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/register", uc.Register)
+	b, _ := json.Marshal(map[string]interface{}{"email": "a@b.com", "password": "senha123", "name": "Lucas"})
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pkgerrors.KeyCasingHeader, "camel")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição
+	r.ServeHTTP(w, req)
+
+	// Assert: As chaves compostas do UserResponse estão em camelCase
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body, "createdAt")
+	assert.Contains(t, body, "updatedAt")
+	assert.NotContains(t, body, "created_at")
+	t.Log("[FIM] TestUserController_Register_CamelCaseKeys")
+}
+
 // Testa o registro de usuário com JSON malformado, espera erro 400
 func TestUserController_Register_BadRequest(t *testing.T) {
 	t.Log("[INICIO] TestUserController_Register_BadRequest")
@@ -114,6 +308,39 @@ func TestUserController_Register_BadRequest(t *testing.T) {
 	t.Log("[FIM] TestUserController_Register_BadRequest")
 }
 
+// Testa que emails com parte local reservada não podem ser auto-registrados
+func TestUserController_Register_ReservedEmail(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_ReservedEmail")
+
+	// Arrange: Configura o controller com a lista de nomes reservados
+	created := 0
+	ms := &mockUserService{
+		CreateFn: func(u *domain.User) error { created++; return nil },
+	}
+	uc := NewUserController(ms, WithReservedLocalParts([]string{"admin", "postmaster"}))
+	r := setupGin()
+	r.POST("/register", uc.Register)
+	register := func(email string) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(map[string]interface{}{"email": email, "password": "senha123", "name": "Lucas"})
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act: Tenta registrar um email reservado e um comum
+	reserved := register("Admin@empresa.com")
+	normal := register("lucas@empresa.com")
+
+	// Assert: O reservado é recusado com erro de validação e o comum é criado
+	assert.Equal(t, http.StatusBadRequest, reserved.Code)
+	assert.Contains(t, reserved.Body.String(), "email")
+	assert.Equal(t, http.StatusCreated, normal.Code)
+	assert.Equal(t, 1, created)
+	t.Log("[FIM] TestUserController_Register_ReservedEmail")
+}
+
 // Testa login com credenciais válidas, espera sucesso (200)
 func TestUserController_Login_Success(t *testing.T) {
 	t.Log("[INICIO] TestUserController_Login_Success")
@@ -274,6 +501,49 @@ func TestUserController_RefreshToken_Success(t *testing.T) {
 	t.Log("[FIM] TestUserController_RefreshToken_Success")
 }
 
+// Testa que login e refresh informam a validade do novo refresh token
+func TestUserController_TokenResponses_IncludeRefreshExpiry(t *testing.T) {
+	t.Log("[INICIO] TestUserController_TokenResponses_IncludeRefreshExpiry")
+
+	// Arrange: Tokens reais com validade de 2 horas
+	jwtService := auth.NewJWTService("test-secret", 1, "test-refresh", 2)
+	refresh, err := jwtService.GenerateRefreshToken("123")
+	assert.NoError(t, err)
+	exp, err := auth.TokenExpiry(refresh)
+	assert.NoError(t, err)
+
+	ms := &mockUserService{
+		AuthenticateFn:  func(string, string) (string, string, error) { return "access", refresh, nil },
+		RefreshTokensFn: func(string) (string, string, error) { return "access", refresh, nil },
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/login", uc.Login)
+	r.POST("/refresh", uc.RefreshToken)
+
+	for path, body := range map[string]map[string]string{
+		"/login":   {"email": "a@b.com", "password": "123"},
+		"/refresh": {"refresh_token": "old-refresh"},
+	} {
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, req)
+
+		// Assert: expiração coincide com a claim exp do token
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		var response map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, exp.UTC().Format(time.RFC3339), response["refresh_expires_at"], path)
+		expiresIn, _ := response["refresh_expires_in"].(float64)
+		assert.InDelta(t, (2 * time.Hour).Seconds(), expiresIn, 5, path)
+	}
+	t.Log("[FIM] TestUserController_TokenResponses_IncludeRefreshExpiry")
+}
+
 // Testa refresh token sem token, espera erro 400
 func TestUserController_RefreshToken_NoToken(t *testing.T) {
 	t.Log("[INICIO] TestUserController_RefreshToken_NoToken")
@@ -356,7 +626,7 @@ func TestUserController_GetByID_Success(t *testing.T) {
 	}
 	uc := NewUserController(ms)
 	r := setupGin()
-	r.GET("/users/:id", uc.GetByID)
+	r.GET("/users/:id", func(c *gin.Context) { c.Set("user_id", "123") }, uc.GetByID)
 	req := httptest.NewRequest("GET", "/users/123", nil)
 	w := httptest.NewRecorder()
 
@@ -368,6 +638,70 @@ func TestUserController_GetByID_Success(t *testing.T) {
 	t.Log("[FIM] TestUserController_GetByID_Success")
 }
 
+// Testa que a busca por ID de outro usuário exige ser administrador
+func TestUserController_GetByID_SelfOrAdmin(t *testing.T) {
+	t.Log("[INICIO] TestUserController_GetByID_SelfOrAdmin")
+
+	// Arrange: Usuário comum e administrador consultando o usuário 123
+	ms := &mockUserService{GetByIDFn: func(id string) (*domain.User, error) { return &domain.User{ID: id}, nil }}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.GET("/as-user/:id", func(c *gin.Context) { c.Set("user_id", "u1"); c.Set("roles", []string{"user"}) }, uc.GetByID)
+	r.GET("/as-admin/:id", func(c *gin.Context) { c.Set("user_id", "a1"); c.Set("roles", []string{"admin"}) }, uc.GetByID)
+	r.GET("/anonymous/:id", uc.GetByID)
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+
+	// Act + Assert
+	assert.Equal(t, http.StatusForbidden, get("/as-user/123"))
+	assert.Equal(t, http.StatusOK, get("/as-admin/123"))
+	assert.Equal(t, http.StatusUnauthorized, get("/anonymous/123"))
+	t.Log("[FIM] TestUserController_GetByID_SelfOrAdmin")
+}
+
+// Testa ETag e If-None-Match na busca por ID
+func TestUserController_GetByID_ETag(t *testing.T) {
+	t.Log("[INICIO] TestUserController_GetByID_ETag")
+
+	// Arrange: Usuário cujo UpdatedAt muda após uma atualização
+	user := &domain.User{ID: "123", Email: "a@b.com", UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ms := &mockUserService{GetByIDFn: func(string) (*domain.User, error) { return user, nil }}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.GET("/users/:id", func(c *gin.Context) { c.Set("user_id", "123") }, uc.GetByID)
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/users/123", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act + Assert: A primeira busca retorna a ETag
+	w := get("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Act + Assert: A busca condicional com a mesma ETag retorna 304 sem corpo
+	w = get(etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, http.StatusNotModified, get(`"outra", W/`+etag).Code)
+
+	// Act + Assert: Após uma atualização a ETag antiga não vale mais
+	user.UpdatedAt = user.UpdatedAt.Add(time.Second)
+	w = get(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	t.Log("[FIM] TestUserController_GetByID_ETag")
+}
+
 // Testa busca de usuário por ID sem ID, espera erro 404 (Gin não faz match da rota)
 func TestUserController_GetByID_NoID(t *testing.T) {
 	t.Log("[INICIO] TestUserController_GetByID_NoID")
@@ -414,7 +748,7 @@ func TestUserController_GetByID_NotFound(t *testing.T) {
 	}
 	uc := NewUserController(ms)
 	r := setupGin()
-	r.GET("/users/:id", uc.GetByID)
+	r.GET("/users/:id", func(c *gin.Context) { c.Set("user_id", "999") }, uc.GetByID)
 	req := httptest.NewRequest("GET", "/users/999", nil)
 	w := httptest.NewRecorder()
 
@@ -523,6 +857,32 @@ func TestUserController_Update_NotFound(t *testing.T) {
 	t.Log("[FIM] TestUserController_Update_NotFound")
 }
 
+// Testa que um conflito de versão na atualização retorna 409
+func TestUserController_Update_VersionConflict(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Update_VersionConflict")
+
+	// Arrange: O service rejeita a gravação porque outra requisição alterou o usuário
+	user := &domain.User{ID: "123", Email: "a@b.com", Name: "Lucas", Version: 1}
+	ms := &mockUserService{
+		GetByIDFn: func(string) (*domain.User, error) { return user, nil },
+		UpdateFn:  func(*domain.User) error { return pkgerrors.ErrUserVersionConflict },
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.PUT("/users/:id", uc.Update)
+	b, _ := json.Marshal(map[string]interface{}{"name": "Lucas Updated"})
+	req := httptest.NewRequest("PUT", "/users/123", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	t.Log("[FIM] TestUserController_Update_VersionConflict")
+}
+
 // Testa deleção de usuário com ID válido, espera sucesso (200)
 func TestUserController_Delete_Success(t *testing.T) {
 	t.Log("[INICIO] TestUserController_Delete_Success")
@@ -643,6 +1003,76 @@ func TestUserController_Register_MissingFields(t *testing.T) {
 }
 
 // Testa registro com erro do service (email já existe), espera erro 409
+// Testa que o registro responde com os erros de cada campo em details.fields
+func TestUserController_Register_FieldErrors(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_FieldErrors")
+
+	// Arrange: Configura o mock e um corpo com email e senha inválidos
+	createCalled := false
+	ms := &mockUserService{CreateFn: func(u *domain.User) error { createCalled = true; return nil }}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/register", uc.Register)
+	b, _ := json.Marshal(map[string]interface{}{"email": "nao-e-email", "password": "12"})
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição de registro
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica que os dois campos aparecem nos detalhes
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Details struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Email inválido", response.Details.Fields["email"])
+	assert.Equal(t, pkgerrors.ErrPasswordTooWeak.Message, response.Details.Fields["password"])
+	assert.False(t, createCalled)
+	t.Log("[FIM] TestUserController_Register_FieldErrors")
+}
+
+// Testa a confirmação de senha no registro, exigida ou não pela configuração
+func TestUserController_Register_PasswordConfirmation(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_PasswordConfirmation")
+
+	// Arrange
+	ms := &mockUserService{CreateFn: func(u *domain.User) error { return nil }}
+	register := func(uc *UserController, body map[string]interface{}) *httptest.ResponseRecorder {
+		r := setupGin()
+		r.POST("/register", uc.Register)
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	required := NewUserController(ms, WithPasswordConfirmation(true))
+	optional := NewUserController(ms)
+
+	// Act + Assert: exigida, a confirmação divergente ou ausente é recusada no campo
+	w := register(required, map[string]interface{}{"email": "a@b.com", "password": "senha1234", "password_confirmation": "outra1234"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "password_confirmation")
+	w = register(required, map[string]interface{}{"email": "a@b.com", "password": "senha1234"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "password_confirmation")
+	w = register(required, map[string]interface{}{"email": "a@b.com", "password": "senha1234", "password_confirmation": "senha1234"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Opcional, a ausência é aceita, mas uma confirmação enviada ainda precisa conferir
+	w = register(optional, map[string]interface{}{"email": "a@b.com", "password": "senha1234"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = register(optional, map[string]interface{}{"email": "a@b.com", "password": "senha1234", "password_confirmation": "outra1234"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	t.Log("[FIM] TestUserController_Register_PasswordConfirmation")
+}
+
 func TestUserController_Register_ServiceError(t *testing.T) {
 	t.Log("[INICIO] TestUserController_Register_ServiceError")
 
@@ -660,7 +1090,7 @@ func TestUserController_Register_ServiceError(t *testing.T) {
 	uc := NewUserController(ms)
 	r := setupGin()
 	r.POST("/register", uc.Register)
-	body := map[string]interface{}{"email": "a@b.com", "password": "123"}
+	body := map[string]interface{}{"email": "a@b.com", "password": "senha123"}
 	b, _ := json.Marshal(body)
 	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
 	req.Header.Set("Content-Type", "application/json")
@@ -844,3 +1274,356 @@ func TestUserController_Update_OnlyName(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	t.Log("[FIM] TestUserController_Update_OnlyName")
 }
+
+// validationFields extrai o mapa details.fields de uma resposta de erro de validação
+func validationFields(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var resp struct {
+		Details struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"details"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	return resp.Details.Fields
+}
+
+// Testa login sem senha, espera 400 com detalhe no campo password
+func TestUserController_Login_MissingPassword(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Login_MissingPassword")
+
+	// Arrange: O serviço não deve ser chamado
+	ms := &mockUserService{
+		AuthenticateFn: func(string, string) (string, string, error) {
+			t.Fatal("Authenticate não deveria ser chamado")
+			return "", "", nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/login", uc.Login)
+	b, _ := json.Marshal(map[string]interface{}{"email": "a@b.com"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição de login
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica o detalhe apenas para password
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	fields := validationFields(t, w)
+	assert.Contains(t, fields, "password")
+	assert.NotContains(t, fields, "email")
+	t.Log("[FIM] TestUserController_Login_MissingPassword")
+}
+
+// Testa login sem email, espera 400 com detalhe no campo email
+func TestUserController_Login_MissingEmail(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Login_MissingEmail")
+
+	// Arrange: O serviço não deve ser chamado
+	ms := &mockUserService{
+		AuthenticateFn: func(string, string) (string, string, error) {
+			t.Fatal("Authenticate não deveria ser chamado")
+			return "", "", nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/login", uc.Login)
+	b, _ := json.Marshal(map[string]interface{}{"password": "123"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act: Executa a requisição de login
+	r.ServeHTTP(w, req)
+
+	// Assert: Verifica o detalhe apenas para email
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	fields := validationFields(t, w)
+	assert.Contains(t, fields, "email")
+	assert.NotContains(t, fields, "password")
+	t.Log("[FIM] TestUserController_Login_MissingEmail")
+}
+
+// Testa a troca de senha: sucesso, senha atual incorreta (401) e nova senha fraca (400)
+func TestUserController_ChangePassword(t *testing.T) {
+	t.Log("[INICIO] TestUserController_ChangePassword")
+
+	// Arrange: o mock reproduz as respostas do serviço para cada senha atual
+	ms := &mockUserService{
+		ChangePasswordFn: func(id, current, newPassword string) error {
+			if current != "senha-atual1" {
+				return pkgerrors.ErrInvalidCredentials
+			}
+			if len(newPassword) < 3 {
+				return pkgerrors.ErrPasswordTooWeak
+			}
+			return nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.PUT("/users/:id/password", func(ctx *gin.Context) {
+		ctx.Set("user_id", "u1")
+	}, uc.ChangePassword)
+
+	cases := []struct {
+		name    string
+		current string
+		next    string
+		want    int
+	}{
+		{"senha atual correta", "senha-atual1", "senha-nova1", http.StatusOK},
+		{"senha atual incorreta", "errada", "senha-nova1", http.StatusUnauthorized},
+		{"nova senha fraca", "senha-atual1", "ab", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, _ := json.Marshal(map[string]string{"current_password": tc.current, "new_password": tc.next})
+			req := httptest.NewRequest("PUT", "/users/u1/password", bytes.NewBuffer(b))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+
+	t.Log("[FIM] TestUserController_ChangePassword")
+}
+
+// Testa que o registro normaliza o email via domain.NewUser e recusa emails inválidos
+func TestUserController_Register_UsesDomainFactory(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Register_UsesDomainFactory")
+
+	// Arrange
+	var created *domain.User
+	ms := &mockUserService{
+		CreateFn: func(u *domain.User) error {
+			created = u
+			return nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/register", uc.Register)
+	register := func(email string) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(map[string]string{"email": email, "password": "senha123", "name": " Lucas "})
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	ok := register("Lucas@Example.com")
+	invalid := register("sem-arroba")
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, ok.Code)
+	if assert.NotNil(t, created) {
+		assert.Equal(t, "lucas@example.com", created.Email)
+		assert.Equal(t, "Lucas", created.Name)
+		assert.Equal(t, []string{domain.RoleUser}, created.Roles)
+		assert.Equal(t, domain.ActorSelf, created.CreatedBy)
+	}
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+
+	t.Log("[FIM] TestUserController_Register_UsesDomainFactory")
+}
+
+func TestUserController_LogoutAll(t *testing.T) {
+	t.Log("[INICIO] TestUserController_LogoutAll")
+
+	// Arrange
+	var revoked []string
+	ms := &mockUserService{
+		RevokeAllTokensFn: func(id string) error {
+			if id == "ausente" {
+				return pkgerrors.ErrUserNotFound
+			}
+			revoked = append(revoked, id)
+			return nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	as := func(id string, roles ...string) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			ctx.Set("user_id", id)
+			ctx.Set("roles", roles)
+		}
+	}
+	r.POST("/self/:id/logout-all", as("u1", domain.RoleUser), uc.LogoutAll)
+	r.POST("/admin/:id/logout-all", as("adm", domain.RoleAdmin), uc.LogoutAll)
+	post := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+		return w.Code
+	}
+
+	// Act + Assert: o próprio usuário e o admin podem revogar; outro usuário não
+	assert.Equal(t, http.StatusOK, post("/self/u1/logout-all"))
+	assert.Equal(t, http.StatusForbidden, post("/self/u2/logout-all"))
+	assert.Equal(t, http.StatusOK, post("/admin/u2/logout-all"))
+	assert.Equal(t, http.StatusNotFound, post("/admin/ausente/logout-all"))
+	assert.Equal(t, []string{"u1", "u2"}, revoked)
+
+	t.Log("[FIM] TestUserController_LogoutAll")
+}
+
+func TestUserController_AccountDeletion(t *testing.T) {
+	t.Log("[INICIO] TestUserController_AccountDeletion")
+
+	// Arrange: o mock só exclui com o token emitido na solicitação
+	deleted := false
+	ms := &mockUserService{
+		RequestDeletionFn: func(id string) (string, error) { return "token-" + id, nil },
+		ConfirmDeletionFn: func(id, token string) error {
+			if token != "token-"+id {
+				return pkgerrors.ErrInvalidDeletionToken
+			}
+			deleted = true
+			return nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	asU1 := func(ctx *gin.Context) { ctx.Set("user_id", "u1") }
+	r.POST("/users/:id/delete/request", asU1, uc.RequestDeletion)
+	r.POST("/users/:id/delete/confirm", asU1, uc.ConfirmDeletion)
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act + Assert: solicitar retorna o token sem excluir
+	w := post("/users/u1/delete/request", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "token-u1", resp["confirmation_token"])
+	assert.False(t, deleted)
+
+	// Outra conta, token ausente ou inválido não excluem
+	assert.Equal(t, http.StatusForbidden, post("/users/u2/delete/request", nil).Code)
+	assert.Equal(t, http.StatusForbidden, post("/users/u2/delete/confirm", map[string]string{"confirmation_token": "token-u2"}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/users/u1/delete/confirm", map[string]string{}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/users/u1/delete/confirm", map[string]string{"confirmation_token": "errado"}).Code)
+	assert.False(t, deleted)
+
+	// Com o token válido, a conta é excluída
+	assert.Equal(t, http.StatusOK, post("/users/u1/delete/confirm", map[string]string{"confirmation_token": resp["confirmation_token"]}).Code)
+	assert.True(t, deleted)
+
+	t.Log("[FIM] TestUserController_AccountDeletion")
+}
+
+func TestUserController_VerifyEmail(t *testing.T) {
+	t.Log("[INICIO] TestUserController_VerifyEmail")
+
+	// Arrange: o mock só aceita o token válido uma vez
+	used := false
+	ms := &mockUserService{
+		VerifyEmailFn: func(token string) error {
+			if token != "valido" || used {
+				return pkgerrors.ErrInvalidVerificationToken
+			}
+			used = true
+			return nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.GET("/users/verify", uc.VerifyEmail)
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+
+	// Act + Assert: token ausente ou inválido recebe 400; o válido verifica uma única vez
+	assert.Equal(t, http.StatusBadRequest, get("/users/verify"))
+	assert.Equal(t, http.StatusBadRequest, get("/users/verify?token=errado"))
+	assert.Equal(t, http.StatusOK, get("/users/verify?token=valido"))
+	assert.Equal(t, http.StatusBadRequest, get("/users/verify?token=valido"))
+
+	t.Log("[FIM] TestUserController_VerifyEmail")
+}
+
+// Testa /users/me: resolve o usuário do contexto e responde 401 sem user_id
+func TestUserController_Me(t *testing.T) {
+	t.Log("[INICIO] TestUserController_Me")
+
+	ms := &mockUserService{
+		GetByIDFn: func(id string) (*domain.User, error) {
+			return &domain.User{ID: id, Email: "me@example.com", Name: "Eu"}, nil
+		},
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.GET("/me/authenticated", func(c *gin.Context) { c.Set("user_id", "u1") }, uc.Me)
+	r.GET("/me/anonymous", uc.Me)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/me/authenticated", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "me@example.com")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/me/anonymous", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	t.Log("[FIM] TestUserController_Me")
+}
+
+// Testa o login sem refresh token (emissão adiada) e a emissão dedicada do inicial
+func TestUserController_InitialRefreshToken(t *testing.T) {
+	t.Log("[INICIO] TestUserController_InitialRefreshToken")
+
+	ms := &mockUserService{
+		AuthenticateFn:             func(string, string) (string, string, error) { return "access", "", nil },
+		IssueInitialRefreshTokenFn: func(id, jti string) (string, error) { return "refresh-" + id + "-" + jti, nil },
+	}
+	uc := NewUserController(ms)
+	r := setupGin()
+	r.POST("/login", uc.Login)
+	r.POST("/refresh/initial", func(c *gin.Context) { c.Set("user_id", "u1"); c.Set("token_id", "jti1") }, uc.InitialRefreshToken)
+	r.POST("/refresh/initial/anonymous", uc.InitialRefreshToken)
+
+	// O login omite o refresh token não emitido
+	b, _ := json.Marshal(map[string]string{"email": "a@b.com", "password": "123"})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var login map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	assert.Equal(t, "access", login["token"])
+	assert.NotContains(t, login, "refresh_token")
+
+	// A rota dedicada entrega o refresh token ao usuário autenticado
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/refresh/initial", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var initial map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &initial))
+	assert.Equal(t, "refresh-u1-jti1", initial["refresh_token"])
+	assert.NotContains(t, initial, "token")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/refresh/initial/anonymous", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	t.Log("[FIM] TestUserController_InitialRefreshToken")
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Tipos de eventos do histórico de atividade da conta
+const (
+	ActivityLogin        = "login"
+	ActivityTokenRefresh = "token_refresh"
+)
+
+// ActivityEvent representa um evento de segurança na conta de um usuário
+type ActivityEvent struct {
+	UserID string    `json:"user_id"`
+	Type   string    `json:"type"`
+	At     time.Time `json:"at"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// ActivityStore define as operações de persistência do histórico de atividade
+type ActivityStore interface {
+	Record(event *ActivityEvent) error
+	ListByUser(userID string, offset, limit int) ([]*ActivityEvent, int, error) // mais recentes primeiro, com o total
+}
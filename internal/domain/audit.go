@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Ações registradas na trilha de auditoria administrativa
+const (
+	AuditActionUserUpdated  = "user_updated"
+	AuditActionUserDeleted  = "user_deleted"
+	AuditActionAccessDenied = "access_denied"
+)
+
+// AuditEvent registra quem (ActorID) executou qual ação sobre qual usuário
+// (TargetID) e quando. Em acessos negados, Route é a rota tentada e Missing o
+// papel ou permissão que faltou ("role:admin", "permission:audit:read").
+type AuditEvent struct {
+	ActorID  string    `json:"actor_id"`
+	Action   string    `json:"action"`
+	TargetID string    `json:"target_id,omitempty"`
+	Route    string    `json:"route,omitempty"`
+	Missing  string    `json:"missing,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// AuditStore define as operações de persistência da trilha de auditoria
+type AuditStore interface {
+	Record(event *AuditEvent) error
+	List(offset, limit int) ([]*AuditEvent, int, error) // mais recentes primeiro, com o total
+}
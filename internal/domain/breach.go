@@ -0,0 +1,9 @@
+package domain
+
+// BreachChecker consulta uma base de senhas vazadas no modelo k-anonymity
+// (como a range API do Have I Been Pwned): recebe apenas os 5 primeiros
+// caracteres hexadecimais do SHA-1 da senha e devolve os sufixos conhecidos
+// com esse prefixo, de modo que a senha nunca deixa a aplicação.
+type BreachChecker interface {
+	BreachedSuffixes(prefix string) ([]string, error)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Nomes dos eventos de domínio publicados pelos serviços
+const (
+	EventEmailChanged           = "email_changed"
+	EventPasswordResetRequested = "password_reset_requested"
+	EventEmailVerificationSent  = "email_verification_sent"
+)
+
+// Event representa um evento de domínio publicado após uma operação bem-sucedida
+type Event interface {
+	EventName() string
+}
+
+// EventPublisher entrega eventos de domínio aos interessados (notificações, auditoria)
+type EventPublisher interface {
+	Publish(event Event) error
+}
+
+// EmailChangedEvent indica que o email de uma conta foi alterado. O endereço
+// antigo deve ser notificado para que um comprometimento da conta seja percebido.
+type EmailChangedEvent struct {
+	UserID    string    `json:"user_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// EventName implementa Event
+func (EmailChangedEvent) EventName() string { return EventEmailChanged }
+
+// PasswordResetRequestedEvent indica que foi emitido um token de redefinição de
+// senha, que deve ser entregue ao dono do email por um canal fora da API.
+type PasswordResetRequestedEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	At        time.Time `json:"at"`
+
+	// RedirectURI é o destino, já validado, do link de redefinição
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+// EventName implementa Event
+func (PasswordResetRequestedEvent) EventName() string { return EventPasswordResetRequested }
+
+// EmailVerificationSentEvent indica que foi emitido um token de verificação de
+// email, que deve ser entregue ao endereço cadastrado por um canal fora da API.
+type EmailVerificationSentEvent struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	At        time.Time `json:"at"`
+}
+
+// EventName implementa Event
+func (EmailVerificationSentEvent) EventName() string { return EventEmailVerificationSent }
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// LoginAttemptStore registra as falhas de login por identificador, sustentando o
+// bloqueio temporário da conta após tentativas repetidas
+type LoginAttemptStore interface {
+	RecordFailure(key string, at time.Time) (int, error) // falhas recentes, incluindo esta
+	Lock(key string, until time.Time) error              // bloqueia até until e zera as falhas
+	LockedUntil(key string) (time.Time, error)           // zero quando não há bloqueio
+	Reset(key string) error                              // limpa falhas e bloqueio
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// NonceStore guarda os nonces de uso único emitidos para formulários sensíveis,
+// evitando que o mesmo envio seja processado duas vezes
+type NonceStore interface {
+	Save(nonce string, expiresAt time.Time) error
+	Consume(nonce string) (bool, error) // remove o nonce; true se existia e ainda era válido
+}
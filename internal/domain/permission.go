@@ -0,0 +1,46 @@
+package domain
+
+import "sort"
+
+// Permissões concedidas pelos papéis padrão
+const (
+	PermissionProfileRead  = "profile:read"
+	PermissionProfileWrite = "profile:write"
+	PermissionUsersRead    = "users:read"
+	PermissionUsersWrite   = "users:write"
+	PermissionUsersDelete  = "users:delete"
+	PermissionAuditRead    = "audit:read"
+)
+
+// RolePermissions mapeia cada papel para as permissões que ele concede
+type RolePermissions map[string][]string
+
+// DefaultRolePermissions retorna o mapeamento usado quando nenhum é configurado
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		RoleUser: {PermissionProfileRead, PermissionProfileWrite},
+		RoleAdmin: {
+			PermissionProfileRead, PermissionProfileWrite,
+			PermissionUsersRead, PermissionUsersWrite, PermissionUsersDelete,
+			PermissionAuditRead,
+		},
+	}
+}
+
+// For retorna as permissões efetivas dos papéis informados, sem repetições e
+// em ordem alfabética. Papéis sem mapeamento não concedem permissões.
+func (rp RolePermissions) For(roles []string) []string {
+	seen := make(map[string]struct{})
+	var permissions []string
+	for _, role := range roles {
+		for _, permission := range rp[role] {
+			if _, ok := seen[permission]; ok {
+				continue
+			}
+			seen[permission] = struct{}{}
+			permissions = append(permissions, permission)
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// TokenBlacklist define a revogação de tokens pela claim jti. Add mantém o jti
+// revogado por ttl, que normalmente é o tempo restante até a expiração do token;
+// Contains indica se ele ainda consta como revogado.
+type TokenBlacklist interface {
+	Add(jti string, ttl time.Duration) error
+	Contains(jti string) (bool, error)
+}
+
+// TokenPurger remove as revogações cujos tokens já expiraram
+type TokenPurger interface {
+	PurgeExpired() (int, error)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Session representa uma sessão de refresh token emitida para um usuário.
+// O ID corresponde à claim jti do refresh token.
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SessionStore define as operações de persistência de sessões
+type SessionStore interface {
+	Create(session *Session) error
+	GetByID(id string) (*Session, error)
+	Delete(id string) error
+	ListByUser(userID string) ([]*Session, error) // ordenadas da mais antiga para a mais recente
+}
@@ -1,18 +1,85 @@
 package domain
 
 import (
+	"errors"
+	"net/mail"
+	"strings"
 	"time"
 )
 
+const (
+	// ActorSelf identifica alterações feitas pelo próprio usuário
+	ActorSelf = "self"
+	// ActorSystem identifica alterações feitas pela própria aplicação
+	ActorSystem = "system"
+)
+
+const (
+	// RoleUser é o papel padrão atribuído a todo novo usuário
+	RoleUser = "user"
+	// RoleAdmin concede acesso às rotas administrativas
+	RoleAdmin = "admin"
+)
+
+const (
+	// UserStatusActive é o estado padrão de uma conta, que pode autenticar
+	UserStatusActive = "active"
+	// UserStatusDisabled bloqueia o login e as requisições com tokens já emitidos
+	UserStatusDisabled = "disabled"
+	// UserStatusDeleted marca a conta excluída pelo próprio usuário (exclusão lógica);
+	// como a desativada, não autentica
+	UserStatusDeleted = "deleted"
+)
+
+// ErrVersionConflict indica que o usuário foi alterado desde que foi carregado
+var ErrVersionConflict = errors.New("versão do usuário desatualizada")
+
+// Campos aceitos por UserRepository.UpdateFields
+const (
+	UserFieldEmail     = "email"
+	UserFieldUsername  = "username"
+	UserFieldPassword  = "password"
+	UserFieldName      = "name"
+	UserFieldRoles     = "roles"
+	UserFieldUpdatedBy = "updated_by"
+	UserFieldStatus    = "status"
+
+	UserFieldMustChangePassword = "must_change_password"
+	UserFieldEmailVerified      = "email_verified"
+	UserFieldPasswordChangedAt  = "password_changed_at"
+	UserFieldTokensRevokedAt    = "tokens_revoked_at"
+)
+
 // User representa o modelo de domínio para usuários
 type User struct {
 	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"` // não expor senha nas respostas JSON
+	OrgID     string    `json:"org_id,omitempty"`   // organização (tenant); vazia fora do modo multi-tenant
+	Email     string    `json:"email"`              // único por organização
+	Username  string    `json:"username,omitempty"` // identificador alternativo de login, único
+	Password  string    `json:"-"`                  // não expor senha nas respostas JSON
 	Name      string    `json:"name,omitempty"`
 	Roles     []string  `json:"roles,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"` // ID do admin, "self" ou "system"
+	UpdatedBy string    `json:"updated_by,omitempty"` // ID do admin, "self" ou "system"
+	Version   int       `json:"version"`              // incrementada a cada atualização (concorrência otimista)
+
+	MustChangePassword bool `json:"must_change_password"` // exige troca de senha no próximo login
+
+	Status string `json:"status,omitempty"` // UserStatusActive ou UserStatusDisabled
+
+	EmailVerified bool `json:"email_verified"` // o usuário confirmou a posse do email
+
+	PasswordChangedAt time.Time `json:"-"` // última troca de senha; zero se nunca trocada
+	TokensRevokedAt   time.Time `json:"-"` // refresh tokens emitidos antes disso são recusados
+}
+
+// UserStats agrega contagens de usuários para painéis administrativos
+type UserStats struct {
+	Total    int `json:"total_users"`
+	Verified int `json:"verified_users"`
+	Admins   int `json:"admin_users"`
 }
 
 // UserService define as operações disponíveis para usuários
@@ -21,10 +88,32 @@ type UserService interface {
 	GetByID(id string) (*User, error)
 	GetByEmail(email string) (*User, error)
 	Update(user *User) error
+	UpdateFields(id string, fields map[string]any) error // atualiza apenas os campos informados
 	Delete(id string) error
-	Authenticate(email, password string) (string, string, error) // access, refresh, error
-	RefreshTokens(refreshToken string) (string, string, error)   // access, refresh, error
+	ChangePassword(userID, currentPassword, newPassword string) error
+	Authenticate(identifier, password string) (string, string, error) // email ou username; access, refresh, error
+	RegisterAndLogin(user *User) (TokenPair, error)                   // cria o usuário e já emite os tokens, como Authenticate
+	IssueInitialRefreshToken(userID, jti string) (string, error)      // primeiro refresh token, com o jti do access token do login
+	RefreshTokens(refreshToken string) (string, string, error)        // access, refresh, error
+	RevokeRefreshToken(refreshToken string) error
+	RotateSessions(userID, refreshToken string) (string, string, error) // encerra as demais sessões; access, refresh, error
+	RevokeAllTokens(userID string) error                                // recusa todos os refresh tokens já emitidos
+	ListSessions(userID string) ([]*Session, error)                     // sessões ativas, da mais antiga para a mais recente
+	RequestPasswordReset(req PasswordResetRequest) error                // emite o token e publica o evento de entrega
+	ConfirmPasswordReset(token, newPassword string) error               // consome o token (uso único) e troca a senha
+	VerifyEmail(token string) error                                     // consome o token (uso único) e marca o email como verificado
+	RequestDeletion(userID string) (string, error)                      // emite o token de confirmação da exclusão
+	ConfirmDeletion(userID, token string) error                         // exclusão lógica da conta dona do token
+	RecordAuditEvent(actorID, action, targetID string) error            // trilha de auditoria das ações administrativas
 	List() ([]*User, error)
+	ListAll() ([]*User, error)                              // listagem administrativa
+	ListPaginated(offset, limit int) ([]*User, int, error)  // página e total de usuários
+	ListCreatedBetween(from, to time.Time) ([]*User, error) // intervalo [from, to); zero = sem limite
+	Stats() (*UserStats, error)
+
+	// Modo multi-tenant: o email é resolvido dentro da organização informada
+	GetByOrgAndEmail(orgID, email string) (*User, error)
+	AuthenticateInOrg(orgID, identifier, password string) (string, string, error)
 }
 
 // UserRepository define as operações de persistência para usuários
@@ -32,26 +121,120 @@ type UserRepository interface {
 	Create(user *User) error
 	GetByID(id string) (*User, error)
 	GetByEmail(email string) (*User, error)
-	Update(user *User) error
+	GetByOrgAndEmail(orgID, email string) (*User, error) // chave composta usada no modo multi-tenant
+	GetByUsername(username string) (*User, error)
+	Update(user *User) error                             // falha com ErrVersionConflict se a versão não confere
+	UpdateFields(id string, fields map[string]any) error // atualiza apenas as colunas informadas
 	Delete(id string) error
 	List() ([]*User, error)
+	ListCreatedBetween(from, to time.Time) ([]*User, error) // intervalo [from, to); zero = sem limite
+	ListPaginated(offset, limit int) ([]*User, int, error)  // ordenada por criação; página e total
+	Stats() (*UserStats, error)                             // contagens calculadas no banco
 }
 
 // UserResponse representa a resposta de um usuário
 type UserResponse struct {
 	ID        string    `json:"id"`
 	Email     string    `json:"email"`
+	Username  string    `json:"username,omitempty"`
 	Name      string    `json:"name,omitempty"`
 	Roles     []string  `json:"roles,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// UserRequest representa a requisição de um usuário
+// AdminUserResponse representa a resposta de um usuário para rotas de admin,
+// incluindo os campos de auditoria
+type AdminUserResponse struct {
+	UserResponse
+	CreatedBy          string `json:"created_by,omitempty"`
+	UpdatedBy          string `json:"updated_by,omitempty"`
+	MustChangePassword bool   `json:"must_change_password"`
+	Status             string `json:"status,omitempty"`
+	EmailVerified      bool   `json:"email_verified"`
+}
+
+// UserRequest representa a requisição de um usuário. As regras usam a tag
+// validate, e não binding, para que o controller as aplique com
+// validator.ValidateStruct e responda com os erros por campo.
 type UserRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=3"`
-	Name     string `json:"name,omitempty"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,password"`
+	// PasswordConfirmation, quando enviada, precisa ser igual à senha
+	PasswordConfirmation string `json:"password_confirmation,omitempty" validate:"omitempty,eqfield=Password"`
+	Name                 string `json:"name,omitempty"`
+	Username             string `json:"username,omitempty"`
+	// OrgID é a organização da conta; no modo multi-tenant, o email é único dentro dela
+	OrgID string `json:"org_id,omitempty"`
+	// AutoLogin pede que o registro já retorne os tokens de acesso
+	AutoLogin bool `json:"auto_login,omitempty"`
+}
+
+// PasswordResetRequest identifica a conta cuja senha deve ser redefinida
+type PasswordResetRequest struct {
+	OrgID string // organização da conta no modo multi-tenant
+	Email string
+	// RedirectURI é o destino do link de redefinição, que deve estar na allowlist
+	// (vazio: sem redirecionamento)
+	RedirectURI string
+}
+
+// TokenPair reúne o access token e o refresh token emitidos em uma autenticação
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ContainsRole verifica se o slice de roles contém o papel informado
+func ContainsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole verifica se o usuário possui o papel informado
+func (u *User) HasRole(role string) bool {
+	return ContainsRole(u.Roles, role)
+}
+
+// AddRole adiciona o papel ao usuário; retorna false se ele já o possuía
+func (u *User) AddRole(role string) bool {
+	if u.HasRole(role) {
+		return false
+	}
+	u.Roles = append(u.Roles, role)
+	return true
+}
+
+// SetRoles substitui os papéis do usuário, descartando repetições e mantendo
+// a ordem da primeira ocorrência; retorna quantas repetições foram descartadas
+func (u *User) SetRoles(roles []string) int {
+	u.Roles = make([]string, 0, len(roles))
+	duplicates := 0
+	for _, role := range roles {
+		if !u.AddRole(role) {
+			duplicates++
+		}
+	}
+	return duplicates
+}
+
+// IsActive indica se a conta pode autenticar; contas sem status são tratadas como ativas
+func (u *User) IsActive() bool {
+	return u.Status == "" || u.Status == UserStatusActive
+}
+
+// IsValidUserStatus verifica se o status informado é um dos estados conhecidos
+func IsValidUserStatus(status string) bool {
+	return status == UserStatusActive || status == UserStatusDisabled
+}
+
+// IsAdmin verifica se o usuário possui o papel de administrador
+func (u *User) IsAdmin() bool {
+	return u.HasRole(RoleAdmin)
 }
 
 // Mapper functions
@@ -59,6 +242,7 @@ func (u *User) ToUserResponse() *UserResponse {
 	return &UserResponse{
 		ID:        u.ID,
 		Email:     u.Email,
+		Username:  u.Username,
 		Name:      u.Name,
 		Roles:     u.Roles,
 		CreatedAt: u.CreatedAt,
@@ -66,11 +250,72 @@ func (u *User) ToUserResponse() *UserResponse {
 	}
 }
 
+func (u *User) ToAdminUserResponse() *AdminUserResponse {
+	return &AdminUserResponse{
+		UserResponse:       *u.ToUserResponse(),
+		CreatedBy:          u.CreatedBy,
+		UpdatedBy:          u.UpdatedBy,
+		MustChangePassword: u.MustChangePassword,
+		Status:             u.Status,
+		EmailVerified:      u.EmailVerified,
+	}
+}
+
+// UserValidationError indica o campo que impediu a construção de um usuário
+type UserValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *UserValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// NormalizeEmail remove espaços nas extremidades e converte o email para minúsculas
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NewUser constrói um usuário validado: normaliza o email e o nome, exige uma
+// senha e atribui RoleUser quando nenhum papel é informado. A senha é mantida em
+// texto puro; o hash fica a cargo do serviço.
+func NewUser(email, password, name string, roles []string) (*User, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, &UserValidationError{Field: "email", Message: "Email é obrigatório"}
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, &UserValidationError{Field: "email", Message: "Email inválido"}
+	}
+
+	if password == "" {
+		return nil, &UserValidationError{Field: "password", Message: "Senha é obrigatória"}
+	}
+
+	if len(roles) == 0 {
+		roles = []string{RoleUser}
+	}
+	for _, role := range roles {
+		if strings.TrimSpace(role) == "" {
+			return nil, &UserValidationError{Field: "roles", Message: "Papéis não podem ser vazios"}
+		}
+	}
+
+	return &User{
+		Email:    email,
+		Password: password,
+		Name:     strings.TrimSpace(name),
+		Roles:    append([]string(nil), roles...),
+		Status:   UserStatusActive,
+	}, nil
+}
+
 func (u *UserRequest) FromUserRequest() *User {
 	return &User{
 		Email:    u.Email,
+		Username: u.Username,
 		Password: u.Password,
 		Name:     u.Name,
-		Roles:    []string{"user"}, // padrão: todo novo usuário é "user"
+		Roles:    []string{RoleUser}, // padrão: todo novo usuário é "user"
 	}
 }
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -250,3 +251,156 @@ func TestUserRequestStructFields(t *testing.T) {
 		t.Error("Name não deveria estar vazio")
 	}
 }
+
+func TestToAdminUserResponse(t *testing.T) {
+	user := &User{
+		ID:        "user123",
+		Email:     "test@example.com",
+		CreatedBy: ActorSelf,
+		UpdatedBy: "admin-1",
+	}
+
+	response := user.ToAdminUserResponse()
+
+	if response.ID != user.ID {
+		t.Errorf("ID esperado %s, mas foi %s", user.ID, response.ID)
+	}
+
+	if response.CreatedBy != ActorSelf {
+		t.Errorf("CreatedBy esperado %s, mas foi %s", ActorSelf, response.CreatedBy)
+	}
+
+	if response.UpdatedBy != "admin-1" {
+		t.Errorf("UpdatedBy esperado admin-1, mas foi %s", response.UpdatedBy)
+	}
+}
+
+func TestUserIsAdmin(t *testing.T) {
+	admin := &User{Roles: []string{RoleUser, RoleAdmin}}
+	if !admin.IsAdmin() {
+		t.Error("Usuário com papel admin deveria ser admin")
+	}
+
+	for _, roles := range [][]string{nil, {RoleUser}, {"Admin"}} {
+		user := &User{Roles: roles}
+		if user.IsAdmin() {
+			t.Errorf("Usuário com roles %v não deveria ser admin", roles)
+		}
+	}
+}
+
+func TestUserHasRole(t *testing.T) {
+	user := &User{Roles: []string{RoleUser}}
+	if !user.HasRole(RoleUser) {
+		t.Error("Usuário deveria possuir o papel user")
+	}
+	if user.HasRole(RoleAdmin) {
+		t.Error("Usuário não deveria possuir o papel admin")
+	}
+}
+
+func TestUserAddRole(t *testing.T) {
+	user := &User{Roles: []string{RoleUser}}
+	if !user.AddRole(RoleAdmin) {
+		t.Error("AddRole deveria adicionar um papel novo")
+	}
+	if user.AddRole(RoleAdmin) {
+		t.Error("AddRole não deveria adicionar um papel repetido")
+	}
+	if len(user.Roles) != 2 {
+		t.Errorf("Roles esperado [user admin], mas foi %v", user.Roles)
+	}
+}
+
+func TestUserSetRoles(t *testing.T) {
+	user := &User{Roles: []string{RoleUser}}
+	duplicates := user.SetRoles([]string{RoleAdmin, RoleUser, RoleAdmin, RoleAdmin})
+	if duplicates != 2 {
+		t.Errorf("Repetições esperadas 2, mas foi %d", duplicates)
+	}
+	if len(user.Roles) != 2 || user.Roles[0] != RoleAdmin || user.Roles[1] != RoleUser {
+		t.Errorf("Roles esperado [admin user], mas foi %v", user.Roles)
+	}
+}
+
+func TestNewUser(t *testing.T) {
+	user, err := NewUser("  Novo@Example.COM ", "senha123", " Novo Usuário ", nil)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	if user.Email != "novo@example.com" {
+		t.Errorf("Email normalizado esperado novo@example.com, mas foi %s", user.Email)
+	}
+	if user.Name != "Novo Usuário" {
+		t.Errorf("Name esperado 'Novo Usuário', mas foi %q", user.Name)
+	}
+	if user.Password != "senha123" {
+		t.Errorf("Password esperado senha123, mas foi %s", user.Password)
+	}
+	if len(user.Roles) != 1 || user.Roles[0] != RoleUser {
+		t.Errorf("Roles padrão esperado [user], mas foi %v", user.Roles)
+	}
+	if user.Status != UserStatusActive {
+		t.Errorf("Status esperado %s, mas foi %s", UserStatusActive, user.Status)
+	}
+
+	roles := []string{RoleAdmin}
+	admin, err := NewUser("admin@example.com", "senha123", "", roles)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	roles[0] = "alterado"
+	if len(admin.Roles) != 1 || admin.Roles[0] != RoleAdmin {
+		t.Errorf("Roles esperado [admin] e independente do slice informado, mas foi %v", admin.Roles)
+	}
+}
+
+func TestNewUserInvalidInputs(t *testing.T) {
+	cases := []struct {
+		name     string
+		email    string
+		password string
+		roles    []string
+		field    string
+	}{
+		{"email vazio", "   ", "senha123", nil, "email"},
+		{"email sem domínio", "usuario", "senha123", nil, "email"},
+		{"email com nome de exibição", "Fulano <fulano@example.com>", "senha123", nil, "email"},
+		{"senha vazia", "user@example.com", "", nil, "password"},
+		{"papel vazio", "user@example.com", "senha123", []string{RoleUser, " "}, "roles"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := NewUser(tc.email, tc.password, "Nome", tc.roles)
+			if user != nil {
+				t.Errorf("nenhum usuário deveria ser criado, mas foi %+v", user)
+			}
+			var validationErr *UserValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("UserValidationError esperado, mas foi %v", err)
+			}
+			if validationErr.Field != tc.field {
+				t.Errorf("campo esperado %s, mas foi %s", tc.field, validationErr.Field)
+			}
+		})
+	}
+}
+
+func TestRolePermissions_For(t *testing.T) {
+	rp := RolePermissions{
+		RoleUser:  {"profile:read"},
+		RoleAdmin: {"users:read", "profile:read"},
+	}
+
+	got := rp.For([]string{RoleUser, RoleAdmin, "desconhecido"})
+	want := []string{"profile:read", "users:read"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("permissões esperadas %v, mas foram %v", want, got)
+	}
+
+	if got := rp.For(nil); len(got) != 0 {
+		t.Errorf("sem papéis não deveria haver permissões, mas foram %v", got)
+	}
+}
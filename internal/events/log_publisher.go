@@ -0,0 +1,37 @@
+package events
+
+import (
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// LogPublisher é uma implementação de domain.EventPublisher que apenas registra
+// os eventos no log, servindo de ponto de partida até existir um canal de entrega
+type LogPublisher struct{}
+
+// Garantir que LogPublisher implementa domain.EventPublisher
+var _ domain.EventPublisher = (*LogPublisher)(nil)
+
+// NewLogPublisher cria um novo publicador que registra os eventos no log
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+// Publish registra o evento no log
+func (p *LogPublisher) Publish(event domain.Event) error {
+	switch e := event.(type) {
+	case domain.EmailChangedEvent:
+		logging.Info("Notificação pendente para %s: email da conta %s alterado para %s", e.OldEmail, e.UserID, e.NewEmail)
+	case domain.PasswordResetRequestedEvent:
+		// O token nunca vai para o log: quem lê o log poderia redefinir a senha
+		logging.Info("Redefinição de senha pendente de entrega para %s (conta %s, expira em %s)", e.Email, e.UserID, e.ExpiresAt.Format(time.RFC3339))
+	case domain.EmailVerificationSentEvent:
+		// Assim como na redefinição, o token não vai para o log
+		logging.Info("Verificação de email pendente de entrega para %s (conta %s, expira em %s)", e.Email, e.UserID, e.ExpiresAt.Format(time.RFC3339))
+	default:
+		logging.Info("Evento publicado: %s %+v", event.EventName(), event)
+	}
+	return nil
+}
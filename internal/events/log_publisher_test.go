@@ -0,0 +1,16 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogPublisher_Publish(t *testing.T) {
+	p := NewLogPublisher()
+
+	assert.NoError(t, p.Publish(domain.EmailChangedEvent{UserID: "1", OldEmail: "a@b.com", NewEmail: "c@d.com"}))
+	assert.NoError(t, p.Publish(domain.PasswordResetRequestedEvent{UserID: "1", Email: "a@b.com", Token: "segredo"}))
+	assert.NoError(t, p.Publish(domain.EmailVerificationSentEvent{UserID: "1", Email: "a@b.com", Token: "segredo"}))
+}
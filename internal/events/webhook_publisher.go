@@ -0,0 +1,66 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// DefaultWebhookTimeout é o tempo máximo de cada entrega ao webhook
+const DefaultWebhookTimeout = 5 * time.Second
+
+// WebhookPublisher entrega os eventos com um POST JSON para a URL configurada,
+// onde um serviço de email envia as notificações e os links com os tokens
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// Garantir que WebhookPublisher implementa domain.EventPublisher
+var _ domain.EventPublisher = (*WebhookPublisher)(nil)
+
+// webhookPayload é o corpo enviado ao webhook. O token dos eventos de
+// redefinição e verificação não é serializado com o evento e vai à parte.
+type webhookPayload struct {
+	Event string       `json:"event"`
+	Data  domain.Event `json:"data"`
+	Token string       `json:"token,omitempty"`
+}
+
+// NewWebhookPublisher cria um publicador para a URL informada; client nil usa um
+// cliente com DefaultWebhookTimeout
+func NewWebhookPublisher(url string, client *http.Client) *WebhookPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return &WebhookPublisher{url: url, client: client}
+}
+
+// Publish envia o evento ao webhook; respostas fora de 2xx são tratadas como falha
+func (p *WebhookPublisher) Publish(event domain.Event) error {
+	payload := webhookPayload{Event: event.EventName(), Data: event}
+	switch e := event.(type) {
+	case domain.PasswordResetRequestedEvent:
+		payload.Token = e.Token
+	case domain.EmailVerificationSentEvent:
+		payload.Token = e.Token
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondeu %d ao evento %s", resp.StatusCode, payload.Event)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	p := NewWebhookPublisher(server.URL, nil)
+
+	require.NoError(t, p.Publish(domain.EmailVerificationSentEvent{UserID: "1", Email: "a@b.com", Token: "segredo"}))
+	assert.Equal(t, domain.EventEmailVerificationSent, received["event"])
+	assert.Equal(t, "segredo", received["token"])
+	assert.Equal(t, "a@b.com", received["data"].(map[string]any)["email"])
+
+	require.NoError(t, p.Publish(domain.EmailChangedEvent{UserID: "1", OldEmail: "a@b.com", NewEmail: "c@d.com"}))
+	assert.Equal(t, domain.EventEmailChanged, received["event"])
+	assert.NotContains(t, received, "token")
+}
+
+func TestWebhookPublisher_FailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	p := NewWebhookPublisher(server.URL, nil)
+	assert.Error(t, p.Publish(domain.EmailChangedEvent{UserID: "1"}))
+
+	// Webhook fora do ar
+	server.Close()
+	assert.Error(t, p.Publish(domain.EmailChangedEvent{UserID: "1"}))
+}
@@ -0,0 +1,113 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+)
+
+// entry guarda as falhas recentes e o bloqueio de um identificador
+type entry struct {
+	failures    []time.Time
+	lockedUntil time.Time
+}
+
+// MemoryStore é uma implementação em memória de domain.LoginAttemptStore.
+// Falhas mais antigas que a janela deixam de contar. Adequada para uma única
+// instância da aplicação; contadores e bloqueios se perdem ao reiniciar.
+type MemoryStore struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	window  time.Duration
+	entries map[string]*entry
+}
+
+// Garantir que MemoryStore implementa domain.LoginAttemptStore e domain.TokenPurger
+var (
+	_ domain.LoginAttemptStore = (*MemoryStore)(nil)
+	_ domain.TokenPurger       = (*MemoryStore)(nil)
+)
+
+// NewMemoryStore cria um armazenamento em memória que considera as falhas
+// ocorridas dentro de window
+func NewMemoryStore(window time.Duration, c clock.Clock) *MemoryStore {
+	if c == nil {
+		c = clock.System()
+	}
+	return &MemoryStore{clock: c, window: window, entries: make(map[string]*entry)}
+}
+
+// RecordFailure registra uma falha em at e retorna quantas falhas ocorreram na janela
+func (s *MemoryStore) RecordFailure(key string, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		e = &entry{}
+		s.entries[key] = e
+	}
+	e.failures = append(s.recent(e.failures, at), at)
+	return len(e.failures), nil
+}
+
+// Lock bloqueia o identificador até until e zera as falhas, para que o bloqueio
+// seguinte exija novas tentativas
+func (s *MemoryStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &entry{lockedUntil: until}
+	return nil
+}
+
+// LockedUntil retorna o fim do bloqueio do identificador, ou zero se não houver
+func (s *MemoryStore) LockedUntil(key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		return e.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+// Reset remove falhas e bloqueio do identificador
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// PurgeExpired remove os identificadores sem bloqueio vigente nem falhas na janela
+func (s *MemoryStore) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	purged := 0
+	for key, e := range s.entries {
+		e.failures = s.recent(e.failures, now)
+		if len(e.failures) == 0 && !now.Before(e.lockedUntil) {
+			delete(s.entries, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// recent descarta as falhas anteriores à janela que termina em now
+func (s *MemoryStore) recent(failures []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-s.window)
+	kept := failures[:0]
+	for _, at := range failures {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,64 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_CountsFailuresWithinWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Minute, clock.Func(func() time.Time { return now }))
+
+	n, err := store.RecordFailure("a@b.com", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, _ = store.RecordFailure("a@b.com", now.Add(30*time.Second))
+	assert.Equal(t, 2, n)
+
+	// A primeira falha saiu da janela
+	n, _ = store.RecordFailure("a@b.com", now.Add(70*time.Second))
+	assert.Equal(t, 2, n)
+
+	// Identificadores são independentes
+	n, _ = store.RecordFailure("c@d.com", now)
+	assert.Equal(t, 1, n)
+}
+
+func TestMemoryStore_LockAndReset(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Minute, clock.Func(func() time.Time { return now }))
+	_, _ = store.RecordFailure("a@b.com", now)
+
+	until := now.Add(15 * time.Minute)
+	assert.NoError(t, store.Lock("a@b.com", until))
+	locked, err := store.LockedUntil("a@b.com")
+	assert.NoError(t, err)
+	assert.Equal(t, until, locked)
+
+	// O bloqueio zera as falhas
+	n, _ := store.RecordFailure("a@b.com", now)
+	assert.Equal(t, 1, n)
+
+	assert.NoError(t, store.Reset("a@b.com"))
+	locked, _ = store.LockedUntil("a@b.com")
+	assert.True(t, locked.IsZero())
+}
+
+func TestMemoryStore_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Minute, clock.Func(func() time.Time { return now }))
+	_, _ = store.RecordFailure("antigo", now)
+	_ = store.Lock("bloqueado", now.Add(time.Hour))
+
+	now = now.Add(2 * time.Minute)
+	_, _ = store.RecordFailure("recente", now)
+	purged, err := store.PurgeExpired()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	locked, _ := store.LockedUntil("bloqueado")
+	assert.False(t, locked.IsZero())
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// AccessLog registra uma linha por requisição após a execução dos handlers,
+// com método, caminho, status final e duração, além do request id quando
+// RequestID está registrado antes
+func AccessLog() gin.HandlerFunc {
+	return accessLog(logging.Info)
+}
+
+// accessLog permite substituir o destino do log nos testes
+func accessLog(logf func(format string, v ...interface{})) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		if requestID := c.GetString(logging.RequestIDKey); requestID != "" {
+			logf("[%s] [%s] %s %s %d %s", requestID, c.ClientIP(), c.Request.Method, path, c.Writer.Status(), time.Since(start))
+			return
+		}
+		logf("[%s] %s %s %d %s", c.ClientIP(), c.Request.Method, path, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog_RecordsFinalStatus(t *testing.T) {
+	t.Log("[INICIO] Teste do access log")
+
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	var lines []string
+	router := gin.New()
+	router.Use(accessLog(func(format string, v ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, v...))
+	}))
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/users/:id", func(c *gin.Context) { errors.GinHandleError(c, errors.ErrUserNotFound) })
+
+	// Act
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+
+	// Assert
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "GET /ok 204")
+	assert.Contains(t, lines[1], "GET /users/42 404")
+
+	t.Log("[FIM] Teste do access log")
+}
+
+func TestAccessLog_IncludesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var lines []string
+	router := gin.New()
+	router.Use(RequestID(), accessLog(func(format string, v ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, v...))
+	}))
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest("GET", "/ok", nil)
+	req.Header.Set(RequestIDHeader, "req-77")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "[req-77]")
+	assert.Contains(t, lines[0], "GET /ok 204")
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/cache"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// UserLookup busca o estado atual de um usuário pelo ID
+type UserLookup interface {
+	GetByID(id string) (*domain.User, error)
+}
+
+// AccountStatusMiddleware recusa requisições de contas desativadas ou removidas,
+// mesmo com access tokens ainda válidos. O resultado da consulta fica em cache
+// pelo TTL configurado, então a desativação passa a valer em até um TTL.
+type AccountStatusMiddleware struct {
+	users  UserLookup
+	active *cache.TTL[string, bool]
+}
+
+// NewAccountStatusMiddleware cria o middleware; ttl <= 0 consulta o usuário a cada requisição
+func NewAccountStatusMiddleware(users UserLookup, ttl time.Duration, c clock.Clock) *AccountStatusMiddleware {
+	return &AccountStatusMiddleware{
+		users:  users,
+		active: cache.NewTTL[string, bool](ttl, c),
+	}
+}
+
+// GinRequireActiveAccount deve ser composto após a autenticação, que define user_id
+func (m *AccountStatusMiddleware) GinRequireActiveAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		active, err := m.isActive(userID)
+		if err != nil {
+			logging.Error("[%s] [%s] Erro ao consultar status da conta user_id=%s: %v", c.ClientIP(), c.FullPath(), userID, err)
+			errors.GinHandleError(c, errors.ErrInternalServer.WithError(err))
+			c.Abort()
+			return
+		}
+		if !active {
+			logging.Warning("[%s] [%s] Requisição recusada para conta inativa user_id=%s", c.ClientIP(), c.FullPath(), userID)
+			errors.GinHandleError(c, errors.ErrAccountInactive)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (m *AccountStatusMiddleware) isActive(userID string) (bool, error) {
+	if active, ok := m.active.Get(userID); ok {
+		return active, nil
+	}
+	user, err := m.users.GetByID(userID)
+	if err != nil && !errors.Is(err, errors.ErrUserNotFound) {
+		return false, err
+	}
+	active := user != nil && user.IsActive()
+	m.active.Set(userID, active)
+	return active, nil
+}
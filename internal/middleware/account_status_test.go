@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLookup conta as consultas para verificar o uso do cache
+type fakeLookup struct {
+	users map[string]*domain.User
+	err   error
+	calls int
+}
+
+func (f *fakeLookup) GetByID(id string) (*domain.User, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.users[id], nil
+}
+
+func TestGinAuthenticate_AccountStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	user := &domain.User{ID: "1", Email: "a@b.com", Status: domain.UserStatusActive}
+	token, _ := jwtService.GenerateToken(user)
+	lookup := &fakeLookup{users: map[string]*domain.User{"1": user}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := NewAccountStatusMiddleware(lookup, 30*time.Second, clock.Func(func() time.Time { return now }))
+	mw := NewAuthMiddleware(jwtService, WithAccountStatus(status))
+	r := gin.New()
+	r.GET("/protected", mw.GinAuthenticate(), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+	do := func() int {
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Conta ativa passa; a segunda requisição usa o cache
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, 1, lookup.calls)
+
+	// A desativação só vale após a expiração do cache
+	user.Status = domain.UserStatusDisabled
+	assert.Equal(t, http.StatusOK, do())
+	now = now.Add(31 * time.Second)
+	assert.Equal(t, http.StatusForbidden, do())
+
+	// Conta removida também é recusada
+	delete(lookup.users, "1")
+	now = now.Add(31 * time.Second)
+	assert.Equal(t, http.StatusForbidden, do())
+
+	// Falha na consulta não libera o acesso
+	lookup.err = errors.New("db fora do ar")
+	now = now.Add(31 * time.Second)
+	assert.Equal(t, http.StatusInternalServerError, do())
+}
@@ -3,10 +3,12 @@ package middleware
 import (
 	"context"
 	"net/http"
-	"strings"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
 )
@@ -20,35 +22,85 @@ const (
 	UserEmailKey contextKey = "user_email"
 )
 
+// AuthenticatedUserHeader é o cabeçalho de resposta que ecoa o ID do usuário autenticado
+const AuthenticatedUserHeader = "X-Authenticated-User"
+
 // AuthMiddleware é um middleware que verifica a autenticação JWT
 type AuthMiddleware struct {
-	jwtService *auth.JWTService
+	jwtService       *auth.JWTService
+	exposeUserHeader bool
+	accountStatus    *AccountStatusMiddleware
+	strictUsers      UserLookup
+	permissions      domain.RolePermissions
+	deniedAudit      domain.AuditStore
+}
+
+// AuthOption configura opções opcionais do AuthMiddleware
+type AuthOption func(*AuthMiddleware)
+
+// WithAuthenticatedUserHeader habilita o cabeçalho X-Authenticated-User nas respostas
+// autenticadas (útil para depuração e rastreamento; desabilitado por padrão)
+func WithAuthenticatedUserHeader(enabled bool) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.exposeUserHeader = enabled
+	}
+}
+
+// WithAccountStatus verifica, após a autenticação, se a conta continua ativa
+func WithAccountStatus(status *AccountStatusMiddleware) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.accountStatus = status
+	}
+}
+
+// WithStrictClaims habilita o modo estrito: a cada requisição o usuário é
+// recarregado e o email e as roles atuais do banco substituem os das claims, de
+// modo que remoções de papel valem antes da expiração do token, ao custo de uma
+// consulta por requisição. Contas removidas ou desativadas são recusadas.
+func WithStrictClaims(users UserLookup) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.strictUsers = users
+	}
+}
+
+// WithRolePermissions substitui o mapeamento de papéis para permissões usado
+// para derivar as permissões do contexto, verificadas por RequirePermission
+func WithRolePermissions(permissions domain.RolePermissions) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.permissions = permissions
+	}
+}
+
+// WithDeniedAccessAudit registra na trilha de auditoria cada acesso recusado por
+// GinRequireRole ou GinRequirePermission, com a rota, o usuário e o papel ou
+// permissão que faltou
+func WithDeniedAccessAudit(store domain.AuditStore) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.deniedAudit = store
+	}
 }
 
 // NewAuthMiddleware cria uma nova instância do middleware de autenticação
-func NewAuthMiddleware(jwtService *auth.JWTService) *AuthMiddleware {
-	return &AuthMiddleware{
-		jwtService: jwtService,
+func NewAuthMiddleware(jwtService *auth.JWTService, opts ...AuthOption) *AuthMiddleware {
+	m := &AuthMiddleware{
+		jwtService:  jwtService,
+		permissions: domain.DefaultRolePermissions(),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Authenticate verifica se o token JWT é válido e adiciona as claims no contexto
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			errors.HandleError(w, errors.ErrMissingToken)
-			return
-		}
-
-		// Extrai o token do cabeçalho (formato: "Bearer <token>")
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			errors.HandleError(w, errors.ErrBadRequest.WithMessage("Formato de autorização inválido"))
+		token, err := auth.ExtractBearerToken(r.Header.Get("Authorization"))
+		if err != nil {
+			errors.HandleError(w, bearerError(err))
 			return
 		}
 
-		token := tokenParts[1]
 		claims, err := m.jwtService.ValidateToken(token)
 		if err != nil {
 			logging.Error("Token inválido: %v", err)
@@ -56,6 +108,11 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		if claims.PasswordChangeRequired {
+			errors.HandleError(w, errors.ErrPasswordChangeRequired)
+			return
+		}
+
 		// Adiciona informações do usuário ao contexto
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
@@ -65,31 +122,36 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
-// GinAuthenticate é um middleware de autenticação para o Gin
+// GinAuthenticate é um middleware de autenticação para o Gin. Tokens restritos à
+// troca de senha são recusados com 403.
 func (m *AuthMiddleware) GinAuthenticate() gin.HandlerFunc {
+	return m.ginAuthenticate(false)
+}
+
+// GinAuthenticateAllowingPasswordChange aceita também tokens restritos à troca de
+// senha; deve ser usado apenas na rota de troca de senha
+func (m *AuthMiddleware) GinAuthenticateAllowingPasswordChange() gin.HandlerFunc {
+	return m.ginAuthenticate(true)
+}
+
+func (m *AuthMiddleware) ginAuthenticate(allowPasswordChange bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
 		ip := c.ClientIP()
 		rota := c.FullPath()
 		userAgent := c.Request.UserAgent()
 
-		if authHeader == "" {
-			logging.Warning("[%s] [%s] [%s] Tentativa de acesso sem token de autenticação", ip, rota, userAgent)
-			errors.GinHandleError(c, errors.ErrMissingToken)
-			c.Abort()
-			return
-		}
-
-		// Extrai o token do cabeçalho (formato: "Bearer <token>")
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			logging.Warning("[%s] [%s] [%s] Formato de token inválido: '%s'", ip, rota, userAgent, authHeader)
-			errors.GinHandleError(c, errors.ErrBadRequest.WithMessage("Formato de autorização inválido"))
+		token, err := auth.ExtractBearerToken(c.GetHeader("Authorization"))
+		if err != nil {
+			if errors.Is(err, auth.ErrMissingBearerToken) {
+				logging.Warning("[%s] [%s] [%s] Tentativa de acesso sem token de autenticação", ip, rota, userAgent)
+			} else {
+				logging.Warning("[%s] [%s] [%s] Cabeçalho Authorization recusado: %v", ip, rota, userAgent, err)
+			}
+			errors.GinHandleError(c, bearerError(err))
 			c.Abort()
 			return
 		}
 
-		token := tokenParts[1]
 		claims, err := m.jwtService.ValidateToken(token)
 		if err != nil {
 			logging.Warning("[%s] [%s] [%s] Token inválido: %v", ip, rota, userAgent, err)
@@ -98,18 +160,77 @@ func (m *AuthMiddleware) GinAuthenticate() gin.HandlerFunc {
 			return
 		}
 
+		if claims.PasswordChangeRequired && !allowPasswordChange {
+			logging.Warning("[%s] [%s] [%s] Token restrito à troca de senha usado por user_id=%s", ip, rota, userAgent, claims.UserID)
+			errors.GinHandleError(c, errors.ErrPasswordChangeRequired)
+			c.Abort()
+			return
+		}
+
 		// Adiciona informações do usuário ao contexto
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
-		c.Set("roles", claims.Roles)
+		c.Set("auth_methods", claims.AuthMethods)
+		c.Set("token_id", claims.ID)
+		m.setRoles(c, claims.Roles)
+
+		if m.exposeUserHeader {
+			c.Header(AuthenticatedUserHeader, claims.UserID)
+		}
 
 		logging.Info("[%s] [%s] [%s] Autenticação bem-sucedida para user_id=%s, email=%s", ip, rota, userAgent, claims.UserID, claims.Email)
 
+		if m.strictUsers != nil && !m.applyCurrentUser(c, claims.UserID) {
+			return
+		}
+
+		if m.accountStatus != nil {
+			m.accountStatus.GinRequireActiveAccount()(c)
+			return
+		}
+
 		// Continua para o próximo handler
 		c.Next()
 	}
 }
 
+// bearerError converte a falha de auth.ExtractBearerToken na resposta da API:
+// 401 sem cabeçalho e 400 para formatos inválidos
+func bearerError(err error) errors.AppError {
+	if errors.Is(err, auth.ErrMissingBearerToken) {
+		return errors.ErrMissingToken
+	}
+	return errors.ErrBadRequest.WithMessage("Formato de autorização inválido")
+}
+
+// applyCurrentUser recarrega o usuário e sobrescreve email e roles do contexto
+// com os valores atuais. Em caso de falha responde e retorna false.
+func (m *AuthMiddleware) applyCurrentUser(c *gin.Context, userID string) bool {
+	user, err := m.strictUsers.GetByID(userID)
+	if err != nil && !errors.Is(err, errors.ErrUserNotFound) {
+		logging.Error("[%s] [%s] Erro ao recarregar usuário user_id=%s: %v", c.ClientIP(), c.FullPath(), userID, err)
+		errors.GinHandleError(c, errors.ErrInternalServer.WithError(err))
+		c.Abort()
+		return false
+	}
+	if user == nil || !user.IsActive() {
+		logging.Warning("[%s] [%s] Requisição recusada para conta inativa ou removida user_id=%s", c.ClientIP(), c.FullPath(), userID)
+		errors.GinHandleError(c, errors.ErrAccountInactive)
+		c.Abort()
+		return false
+	}
+
+	c.Set("user_email", user.Email)
+	m.setRoles(c, user.Roles)
+	return true
+}
+
+// setRoles grava no contexto os papéis e as permissões derivadas deles
+func (m *AuthMiddleware) setRoles(c *gin.Context, roles []string) {
+	c.Set("roles", roles)
+	c.Set("permissions", m.permissions.For(roles))
+}
+
 // RequireRole verifica se o usuário tem um papel específico
 // Esta é uma função de exemplo que pode ser expandida conforme necessário
 func (m *AuthMiddleware) RequireRole(role string, next http.Handler) http.Handler {
@@ -128,7 +249,9 @@ func (m *AuthMiddleware) RequireRole(role string, next http.Handler) http.Handle
 	})
 }
 
-// GinRequireRole verifica se o usuário tem um papel específico (versão Gin)
+// GinRequireRole verifica se o usuário tem um papel específico (versão Gin).
+// Sem identidade no contexto (rota sem autenticação antes) responde 401; com
+// usuário autenticado sem o papel, 403.
 func (m *AuthMiddleware) GinRequireRole(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
@@ -144,8 +267,16 @@ func (m *AuthMiddleware) GinRequireRole(role string) gin.HandlerFunc {
 			roles, _ = rolesIface.([]string)
 		}
 
-		if !exists || !hasRoles || !containsRole(roles, role) {
+		if !exists || !hasRoles {
+			logging.Warning("[%s] [%s] [%s] Acesso negado: verificação do papel '%s' sem usuário autenticado", ip, rota, userAgent, role)
+			errors.GinHandleError(c, errors.ErrUnauthorized.WithMessage("Autenticação necessária"))
+			c.Abort()
+			return
+		}
+
+		if !containsRole(roles, role) {
 			logging.Warning("[%s] [%s] [%s] Acesso negado: usuário (id=%v, email=%v) não possui o papel '%s'", ip, rota, userAgent, userID, userEmail, role)
+			recordDenied(c, m.deniedAudit, "role:"+role)
 			errors.GinHandleError(c, errors.ErrForbidden.WithMessage("Acesso negado: permissão insuficiente"))
 			c.Abort()
 			return
@@ -156,12 +287,85 @@ func (m *AuthMiddleware) GinRequireRole(role string) gin.HandlerFunc {
 	}
 }
 
-// containsRole verifica se o slice de roles contém o papel exigido
-func containsRole(roles []string, role string) bool {
-	for _, r := range roles {
-		if r == role {
-			return true
+// RequirePermission exige que os papéis do usuário autenticado concedam a
+// permissão informada. As permissões são derivadas pelo AuthMiddleware a partir
+// dos papéis, portanto o handler deve vir depois da autenticação.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return requirePermission(permission, nil)
+}
+
+// GinRequirePermission funciona como RequirePermission e, com
+// WithDeniedAccessAudit, registra as recusas na trilha de auditoria
+func (m *AuthMiddleware) GinRequirePermission(permission string) gin.HandlerFunc {
+	return requirePermission(permission, m.deniedAudit)
+}
+
+func requirePermission(permission string, audit domain.AuditStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		rota := c.FullPath()
+		userAgent := c.Request.UserAgent()
+		userID, _ := c.Get("user_id")
+
+		var permissions []string
+		if value, ok := c.Get("permissions"); ok {
+			permissions, _ = value.([]string)
 		}
+
+		if !slices.Contains(permissions, permission) {
+			logging.Warning("[%s] [%s] [%s] Acesso negado: usuário (id=%v) não possui a permissão '%s'", ip, rota, userAgent, userID, permission)
+			recordDenied(c, audit, "permission:"+permission)
+			errors.GinHandleError(c, errors.ErrForbidden.WithMessage("Acesso negado: permissão insuficiente"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
 	}
-	return false
+}
+
+// RequireAuthMethod exige que o token tenha sido emitido após autenticação com
+// ao menos um dos métodos informados (claim amr), como auth.AuthMethodMFA em
+// rotas sensíveis. Tokens sem a claim são recusados; deve vir depois da autenticação.
+func RequireAuthMethod(methods ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var used []string
+		if value, ok := c.Get("auth_methods"); ok {
+			used, _ = value.([]string)
+		}
+
+		for _, method := range methods {
+			if slices.Contains(used, method) {
+				c.Next()
+				return
+			}
+		}
+
+		logging.Warning("[%s] [%s] [%s] Acesso negado: usuário (id=%v) autenticado com %v, exigido um de %v", c.ClientIP(), c.FullPath(), c.Request.UserAgent(), c.GetString("user_id"), used, methods)
+		errors.GinHandleError(c, errors.ErrForbidden.WithMessage("Acesso negado: esta operação exige um método de autenticação mais forte"))
+		c.Abort()
+	}
+}
+
+// recordDenied registra o acesso recusado na trilha, quando configurada. Falhas
+// são apenas logadas: a resposta 403 não depende da auditoria.
+func recordDenied(c *gin.Context, audit domain.AuditStore, missing string) {
+	if audit == nil {
+		return
+	}
+	event := &domain.AuditEvent{
+		ActorID: c.GetString("user_id"),
+		Action:  domain.AuditActionAccessDenied,
+		Route:   c.Request.Method + " " + c.FullPath(),
+		Missing: missing,
+		At:      time.Now(),
+	}
+	if err := audit.Record(event); err != nil {
+		logging.With(c).Error("Falha ao auditar acesso negado (%s): %v", missing, err)
+	}
+}
+
+// containsRole verifica se o slice de roles contém o papel exigido
+func containsRole(roles []string, role string) bool {
+	return domain.ContainsRole(roles, role)
 }
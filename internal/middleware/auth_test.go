@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/audit"
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -49,6 +50,40 @@ func TestGinAuthenticate_SuccessAndFail(t *testing.T) {
 	assert.Equal(t, 401, w3.Code)
 }
 
+func TestGinAuthenticate_StrictClaimsUsesCurrentRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	user := &domain.User{ID: "1", Email: "a@b.com", Roles: []string{"user", "admin"}, Status: domain.UserStatusActive}
+	token, _ := jwtService.GenerateToken(user)
+
+	// O papel admin é removido no banco; o token continua declarando-o
+	stored := *user
+	stored.Roles = []string{"user"}
+	lookup := &fakeLookup{users: map[string]*domain.User{"1": &stored}}
+
+	do := func(mw *AuthMiddleware) int {
+		r := gin.New()
+		r.GET("/admin", mw.GinAuthenticate(), mw.GinRequireRole("admin"), func(c *gin.Context) {
+			c.String(200, "ok")
+		})
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Sem o modo estrito as claims do token são usadas
+	assert.Equal(t, 200, do(NewAuthMiddleware(jwtService)))
+	// No modo estrito a remoção vale de imediato
+	assert.Equal(t, 403, do(NewAuthMiddleware(jwtService, WithStrictClaims(lookup))))
+	assert.Equal(t, 1, lookup.calls)
+
+	// Conta removida do banco é recusada
+	delete(lookup.users, "1")
+	assert.Equal(t, 403, do(NewAuthMiddleware(jwtService, WithStrictClaims(lookup))))
+}
+
 func TestGinRequireRole_SuccessAndFail(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	jwtService := getJWT()
@@ -75,6 +110,151 @@ func TestGinRequireRole_SuccessAndFail(t *testing.T) {
 	assert.Equal(t, 403, w2.Code)
 }
 
+func TestGinRequireRole_WithoutAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := audit.NewMemoryStore(10)
+	mw := NewAuthMiddleware(getJWT(), WithDeniedAccessAudit(store))
+	r := gin.New()
+	// Rota protegida por papel, mas sem o middleware de autenticação antes
+	r.GET("/admin", mw.GinRequireRole("admin"), func(c *gin.Context) { c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	// Sem identidade a resposta é 401, e não uma recusa de autorização auditada
+	assert.Equal(t, 401, w.Code)
+	_, total, _ := store.List(0, 10)
+	assert.Equal(t, 0, total)
+}
+
+func TestRequirePermission_GrantedByRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	mw := NewAuthMiddleware(jwtService, WithRolePermissions(domain.RolePermissions{
+		"auditor": {"audit:read"},
+		"user":    {"profile:read"},
+	}))
+	r := gin.New()
+	r.GET("/admin/audit", mw.GinAuthenticate(), RequirePermission("audit:read"), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+
+	// Sucesso: o papel auditor concede audit:read
+	token, _ := jwtService.GenerateToken(&domain.User{ID: "1", Email: "a@b.com", Roles: []string{"user", "auditor"}})
+	req := httptest.NewRequest("GET", "/admin/audit", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	// Falha: nenhum papel do usuário concede a permissão
+	token2, _ := jwtService.GenerateToken(&domain.User{ID: "2", Email: "b@b.com", Roles: []string{"user"}})
+	req2 := httptest.NewRequest("GET", "/admin/audit", nil)
+	req2.Header.Set("Authorization", "Bearer "+token2)
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, req2)
+	assert.Equal(t, 403, w2.Code)
+}
+
+func TestRequirePermission_WithoutAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin/audit", RequirePermission("audit:read"), func(c *gin.Context) { c.String(200, "ok") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/admin/audit", nil))
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestRequireAuthMethod_BlocksPasswordOnlyToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	mw := NewAuthMiddleware(jwtService)
+	r := gin.New()
+	r.DELETE("/account", mw.GinAuthenticate(), RequireAuthMethod(auth.AuthMethodMFA, auth.AuthMethodOTP), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+	user := &domain.User{ID: "1", Email: "a@b.com", Roles: []string{"user"}}
+
+	// Falha: token emitido apenas com senha
+	pwdToken, _ := jwtService.GenerateToken(user, auth.AuthMethodPassword)
+	req := httptest.NewRequest("DELETE", "/account", nil)
+	req.Header.Set("Authorization", "Bearer "+pwdToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+	assert.Contains(t, w.Body.String(), "método de autenticação mais forte")
+
+	// Falha: token sem a claim amr
+	legacyToken, _ := jwtService.GenerateToken(user)
+	req2 := httptest.NewRequest("DELETE", "/account", nil)
+	req2.Header.Set("Authorization", "Bearer "+legacyToken)
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, req2)
+	assert.Equal(t, 403, w2.Code)
+
+	// Sucesso: senha seguida de segundo fator
+	mfaToken, _ := jwtService.GenerateToken(user, auth.AuthMethodPassword, auth.AuthMethodOTP, auth.AuthMethodMFA)
+	req3 := httptest.NewRequest("DELETE", "/account", nil)
+	req3.Header.Set("Authorization", "Bearer "+mfaToken)
+	w3 := httptest.NewRecorder()
+	r.ServeHTTP(w3, req3)
+	assert.Equal(t, 200, w3.Code)
+}
+
+func TestGinRequireRole_AuditsDeniedAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	trail := audit.NewMemoryStore(0)
+	mw := NewAuthMiddleware(jwtService, WithDeniedAccessAudit(trail))
+	r := gin.New()
+	r.GET("/admin/users", mw.GinAuthenticate(), mw.GinRequireRole("admin"), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+
+	token, _ := jwtService.GenerateToken(&domain.User{ID: "2", Email: "b@b.com", Roles: []string{"user"}})
+	req := httptest.NewRequest("GET", "/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 403, w.Code)
+	events, total, err := trail.List(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "2", events[0].ActorID)
+		assert.Equal(t, domain.AuditActionAccessDenied, events[0].Action)
+		assert.Equal(t, "GET /admin/users", events[0].Route)
+		assert.Equal(t, "role:admin", events[0].Missing)
+	}
+}
+
+func TestGinRequirePermission_AuditsOnlyDenials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	trail := audit.NewMemoryStore(0)
+	mw := NewAuthMiddleware(jwtService, WithDeniedAccessAudit(trail))
+	r := gin.New()
+	r.GET("/admin/audit", mw.GinAuthenticate(), mw.GinRequirePermission(domain.PermissionAuditRead), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+
+	for _, roles := range [][]string{{"admin"}, {"user"}} {
+		token, _ := jwtService.GenerateToken(&domain.User{ID: roles[0], Email: "a@b.com", Roles: roles})
+		req := httptest.NewRequest("GET", "/admin/audit", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	events, total, _ := trail.List(0, 10)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "user", events[0].ActorID)
+		assert.Equal(t, "permission:"+domain.PermissionAuditRead, events[0].Missing)
+	}
+}
+
 func TestAuthenticate_HTTP_SuccessAndFail(t *testing.T) {
 	jwtService := getJWT()
 	user := &domain.User{ID: "1", Email: "a@b.com", Roles: []string{"admin"}}
@@ -172,3 +352,77 @@ func TestAuthenticate_InvalidHeaderFormat(t *testing.T) {
 	handler.ServeHTTP(w2, req2)
 	assert.Equal(t, 400, w2.Code)
 }
+
+func TestAuthenticate_BearerParsingMatchesAcrossMiddlewares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	token, _ := jwtService.GenerateToken(&domain.User{ID: "1", Email: "a@b.com", Roles: []string{"user"}})
+	mw := NewAuthMiddleware(jwtService)
+
+	r := gin.New()
+	r.GET("/", mw.GinAuthenticate(), func(c *gin.Context) { c.String(200, "ok") })
+	handler := mw.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"válido", "Bearer " + token, 200},
+		{"esquema minúsculo", "bearer " + token, 200},
+		{"ausente", "", 401},
+		{"sem esquema", token, 400},
+		{"token vazio", "Bearer ", 400},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, h := range map[string]http.Handler{"gin": r, "net/http": handler} {
+				req := httptest.NewRequest("GET", "/", nil)
+				if tc.header != "" {
+					req.Header.Set("Authorization", tc.header)
+				}
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, req)
+				assert.Equal(t, tc.want, w.Code, name)
+			}
+		})
+	}
+}
+
+func TestGinAuthenticate_AuthenticatedUserHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := getJWT()
+	user := &domain.User{ID: "42", Email: "a@b.com", Roles: []string{"user"}}
+	token, _ := jwtService.GenerateToken(user)
+
+	for _, enabled := range []bool{true, false} {
+		mw := NewAuthMiddleware(jwtService, WithAuthenticatedUserHeader(enabled))
+		r := gin.New()
+		r.GET("/protected", mw.GinAuthenticate(), func(c *gin.Context) {
+			c.String(200, "ok")
+		})
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+		if enabled {
+			assert.Equal(t, "42", w.Header().Get(AuthenticatedUserHeader))
+		} else {
+			assert.Empty(t, w.Header().Get(AuthenticatedUserHeader))
+		}
+	}
+
+	// Desabilitado por padrão
+	r := gin.New()
+	r.GET("/protected", NewAuthMiddleware(jwtService).GinAuthenticate(), func(c *gin.Context) {
+		c.String(200, "ok")
+	})
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get(AuthenticatedUserHeader))
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// CORSConfig define as opções do middleware de CORS
+type CORSConfig struct {
+	// AllowedOrigins aceita origens exatas, "*" para qualquer origem e curingas
+	// de subdomínio como "https://*.example.com"
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// MaxAge define por quanto tempo o navegador pode reutilizar o resultado do preflight.
+	// Zero omite o cabeçalho Access-Control-Max-Age.
+	MaxAge time.Duration
+	// AllowCredentials libera cookies e o cabeçalho Authorization em requisições
+	// de outra origem (Access-Control-Allow-Credentials: true)
+	AllowCredentials bool
+}
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", errors.KeyCasingHeader, NonceHeader}
+)
+
+// CORS é um middleware que responde aos preflights e libera as origens permitidas
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge / time.Second))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		// A resposta depende da origem mesmo quando ela é recusada
+		c.Header("Vary", "Origin")
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			c.Next()
+			return
+		}
+
+		// A origem é sempre ecoada: "*" não é aceito pelos navegadores com credenciais
+		c.Header("Access-Control-Allow-Origin", origin)
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Preflight: responde diretamente sem chegar aos handlers
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// originAllowed verifica se a origem está na lista de origens permitidas,
+// considerando "*" e os curingas de subdomínio
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || o == origin || wildcardOriginMatch(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardOriginMatch compara a origem com um padrão "esquema://*.dominio",
+// exigindo ao menos um rótulo no lugar do curinga: "https://*.example.com"
+// aceita "https://app.example.com", mas não "https://example.com"
+func wildcardOriginMatch(pattern, origin string) bool {
+	prefix, suffix, ok := strings.Cut(pattern, "*")
+	if !ok || !strings.HasSuffix(prefix, "://") || !strings.HasPrefix(suffix, ".") {
+		return false
+	}
+	if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	label := origin[len(prefix) : len(origin)-len(suffix)]
+	return label != "" && !strings.ContainsAny(label, "/:")
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCORSRouter(cfg CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(cfg))
+	r.GET("/users", func(c *gin.Context) { c.String(200, "ok") })
+	return r
+}
+
+func TestCORS_PreflightIncludesMaxAge(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		MaxAge:         10 * time.Minute,
+	})
+	req := httptest.NewRequest("OPTIONS", "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_PreflightWithoutMaxAge(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}})
+	req := httptest.NewRequest("OPTIONS", "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORS_SimpleRequestOmitsMaxAge(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		MaxAge:         time.Minute,
+	})
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	// A requisição segue, mas sem cabeçalhos que liberem a leitura pelo navegador
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORS_PreflightReturnsNoContent(t *testing.T) {
+	r := setupCORSRouter(CORSConfig{AllowedOrigins: []string{"https://*.example.com"}})
+	req := httptest.NewRequest("OPTIONS", "/users", nil)
+	req.Header.Set("Origin", "https://admin.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+	assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "POST")
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+}
+
+func TestCORS_OriginAllowed_Wildcards(t *testing.T) {
+	cases := []struct {
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{[]string{"*"}, "https://qualquer.com", true},
+		{[]string{"https://*.example.com"}, "https://app.example.com", true},
+		{[]string{"https://*.example.com"}, "https://a.b.example.com", true},
+		{[]string{"https://*.example.com"}, "https://example.com", false},
+		{[]string{"https://*.example.com"}, "http://app.example.com", false},
+		{[]string{"https://*.example.com"}, "https://app.example.com.evil.org", false},
+		{[]string{"https://*.example.com"}, "https://evil.org/.example.com", false},
+		{[]string{"https://app.example.com"}, "https://app.example.com:8443", false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, originAllowed(tc.allowed, tc.origin), "%v %s", tc.allowed, tc.origin)
+	}
+}
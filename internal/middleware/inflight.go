@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// MaxInFlight limita o número de requisições processadas simultaneamente usando
+// um semáforo. Quando todas as vagas estão ocupadas a requisição recebe 503
+// imediatamente, sem enfileirar. max <= 0 desabilita o limite.
+func MaxInFlight(max int) gin.HandlerFunc {
+	if max <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	slots := make(chan struct{}, max)
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+			c.Next()
+		default:
+			logging.Warning("[%s] [%s] Limite de %d requisições simultâneas atingido", c.ClientIP(), c.FullPath(), max)
+			c.Header("Retry-After", "1")
+			errors.GinHandleError(c, errors.ErrServiceUnavailable)
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxInFlight_RejectsWhenSaturatedAndFreesCapacity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	r := gin.New()
+	r.Use(MaxInFlight(1))
+	r.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	r.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Ocupa a única vaga com uma requisição lenta
+	var wg sync.WaitGroup
+	slow := httptest.NewRecorder()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.ServeHTTP(slow, httptest.NewRequest("GET", "/slow", nil))
+	}()
+	<-entered
+
+	// Com o limite saturado, novas requisições recebem 503
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Ao terminar o handler, a vaga é liberada
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, slow.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMaxInFlight_DisabledWhenZero(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(MaxInFlight(0))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// LogContext popula os campos de log da requisição (request id, IP do cliente e
+// rota) consumidos por logging.With(ctx). O request id vem de RequestID, quando
+// registrado antes, ou do cabeçalho X-Request-ID. O usuário autenticado é
+// incluído a partir do user_id definido depois pelo middleware de autenticação.
+func LogContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetString(logging.RequestIDKey)
+		if requestID == "" {
+			requestID = c.GetHeader(RequestIDHeader)
+		}
+		fields := logging.Fields{
+			RequestID: requestID,
+			ClientIP:  c.ClientIP(),
+			Route:     c.FullPath(),
+		}
+		c.Set(logging.FieldsKey, fields)
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), fields))
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer permite ler o log enquanto outros testes escrevem nele
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// logOut captura os logs do pacote durante os testes
+var logOut syncBuffer
+
+func TestMain(m *testing.M) {
+	logging.SetupLogger(logging.Config{InfoWriter: &logOut, WarningWriter: &logOut, ErrorWriter: &logOut})
+	os.Exit(m.Run())
+}
+
+func TestLogContext_HandlerLogIncludesFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(LogContext())
+	authenticated := func(c *gin.Context) { c.Set("user_id", "user-42") }
+	r.GET("/users/:id/activity", authenticated, func(c *gin.Context) {
+		logging.With(c).Info("atividade consultada")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/users/abc/activity", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.RemoteAddr = "203.0.113.7:4321"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var line string
+	for _, l := range strings.Split(logOut.String(), "\n") {
+		if strings.Contains(l, "atividade consultada") {
+			line = l
+		}
+	}
+	assert.Contains(t, line, "request_id=req-123")
+	assert.Contains(t, line, "ip=203.0.113.7")
+	assert.Contains(t, line, "user=user-42")
+	assert.Contains(t, line, "route=/users/:id/activity")
+}
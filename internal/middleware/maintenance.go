@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// maintenanceExemptPaths são as rotas de saúde, que seguem respondendo durante a
+// manutenção para que probes e balanceadores não derrubem a instância
+var maintenanceExemptPaths = map[string]bool{
+	"/health": true,
+	"/status": true,
+}
+
+// MaintenanceMode responde 503 a todas as rotas, exceto /health e /status,
+// enquanto enabled retornar true. enabled é consultada a cada requisição, para
+// que a manutenção seja ligada e desligada sem reiniciar o servidor.
+func MaintenanceMode(enabled func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled() || maintenanceExemptPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		logging.Debug("[%s] [%s] Requisição recusada durante a manutenção", c.ClientIP(), c.Request.URL.Path)
+		errors.GinHandleError(c, errors.ErrMaintenance)
+		c.Abort()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var maintenance atomic.Bool
+	r := gin.New()
+	r.Use(MaintenanceMode(maintenance.Load))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/health", ok)
+	r.GET("/status", ok)
+	r.POST("/users/login", ok)
+	get := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// Desligada, nada muda
+	assert.Equal(t, http.StatusOK, get("POST", "/users/login").Code)
+
+	// Ligada, rotas comuns recebem 503 e as de saúde seguem respondendo
+	maintenance.Store(true)
+	w := get("POST", "/users/login")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "manutenção")
+	assert.Equal(t, http.StatusOK, get("GET", "/health").Code)
+	assert.Equal(t, http.StatusOK, get("GET", "/status").Code)
+
+	// Desligada novamente sem recriar o middleware
+	maintenance.Store(false)
+	assert.Equal(t, http.StatusOK, get("POST", "/users/login").Code)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// NonceHeader é o cabeçalho com o nonce obtido em GET /nonce
+const NonceHeader = "X-Nonce"
+
+// RequireNonce exige um nonce válido no cabeçalho X-Nonce e o consome antes do
+// handler, de modo que um reenvio do mesmo formulário é recusado com 409
+func RequireNonce(store domain.NonceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(NonceHeader)
+		if value == "" {
+			logging.Warning("[%s] [%s] Requisição sem nonce", c.ClientIP(), c.FullPath())
+			errors.GinHandleError(c, errors.ErrNonceRequired)
+			c.Abort()
+			return
+		}
+
+		ok, err := store.Consume(value)
+		if err != nil {
+			logging.Error("[%s] [%s] Erro ao consumir nonce: %v", c.ClientIP(), c.FullPath(), err)
+			errors.GinHandleError(c, errors.ErrInternalServer.WithError(err))
+			c.Abort()
+			return
+		}
+		if !ok {
+			logging.Warning("[%s] [%s] Nonce reutilizado, expirado ou desconhecido", c.ClientIP(), c.FullPath())
+			errors.GinHandleError(c, errors.ErrNonceInvalid)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
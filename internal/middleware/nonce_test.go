@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/nonce"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingNonceStore simula uma falha do armazenamento de nonces
+type failingNonceStore struct{}
+
+func (failingNonceStore) Save(string, time.Time) error { return nil }
+func (failingNonceStore) Consume(string) (bool, error) { return false, errors.New("falha") }
+
+func nonceRouter(mw gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/submit", mw, func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return r
+}
+
+func postWithNonce(r *gin.Engine, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+	if value != "" {
+		req.Header.Set(NonceHeader, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireNonce_WorksOnceAndRejectsReuse(t *testing.T) {
+	store := nonce.NewMemoryStore(nil)
+	assert.NoError(t, store.Save("abc", time.Now().Add(time.Minute)))
+	r := nonceRouter(RequireNonce(store))
+
+	assert.Equal(t, http.StatusCreated, postWithNonce(r, "abc").Code)
+	assert.Equal(t, http.StatusConflict, postWithNonce(r, "abc").Code)
+}
+
+func TestRequireNonce_MissingOrUnknown(t *testing.T) {
+	r := nonceRouter(RequireNonce(nonce.NewMemoryStore(nil)))
+
+	assert.Equal(t, http.StatusBadRequest, postWithNonce(r, "").Code)
+	assert.Equal(t, http.StatusConflict, postWithNonce(r, "desconhecido").Code)
+}
+
+func TestRequireNonce_StoreError(t *testing.T) {
+	r := nonceRouter(RequireNonce(failingNonceStore{}))
+
+	assert.Equal(t, http.StatusInternalServerError, postWithNonce(r, "abc").Code)
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"golang.org/x/time/rate"
+)
+
+// minRateLimitIdle é o tempo mínimo sem requisições antes de um bucket ser descartado
+const minRateLimitIdle = time.Minute
+
+// ipBucket guarda o token bucket de um IP e a última vez em que foi usado
+type ipBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ipRateLimiter mantém um token bucket por IP. Buckets ociosos por mais que
+// idle são descartados a cada idle, durante as próprias requisições.
+type ipRateLimiter struct {
+	mu        sync.Mutex
+	clock     clock.Clock
+	rps       rate.Limit
+	burst     int
+	idle      time.Duration
+	lastSweep time.Time
+	buckets   map[string]*ipBucket
+}
+
+func newIPRateLimiter(rps float64, burst int, c clock.Clock) *ipRateLimiter {
+	if c == nil {
+		c = clock.System()
+	}
+	// Um bucket ocioso pelo tempo de recarga completa equivale a um novo
+	idle := time.Duration(float64(burst) / rps * float64(time.Second))
+	if idle < minRateLimitIdle {
+		idle = minRateLimitIdle
+	}
+	return &ipRateLimiter{
+		clock:     c,
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		idle:      idle,
+		lastSweep: c.Now(),
+		buckets:   make(map[string]*ipBucket),
+	}
+}
+
+// allow consome um token do bucket do IP; quando não há token, retorna também
+// o tempo até o próximo
+func (l *ipRateLimiter) allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	if now.Sub(l.lastSweep) >= l.idle {
+		l.evictIdle(now)
+	}
+
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &ipBucket{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.buckets[ip] = b
+	}
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// evictIdle remove os buckets sem uso desde now-idle
+func (l *ipRateLimiter) evictIdle(now time.Time) {
+	cutoff := now.Add(-l.idle)
+	for ip, b := range l.buckets {
+		if b.lastSeen.Before(cutoff) {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimit limita as requisições por IP do cliente com um token bucket de
+// rps tokens por segundo e capacidade burst. Excedido o limite, a requisição
+// recebe 429 com Retry-After. rps <= 0 ou burst <= 0 desabilita o limite.
+func RateLimit(rps float64, burst int) gin.HandlerFunc {
+	return rateLimit(rps, burst, nil)
+}
+
+func rateLimit(rps float64, burst int, c clock.Clock) gin.HandlerFunc {
+	if rps <= 0 || burst <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := newIPRateLimiter(rps, burst, c)
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		if ok, retryAfter := limiter.allow(ip); !ok {
+			logging.Warning("[%s] [%s] Limite de requisições por IP excedido", ip, c.FullPath())
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			errors.GinHandleError(c, errors.ErrRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRateLimitRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler)
+	r.POST("/users/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func loginFrom(r *gin.Engine, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/users/login", nil)
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_ThrottlesPerIP(t *testing.T) {
+	r := newRateLimitRouter(RateLimit(0.01, 2))
+
+	// O burst é consumido e a requisição seguinte do mesmo IP recebe 429
+	assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.1").Code)
+	w := loginFrom(r, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Muitas requisições")
+
+	// Outro IP tem o seu próprio bucket
+	assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.2").Code)
+}
+
+func TestRateLimit_RefillsOverTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := newRateLimitRouter(rateLimit(1, 1, clock.Func(func() time.Time { return now })))
+
+	assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.1").Code)
+	w := loginFrom(r, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.1").Code)
+}
+
+func TestRateLimit_EvictsIdleBuckets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newIPRateLimiter(1, 5, clock.Func(func() time.Time { return now }))
+
+	limiter.allow("10.0.0.1")
+	now = now.Add(30 * time.Second)
+	limiter.allow("10.0.0.2")
+	assert.Len(t, limiter.buckets, 2)
+
+	// Após o intervalo de ociosidade, só o bucket usado recentemente permanece
+	now = now.Add(45 * time.Second)
+	limiter.allow("10.0.0.2")
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "10.0.0.2")
+}
+
+func TestRateLimit_DisabledWhenZero(t *testing.T) {
+	r := newRateLimitRouter(RateLimit(0, 0))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, loginFrom(r, "10.0.0.1").Code)
+	}
+}
+
+func loginFromForwarded(r *gin.Engine, ip, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/users/login", nil)
+	req.RemoteAddr = ip + ":12345"
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_SpoofedForwardedForIgnored(t *testing.T) {
+	r := newRateLimitRouter(RateLimit(0.01, 1))
+	// Sem proxies confiáveis (TRUSTED_PROXIES vazio), o bucket é o da conexão
+	assert.NoError(t, r.SetTrustedProxies(nil))
+
+	assert.Equal(t, http.StatusOK, loginFromForwarded(r, "10.0.0.1", "203.0.113.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, loginFromForwarded(r, "10.0.0.1", "203.0.113.2").Code)
+}
+
+func TestRateLimit_ForwardedForFromTrustedProxy(t *testing.T) {
+	r := newRateLimitRouter(RateLimit(0.01, 1))
+	assert.NoError(t, r.SetTrustedProxies([]string{"10.0.0.1"}))
+
+	// Atrás de um proxy confiável, cada cliente encaminhado tem o seu bucket
+	assert.Equal(t, http.StatusOK, loginFromForwarded(r, "10.0.0.1", "203.0.113.1").Code)
+	assert.Equal(t, http.StatusOK, loginFromForwarded(r, "10.0.0.1", "203.0.113.2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, loginFromForwarded(r, "10.0.0.1", "203.0.113.1").Code)
+
+	// Um cliente que não é proxy não escolhe o próprio IP
+	assert.Equal(t, http.StatusOK, loginFromForwarded(r, "10.0.0.9", "203.0.113.3").Code)
+	assert.Equal(t, http.StatusTooManyRequests, loginFromForwarded(r, "10.0.0.9", "203.0.113.4").Code)
+}
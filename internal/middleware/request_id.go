@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// RequestIDHeader identifica a requisição nos logs, quando enviado pelo cliente
+// ou por um proxy
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limita o tamanho do ID aceito do cliente
+const maxRequestIDLength = 128
+
+// RequestID garante um ID por requisição: reaproveita o X-Request-ID recebido,
+// quando válido, ou gera um UUID. O ID é gravado no contexto do Gin (sob
+// logging.RequestIDKey), no contexto da requisição para logging.With e no
+// cabeçalho de resposta, correlacionando as linhas de log de uma requisição.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(logging.RequestIDKey, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID aceita IDs curtos com letras, dígitos e - _ . : apenas, para que
+// valores do cliente não injetem espaços ou quebras de linha nos logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
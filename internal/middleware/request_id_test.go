@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestIDRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), LogContext())
+	r.GET("/users/login", func(c *gin.Context) {
+		*seen = logging.FieldsFrom(c).RequestID
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequestID_EchoesIncomingHeader(t *testing.T) {
+	var seen string
+	r := newRequestIDRouter(&seen)
+
+	req := httptest.NewRequest("GET", "/users/login", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, "req-123", seen)
+}
+
+func TestRequestID_GeneratesWhenMissing(t *testing.T) {
+	var seen string
+	r := newRequestIDRouter(&seen)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/login", nil))
+
+	id := w.Header().Get(RequestIDHeader)
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err, "um UUID deveria ser gerado, mas foi %q", id)
+	assert.Equal(t, id, seen)
+}
+
+func TestRequestID_ReplacesInvalidHeader(t *testing.T) {
+	var seen string
+	r := newRequestIDRouter(&seen)
+
+	for _, invalid := range []string{"req 1\nfalso=log", strings.Repeat("a", maxRequestIDLength+1)} {
+		req := httptest.NewRequest("GET", "/users/login", nil)
+		req.Header.Set(RequestIDHeader, invalid)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.NotEqual(t, invalid, id)
+		_, err := uuid.Parse(id)
+		assert.NoError(t, err)
+	}
+}
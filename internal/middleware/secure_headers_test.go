@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecureHeadersRouter(opts ...SecureHeadersOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(SecureHeaders(opts...))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestSecureHeaders_Defaults(t *testing.T) {
+	r := newSecureHeadersRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecureHeaders_Configured(t *testing.T) {
+	r := newSecureHeadersRouter(
+		WithFrameOptions("SAMEORIGIN"),
+		WithReferrerPolicy("strict-origin-when-cross-origin"),
+		WithHSTS(365*24*time.Hour, true),
+	)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "SAMEORIGIN", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecureHeaders_HSTSOnlyOverHTTPS(t *testing.T) {
+	r := newSecureHeadersRouter(WithHSTS(time.Hour, false), WithFrameOptions(""))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+	assert.Empty(t, w.Header().Values("X-Frame-Options"))
+
+	// Atrás de um proxy que termina o TLS
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "max-age=3600", w.Header().Get("Strict-Transport-Security"))
+}
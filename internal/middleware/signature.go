@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// SignatureHeader é o cabeçalho com a assinatura HMAC-SHA256 do corpo
+const SignatureHeader = "X-Signature"
+
+// signaturePrefix é o prefixo opcional da assinatura, no formato "sha256=<hex>"
+const signaturePrefix = "sha256="
+
+// SignBody calcula a assinatura esperada em X-Signature para o corpo informado
+func SignBody(secret, body []byte) string {
+	return hex.EncodeToString(bodyMAC(secret, body))
+}
+
+func bodyMAC(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// RequireSignature exige que o corpo da requisição venha assinado com HMAC-SHA256
+// e o segredo compartilhado, em hexadecimal no cabeçalho X-Signature (com ou sem
+// o prefixo "sha256="). A comparação é feita em tempo constante e o corpo é
+// restaurado para o handler. Deve ser aplicado rota a rota, nas chamadas internas.
+func RequireSignature(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		signature := strings.TrimPrefix(strings.TrimSpace(c.GetHeader(SignatureHeader)), signaturePrefix)
+		if signature == "" {
+			logging.Warning("[%s] [%s] Requisição sem assinatura", c.ClientIP(), c.FullPath())
+			errors.GinHandleError(c, errors.ErrSignatureRequired)
+			c.Abort()
+			return
+		}
+		got, err := hex.DecodeString(signature)
+		if err != nil {
+			logging.Warning("[%s] [%s] Assinatura malformada", c.ClientIP(), c.FullPath())
+			errors.GinHandleError(c, errors.ErrInvalidSignature)
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				errors.GinHandleError(c, errors.ErrBadRequest.WithError(err))
+				c.Abort()
+				return
+			}
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !hmac.Equal(got, bodyMAC(secret, body)) {
+			logging.Warning("[%s] [%s] Assinatura inválida", c.ClientIP(), c.FullPath())
+			errors.GinHandleError(c, errors.ErrInvalidSignature)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSigningSecret = []byte("segredo-compartilhado")
+
+func newSignedRouter(received *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/auth/introspect/batch", RequireSignature(testSigningSecret), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		*received = string(body)
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func postSigned(r *gin.Engine, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/auth/introspect/batch", bytes.NewBufferString(body))
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireSignature_ValidSignaturePasses(t *testing.T) {
+	var received string
+	r := newSignedRouter(&received)
+	body := `{"tokens":["a","b"]}`
+
+	w := postSigned(r, body, SignBody(testSigningSecret, []byte(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, received, "o handler deveria receber o corpo original")
+
+	// O prefixo sha256= também é aceito
+	w = postSigned(r, body, "sha256="+SignBody(testSigningSecret, []byte(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequireSignature_TamperedBodyFails(t *testing.T) {
+	var received string
+	r := newSignedRouter(&received)
+	signature := SignBody(testSigningSecret, []byte(`{"tokens":["a"]}`))
+
+	w := postSigned(r, `{"tokens":["a","b"]}`, signature)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Assinatura da requisição inválida")
+	assert.Empty(t, received)
+}
+
+func TestRequireSignature_WrongSecretFails(t *testing.T) {
+	var received string
+	r := newSignedRouter(&received)
+	body := `{"tokens":["a"]}`
+
+	w := postSigned(r, body, SignBody([]byte("outro-segredo"), []byte(body)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postSigned(r, body, "nao-e-hex")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequireSignature_MissingSignatureRejected(t *testing.T) {
+	var received string
+	r := newSignedRouter(&received)
+
+	w := postSigned(r, `{"tokens":["a"]}`, "")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "X-Signature")
+	assert.Empty(t, received)
+}
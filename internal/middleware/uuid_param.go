@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// ErrInvalidIDParam é retornado quando o parâmetro de caminho não é um UUID válido
+var ErrInvalidIDParam = errors.ErrBadRequest.WithMessage("ID inválido: esperado um UUID")
+
+// ValidateUUIDParam recusa com 400 requisições cujo parâmetro de caminho informado
+// não seja um UUID bem formado e normaliza o valor para a forma canônica
+// (minúsculas, com hífens) antes de chegar ao handler.
+func ValidateUUIDParam(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Param(name)
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			logging.Warning("[%s] [%s] Parâmetro %s inválido: %q", c.ClientIP(), c.FullPath(), name, raw)
+			errors.GinHandleError(c, ErrInvalidIDParam)
+			c.Abort()
+			return
+		}
+
+		for i := range c.Params {
+			if c.Params[i].Key == name {
+				c.Params[i].Value = id.String()
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupUUIDParamRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:id", ValidateUUIDParam("id"), func(c *gin.Context) {
+		*seen = c.Param("id")
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestValidateUUIDParam_RejectsMalformedID(t *testing.T) {
+	t.Log("[INICIO] Teste de ID malformado")
+
+	// Arrange
+	var seen string
+	router := setupUUIDParamRouter(&seen)
+
+	for _, id := range []string{"123", "nao-e-uuid", "550e8400-e29b-41d4-a716-44665544000"} {
+		// Act
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/"+id, nil))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, id)
+		assert.Contains(t, w.Body.String(), "UUID", id)
+	}
+	assert.Empty(t, seen, "o handler não deveria ser executado")
+
+	t.Log("[FIM] Teste de ID malformado")
+}
+
+func TestValidateUUIDParam_NormalizesValidID(t *testing.T) {
+	t.Log("[INICIO] Teste de normalização do ID")
+
+	// Arrange
+	var seen string
+	router := setupUUIDParamRouter(&seen)
+
+	// Act
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/users/550E8400-E29B-41D4-A716-446655440000", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440000", seen)
+
+	t.Log("[FIM] Teste de normalização do ID")
+}
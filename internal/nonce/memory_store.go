@@ -0,0 +1,66 @@
+package nonce
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+)
+
+// MemoryStore é uma implementação em memória de domain.NonceStore.
+// Adequada para uma única instância da aplicação; nonces se perdem ao reiniciar.
+type MemoryStore struct {
+	mu     sync.Mutex
+	clock  clock.Clock
+	nonces map[string]time.Time
+}
+
+// Garantir que MemoryStore implementa domain.NonceStore e domain.TokenPurger
+var (
+	_ domain.NonceStore  = (*MemoryStore)(nil)
+	_ domain.TokenPurger = (*MemoryStore)(nil)
+)
+
+// NewMemoryStore cria um novo armazenamento de nonces em memória
+func NewMemoryStore(c clock.Clock) *MemoryStore {
+	if c == nil {
+		c = clock.System()
+	}
+	return &MemoryStore{clock: c, nonces: make(map[string]time.Time)}
+}
+
+// Save registra um nonce válido até expiresAt
+func (s *MemoryStore) Save(nonce string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nonces[nonce] = expiresAt
+	return nil
+}
+
+// Consume remove o nonce, indicando se ele existia e ainda não havia expirado
+func (s *MemoryStore) Consume(nonce string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.nonces[nonce]
+	if !ok {
+		return false, nil
+	}
+	delete(s.nonces, nonce)
+	return s.clock.Now().Before(expiresAt), nil
+}
+
+// PurgeExpired remove os nonces expirados que nunca foram usados
+func (s *MemoryStore) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	purged := 0
+	for nonce, expiresAt := range s.nonces {
+		if !now.Before(expiresAt) {
+			delete(s.nonces, nonce)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,45 @@
+package nonce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_ConsumeOnce(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(clock.Func(func() time.Time { return now }))
+	assert.NoError(t, store.Save("n1", now.Add(time.Minute)))
+	assert.NoError(t, store.Save("n2", now.Add(time.Minute)))
+
+	ok, err := store.Consume("n1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Reuso e nonces desconhecidos são recusados
+	ok, _ = store.Consume("n1")
+	assert.False(t, ok)
+	ok, _ = store.Consume("desconhecido")
+	assert.False(t, ok)
+
+	// Nonce expirado também é recusado
+	now = now.Add(time.Minute)
+	ok, _ = store.Consume("n2")
+	assert.False(t, ok)
+}
+
+func TestMemoryStore_PurgeExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(clock.Func(func() time.Time { return now }))
+	_ = store.Save("velho", now.Add(time.Second))
+	_ = store.Save("novo", now.Add(time.Hour))
+
+	now = now.Add(time.Minute)
+	purged, err := store.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	ok, _ := store.Consume("novo")
+	assert.True(t, ok)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/lucas-de-lima/go-auth-system/prisma/db"
+)
+
+// RevokedTokenRepository implementa domain.TokenBlacklist sobre a tabela
+// revoked_tokens, mantendo as revogações entre reinícios da aplicação
+type RevokedTokenRepository struct {
+	db *db.PrismaClient
+}
+
+// Garantir que RevokedTokenRepository implementa domain.TokenBlacklist e domain.TokenPurger
+var (
+	_ domain.TokenBlacklist = (*RevokedTokenRepository)(nil)
+	_ domain.TokenPurger    = (*RevokedTokenRepository)(nil)
+)
+
+// NewRevokedTokenRepository cria uma nova instância do repositório de tokens revogados
+func NewRevokedTokenRepository(db *db.PrismaClient) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db: db,
+	}
+}
+
+// Add registra o jti como revogado pelos próximos ttl; revogar de novo apenas
+// atualiza a expiração
+func (rr *RevokedTokenRepository) Add(jti string, ttl time.Duration) error {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(ttl)
+
+	_, err := rr.db.RevokedToken.UpsertOne(
+		db.RevokedToken.Jti.Equals(jti),
+	).Create(
+		db.RevokedToken.Jti.Set(jti),
+		db.RevokedToken.ExpiresAt.Set(expiresAt),
+	).Update(
+		db.RevokedToken.ExpiresAt.Set(expiresAt),
+	).Exec(ctx)
+	if err != nil {
+		logging.Error("Erro ao revogar token %s: %v", jti, err)
+		return err
+	}
+
+	return nil
+}
+
+// Contains indica se o jti consta na tabela de tokens revogados
+func (rr *RevokedTokenRepository) Contains(jti string) (bool, error) {
+	ctx := context.Background()
+
+	_, err := rr.db.RevokedToken.FindUnique(
+		db.RevokedToken.Jti.Equals(jti),
+	).Exec(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return false, nil
+		}
+		logging.Error("Erro ao consultar token revogado %s: %v", jti, err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// PurgeExpired remove as revogações de tokens já expirados, que não precisam mais
+// ser recusadas, e retorna quantas foram removidas
+func (rr *RevokedTokenRepository) PurgeExpired() (int, error) {
+	ctx := context.Background()
+
+	result, err := rr.db.RevokedToken.FindMany(
+		db.RevokedToken.ExpiresAt.Lt(time.Now()),
+	).Delete().Exec(ctx)
+	if err != nil {
+		logging.Error("Erro ao remover tokens revogados expirados: %v", err)
+		return 0, err
+	}
+
+	return result.Count, nil
+}
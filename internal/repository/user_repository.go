@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,15 +32,24 @@ func (ur *UserRepository) Create(user *domain.User) error {
 	if user.ID == "" {
 		user.ID = uuid.New().String()
 	}
+	user.Version = 1
 
 	// Cria o usuário no Prisma
 	_, err := ur.db.User.CreateOne(
 		db.User.Email.Set(user.Email),
 		db.User.Password.Set(user.Password),
 		db.User.ID.Set(user.ID),
+		db.User.OrgID.Set(user.OrgID),
 		db.User.Name.Set(user.Name),
 		db.User.CreatedAt.Set(user.CreatedAt),
 		db.User.UpdatedAt.Set(user.UpdatedAt),
+		db.User.CreatedBy.Set(user.CreatedBy),
+		db.User.UpdatedBy.Set(user.UpdatedBy),
+		db.User.Version.Set(user.Version),
+		db.User.MustChangePassword.Set(user.MustChangePassword),
+		db.User.Username.SetOptional(optionalString(user.Username)),
+		db.User.Status.Set(user.Status),
+		db.User.EmailVerified.Set(user.EmailVerified),
 	).Exec(ctx)
 
 	if err != nil {
@@ -69,13 +79,14 @@ func (ur *UserRepository) GetByID(id string) (*domain.User, error) {
 	return mapPrismaUserToDomain(prismaUser), nil
 }
 
-// GetByEmail busca um usuário pelo email
+// GetByEmail busca um usuário pelo email. No modo multi-tenant o mesmo email
+// pode existir em várias organizações; retorna o cadastrado primeiro.
 func (ur *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	ctx := context.Background()
 
-	prismaUser, err := ur.db.User.FindUnique(
+	prismaUser, err := ur.db.User.FindFirst(
 		db.User.Email.Equals(email),
-	).Exec(ctx)
+	).OrderBy(db.User.CreatedAt.Order(db.SortOrderAsc)).Exec(ctx)
 
 	if err != nil {
 		if errors.Is(err, db.ErrNotFound) {
@@ -88,27 +99,196 @@ func (ur *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	return mapPrismaUserToDomain(prismaUser), nil
 }
 
-// Update atualiza os dados de um usuário
+// GetByOrgAndEmail busca um usuário pela chave composta (organização, email)
+func (ur *UserRepository) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	ctx := context.Background()
+
+	prismaUser, err := ur.db.User.FindFirst(
+		db.User.OrgID.Equals(orgID),
+		db.User.Email.Equals(email),
+	).Exec(ctx)
+
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, nil
+		}
+		logging.Error("Erro ao buscar usuário por organização e email: %v", err)
+		return nil, err
+	}
+
+	return mapPrismaUserToDomain(prismaUser), nil
+}
+
+// GetByUsername busca um usuário pelo username
+func (ur *UserRepository) GetByUsername(username string) (*domain.User, error) {
+	ctx := context.Background()
+
+	prismaUser, err := ur.db.User.FindUnique(
+		db.User.Username.Equals(username),
+	).Exec(ctx)
+
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, nil
+		}
+		logging.Error("Erro ao buscar usuário por username: %v", err)
+		return nil, err
+	}
+
+	return mapPrismaUserToDomain(prismaUser), nil
+}
+
+// Update atualiza os dados de um usuário, desde que a versão carregada ainda seja
+// a atual. Caso contrário retorna domain.ErrVersionConflict.
 func (ur *UserRepository) Update(user *domain.User) error {
 	ctx := context.Background()
 
-	_, err := ur.db.User.FindUnique(
+	columns := updateColumns(user)
+	params := make([]db.UserSetParam, 0, len(columns)+2)
+	for field, value := range columns {
+		param, err := userSetParam(field, value)
+		if err != nil {
+			logging.Error("Erro ao montar atualização do usuário: %v", err)
+			return err
+		}
+		params = append(params, param)
+	}
+	params = append(params, db.User.UpdatedAt.Set(time.Now()), db.User.Version.Increment(1))
+
+	result, err := ur.db.User.FindMany(
 		db.User.ID.Equals(user.ID),
-	).Update(
-		db.User.Email.Set(user.Email),
-		db.User.Password.Set(user.Password),
-		db.User.Name.Set(user.Name),
-		db.User.UpdatedAt.Set(time.Now()),
-	).Exec(ctx)
+		db.User.Version.Equals(user.Version),
+	).Update(params...).Exec(ctx)
 
 	if err != nil {
 		logging.Error("Erro ao atualizar usuário: %v", err)
 		return err
 	}
 
+	if result.Count == 0 {
+		return domain.ErrVersionConflict
+	}
+
+	user.Version++
 	return nil
 }
 
+// updateColumns lista as colunas gravadas por Update, todas as editáveis do
+// usuário, incluindo as roles alteradas pelo admin
+func updateColumns(user *domain.User) map[string]any {
+	return map[string]any{
+		domain.UserFieldEmail:              user.Email,
+		domain.UserFieldUsername:           user.Username,
+		domain.UserFieldPassword:           user.Password,
+		domain.UserFieldName:               user.Name,
+		domain.UserFieldRoles:              user.Roles,
+		domain.UserFieldUpdatedBy:          user.UpdatedBy,
+		domain.UserFieldStatus:             user.Status,
+		domain.UserFieldMustChangePassword: user.MustChangePassword,
+		domain.UserFieldEmailVerified:      user.EmailVerified,
+		domain.UserFieldPasswordChangedAt:  user.PasswordChangedAt,
+		domain.UserFieldTokensRevokedAt:    user.TokensRevokedAt,
+	}
+}
+
+// UpdateFields atualiza apenas as colunas informadas, sem sobrescrever alterações
+// concorrentes nos demais campos
+func (ur *UserRepository) UpdateFields(id string, fields map[string]any) error {
+	ctx := context.Background()
+
+	params := make([]db.UserSetParam, 0, len(fields)+2)
+	for field, value := range fields {
+		param, err := userSetParam(field, value)
+		if err != nil {
+			logging.Error("Erro ao montar atualização parcial do usuário: %v", err)
+			return err
+		}
+		params = append(params, param)
+	}
+	params = append(params, db.User.UpdatedAt.Set(time.Now()), db.User.Version.Increment(1))
+
+	_, err := ur.db.User.FindUnique(
+		db.User.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+
+	if err != nil {
+		logging.Error("Erro ao atualizar campos do usuário: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// userSetParam converte um campo domain.UserField* no parâmetro Prisma correspondente
+func userSetParam(field string, value any) (db.UserSetParam, error) {
+	switch field {
+	case domain.UserFieldEmail:
+		if v, ok := value.(string); ok {
+			return db.User.Email.Set(v), nil
+		}
+	case domain.UserFieldUsername:
+		if v, ok := value.(string); ok {
+			return db.User.Username.SetOptional(optionalString(v)), nil
+		}
+	case domain.UserFieldPassword:
+		if v, ok := value.(string); ok {
+			return db.User.Password.Set(v), nil
+		}
+	case domain.UserFieldName:
+		if v, ok := value.(string); ok {
+			return db.User.Name.Set(v), nil
+		}
+	case domain.UserFieldRoles:
+		if v, ok := value.([]string); ok {
+			return db.User.Roles.Set(v), nil
+		}
+	case domain.UserFieldUpdatedBy:
+		if v, ok := value.(string); ok {
+			return db.User.UpdatedBy.Set(v), nil
+		}
+	case domain.UserFieldStatus:
+		if v, ok := value.(string); ok {
+			return db.User.Status.Set(v), nil
+		}
+	case domain.UserFieldMustChangePassword:
+		if v, ok := value.(bool); ok {
+			return db.User.MustChangePassword.Set(v), nil
+		}
+	case domain.UserFieldEmailVerified:
+		if v, ok := value.(bool); ok {
+			return db.User.EmailVerified.Set(v), nil
+		}
+	case domain.UserFieldPasswordChangedAt:
+		if v, ok := value.(time.Time); ok {
+			return db.User.PasswordChangedAt.SetOptional(optionalTime(v)), nil
+		}
+	case domain.UserFieldTokensRevokedAt:
+		if v, ok := value.(time.Time); ok {
+			return db.User.TokensRevokedAt.SetOptional(optionalTime(v)), nil
+		}
+	default:
+		return nil, fmt.Errorf("campo desconhecido: %s", field)
+	}
+	return nil, fmt.Errorf("tipo inválido para o campo %s: %T", field, value)
+}
+
+// optionalTime grava horários zerados como NULL
+func optionalTime(v time.Time) *time.Time {
+	if v.IsZero() {
+		return nil
+	}
+	return &v
+}
+
+// optionalString grava strings vazias como NULL, preservando a unicidade de
+// colunas opcionais como username
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
 // Delete remove um usuário pelo ID
 func (ur *UserRepository) Delete(id string) error {
 	ctx := context.Background()
@@ -140,6 +320,92 @@ func (ur *UserRepository) List() ([]*domain.User, error) {
 	return users, nil
 }
 
+// ListCreatedBetween lista os usuários criados no intervalo [from, to); limites
+// zerados não restringem a busca
+func (ur *UserRepository) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	ctx := context.Background()
+	var filters []db.UserWhereParam
+	if !from.IsZero() {
+		filters = append(filters, db.User.CreatedAt.Gte(from))
+	}
+	if !to.IsZero() {
+		filters = append(filters, db.User.CreatedAt.Lt(to))
+	}
+	prismaUsers, err := ur.db.User.FindMany(filters...).OrderBy(db.User.CreatedAt.Order(db.SortOrderAsc)).Exec(ctx)
+	if err != nil {
+		logging.Error("Erro ao listar usuários por data de criação: %v", err)
+		return nil, err
+	}
+	users := make([]*domain.User, 0, len(prismaUsers))
+	for _, pu := range prismaUsers {
+		users = append(users, mapPrismaUserToDomain(&pu))
+	}
+	return users, nil
+}
+
+// ListPaginated retorna até limit usuários a partir de offset, ordenados pela
+// data de criação, junto com o total de usuários cadastrados
+func (ur *UserRepository) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	ctx := context.Background()
+	prismaUsers, err := ur.db.User.FindMany().
+		OrderBy(db.User.CreatedAt.Order(db.SortOrderAsc)).
+		Skip(offset).
+		Take(limit).
+		Exec(ctx)
+	if err != nil {
+		logging.Error("Erro ao listar página de usuários: %v", err)
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		Total int `json:"total"`
+	}
+	if err := ur.db.Prisma.QueryRaw(`SELECT COUNT(*)::int AS total FROM users`).Exec(ctx, &rows); err != nil {
+		logging.Error("Erro ao contar usuários: %v", err)
+		return nil, 0, err
+	}
+	total := 0
+	if len(rows) > 0 {
+		total = rows[0].Total
+	}
+
+	users := make([]*domain.User, 0, len(prismaUsers))
+	for _, pu := range prismaUsers {
+		users = append(users, mapPrismaUserToDomain(&pu))
+	}
+	return users, total, nil
+}
+
+// Stats conta o total de usuários, os com email verificado e os administradores
+// em uma única consulta agregada
+func (ur *UserRepository) Stats() (*domain.UserStats, error) {
+	ctx := context.Background()
+
+	var rows []struct {
+		Total    int `json:"total"`
+		Verified int `json:"verified"`
+		Admins   int `json:"admins"`
+	}
+	err := ur.db.Prisma.QueryRaw(`
+		SELECT
+			COUNT(*)::int AS total,
+			COUNT(*) FILTER (WHERE email_verified)::int AS verified,
+			COUNT(*) FILTER (WHERE $1 = ANY(roles))::int AS admins
+		FROM users`, domain.RoleAdmin).Exec(ctx, &rows)
+	if err != nil {
+		logging.Error("Erro ao contar usuários: %v", err)
+		return nil, err
+	}
+
+	stats := &domain.UserStats{}
+	if len(rows) > 0 {
+		stats.Total = rows[0].Total
+		stats.Verified = rows[0].Verified
+		stats.Admins = rows[0].Admins
+	}
+	return stats, nil
+}
+
 // mapPrismaUserToDomain converte um model Prisma para o modelo de domínio
 func mapPrismaUserToDomain(prismaUser *db.UserModel) *domain.User {
 	if prismaUser == nil {
@@ -151,13 +417,50 @@ func mapPrismaUserToDomain(prismaUser *db.UserModel) *domain.User {
 		name = *prismaUser.InnerUser.Name
 	}
 
+	createdBy := ""
+	if prismaUser.InnerUser.CreatedBy != nil {
+		createdBy = *prismaUser.InnerUser.CreatedBy
+	}
+
+	updatedBy := ""
+	if prismaUser.InnerUser.UpdatedBy != nil {
+		updatedBy = *prismaUser.InnerUser.UpdatedBy
+	}
+
+	username := ""
+	if prismaUser.InnerUser.Username != nil {
+		username = *prismaUser.InnerUser.Username
+	}
+
+	var passwordChangedAt time.Time
+	if prismaUser.InnerUser.PasswordChangedAt != nil {
+		passwordChangedAt = *prismaUser.InnerUser.PasswordChangedAt
+	}
+
+	var tokensRevokedAt time.Time
+	if prismaUser.InnerUser.TokensRevokedAt != nil {
+		tokensRevokedAt = *prismaUser.InnerUser.TokensRevokedAt
+	}
+
 	return &domain.User{
 		ID:        prismaUser.ID,
+		OrgID:     prismaUser.OrgID,
 		Email:     prismaUser.Email,
+		Username:  username,
 		Password:  prismaUser.Password,
 		Name:      name,
 		Roles:     prismaUser.InnerUser.Roles,
 		CreatedAt: prismaUser.CreatedAt,
 		UpdatedAt: prismaUser.UpdatedAt,
+		CreatedBy: createdBy,
+		UpdatedBy: updatedBy,
+		Version:   prismaUser.Version,
+
+		MustChangePassword: prismaUser.MustChangePassword,
+		Status:             prismaUser.Status,
+		EmailVerified:      prismaUser.EmailVerified,
+
+		PasswordChangedAt: passwordChangedAt,
+		TokensRevokedAt:   tokensRevokedAt,
 	}
 }
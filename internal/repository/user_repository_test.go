@@ -422,3 +422,22 @@ func TestDataStructureValidation(t *testing.T) {
 	assert.False(t, user.CreatedAt.IsZero())
 	assert.False(t, user.UpdatedAt.IsZero())
 }
+
+func TestUpdateColumns_IncludesRoles(t *testing.T) {
+	user := &domain.User{
+		Email:     "admin@example.com",
+		Roles:     []string{domain.RoleUser, domain.RoleAdmin},
+		Status:    domain.UserStatusActive,
+		UpdatedBy: "admin-id",
+	}
+
+	columns := updateColumns(user)
+
+	// As roles alteradas pelo admin fazem parte do payload de Update
+	assert.Equal(t, user.Roles, columns[domain.UserFieldRoles])
+	// Toda coluna listada precisa ter um parâmetro Prisma correspondente
+	for field, value := range columns {
+		_, err := userSetParam(field, value)
+		assert.NoError(t, err, field)
+	}
+}
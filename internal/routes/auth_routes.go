@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/introspect"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+)
+
+// AuthRoutes define as rotas de validação de tokens para gateways e serviços
+type AuthRoutes struct {
+	introspectController *introspect.IntrospectController
+	authMiddleware       *middleware.AuthMiddleware
+	signature            gin.HandlerFunc
+}
+
+// NewAuthRoutes cria uma nova instância de rotas de autenticação
+func NewAuthRoutes(introspectController *introspect.IntrospectController, jwtService *auth.JWTService, authOpts ...middleware.AuthOption) *AuthRoutes {
+	return &AuthRoutes{
+		introspectController: introspectController,
+		authMiddleware:       middleware.NewAuthMiddleware(jwtService, authOpts...),
+	}
+}
+
+// WithRequestSigning exige o middleware de assinatura informado nas rotas de
+// introspecção, usadas por serviços internos, no lugar do access token de admin
+func (ar *AuthRoutes) WithRequestSigning(signature gin.HandlerFunc) *AuthRoutes {
+	ar.signature = signature
+	return ar
+}
+
+// protected antepõe ao handler o middleware de assinatura, quando habilitado, ou
+// a autenticação com role 'admin', para que a rota nunca fique pública
+func (ar *AuthRoutes) protected(handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if ar.signature != nil {
+		return append([]gin.HandlerFunc{ar.signature}, handlers...)
+	}
+	return append([]gin.HandlerFunc{ar.authMiddleware.GinAuthenticate(), ar.authMiddleware.GinRequireRole(domain.RoleAdmin)}, handlers...)
+}
+
+// Setup configura as rotas no router fornecido
+func (ar *AuthRoutes) Setup(router *gin.Engine) {
+	authRoutes := router.Group("/auth")
+	{
+		authRoutes.POST("/introspect/batch", ar.protected(ar.introspectController.IntrospectBatch)...)
+	}
+}
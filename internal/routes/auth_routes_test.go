@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/introspect"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type introspectUsers map[string]*domain.User
+
+func (u introspectUsers) GetByID(id string) (*domain.User, error) { return u[id], nil }
+
+func postIntrospect(router *gin.Engine, token string, headers map[string]string) int {
+	req := httptest.NewRequest("POST", "/auth/introspect/batch", bytes.NewBufferString(`{"tokens":["a"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAuthRoutes_IntrospectRequiresAdminByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret", 1, "test-refresh", 1)
+	ic := introspect.NewIntrospectController(jwtService, introspectUsers{}, 0)
+	router := gin.New()
+	NewAuthRoutes(ic, jwtService).Setup(router)
+
+	admin, _ := jwtService.GenerateToken(&domain.User{ID: "adm", Roles: []string{domain.RoleAdmin}})
+	user, _ := jwtService.GenerateToken(&domain.User{ID: "u1", Roles: []string{domain.RoleUser}})
+
+	assert.Equal(t, http.StatusUnauthorized, postIntrospect(router, "", nil))
+	assert.Equal(t, http.StatusForbidden, postIntrospect(router, user, nil))
+	assert.Equal(t, http.StatusOK, postIntrospect(router, admin, nil))
+}
+
+func TestAuthRoutes_IntrospectWithRequestSigning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret", 1, "test-refresh", 1)
+	ic := introspect.NewIntrospectController(jwtService, introspectUsers{}, 0)
+	router := gin.New()
+	NewAuthRoutes(ic, jwtService).
+		WithRequestSigning(middleware.RequireSignature([]byte("segredo-compartilhado"))).
+		Setup(router)
+
+	assert.Equal(t, http.StatusUnauthorized, postIntrospect(router, "", nil))
+	signature := middleware.SignBody([]byte("segredo-compartilhado"), []byte(`{"tokens":["a"]}`))
+	assert.Equal(t, http.StatusOK, postIntrospect(router, "", map[string]string{middleware.SignatureHeader: signature}))
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/discovery"
+)
+
+// DiscoveryRoutes define as rotas públicas de descoberta (/.well-known)
+type DiscoveryRoutes struct {
+	discoveryController *discovery.DiscoveryController
+}
+
+// NewDiscoveryRoutes cria uma nova instância de rotas de descoberta
+func NewDiscoveryRoutes(discoveryController *discovery.DiscoveryController) *DiscoveryRoutes {
+	return &DiscoveryRoutes{discoveryController: discoveryController}
+}
+
+// Setup configura as rotas no router fornecido
+func (dr *DiscoveryRoutes) Setup(router *gin.Engine) {
+	wellKnown := router.Group("/.well-known")
+	{
+		wellKnown.GET("/openid-configuration", dr.discoveryController.OpenIDConfiguration)
+		wellKnown.GET("/jwks.json", dr.discoveryController.JWKS)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/nonce"
+)
+
+// NonceRoutes define a rota pública de emissão de nonces
+type NonceRoutes struct {
+	nonceController *nonce.NonceController
+}
+
+// NewNonceRoutes cria uma nova instância de rotas de nonce
+func NewNonceRoutes(nonceController *nonce.NonceController) *NonceRoutes {
+	return &NonceRoutes{nonceController: nonceController}
+}
+
+// Setup configura as rotas no router fornecido
+func (nr *NonceRoutes) Setup(router *gin.Engine) {
+	router.GET("/nonce", nr.nonceController.Issue)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// ConfigureRouter aplica o comportamento de roteamento da API. Caminhos com barra
+// final (ex.: /admin/users/) e caminhos sem parâmetros obrigatórios (ex.: /users/)
+// não são redirecionados: respondem 404 com o erro JSON padrão, para que clientes
+// não dependam de redirecionamentos que descartam o corpo de POST/PUT.
+func ConfigureRouter(router *gin.Engine) {
+	router.RedirectTrailingSlash = false
+	router.RedirectFixedPath = false
+	router.NoRoute(func(ctx *gin.Context) {
+		errors.GinHandleError(ctx, errors.ErrRouteNotFound)
+	})
+}
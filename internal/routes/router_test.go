@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureRouter_TrailingSlashAndMissingParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	ConfigureRouter(router)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/admin/users", ok)
+	router.GET("/users/:id", ok)
+	router.POST("/users/login", ok)
+
+	cases := []struct {
+		method, path string
+		want         int
+	}{
+		{"GET", "/admin/users", http.StatusOK},
+		{"GET", "/admin/users/", http.StatusNotFound},
+		{"GET", "/users/123", http.StatusOK},
+		{"GET", "/users/", http.StatusNotFound},
+		{"POST", "/users/login/", http.StatusNotFound},
+		{"GET", "/ADMIN/users", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+		assert.Equal(t, tc.want, w.Code, "%s %s", tc.method, tc.path)
+		assert.Empty(t, w.Header().Get("Location"), "%s %s não deveria redirecionar", tc.method, tc.path)
+		if tc.want == http.StatusNotFound {
+			assert.Contains(t, w.Body.String(), "Rota não encontrada", "%s %s", tc.method, tc.path)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/status"
+)
+
+// StatusRoutes define as rotas públicas de saúde da aplicação
+type StatusRoutes struct {
+	statusController *status.StatusController
+}
+
+// NewStatusRoutes cria uma nova instância de rotas de status
+func NewStatusRoutes(statusController *status.StatusController) *StatusRoutes {
+	return &StatusRoutes{statusController: statusController}
+}
+
+// Setup configura as rotas no router fornecido
+func (sr *StatusRoutes) Setup(router *gin.Engine) {
+	router.GET("/health", sr.statusController.Health)
+	router.GET("/status", sr.statusController.Status)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
 	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
 )
 
@@ -12,41 +13,117 @@ type UserRoutes struct {
 	userController  *user.UserController
 	authMiddleware  *middleware.AuthMiddleware
 	adminController *user.AdminController
+
+	activityController *user.ActivityController
+	auditController    *user.AuditController
+	requireNonce       gin.HandlerFunc
+	rateLimit          gin.HandlerFunc
 }
 
 // NewUserRoutes cria uma nova instância de rotas de usuário
-func NewUserRoutes(userController *user.UserController, jwtService *auth.JWTService, adminController *user.AdminController) *UserRoutes {
+func NewUserRoutes(userController *user.UserController, jwtService *auth.JWTService, adminController *user.AdminController, authOpts ...middleware.AuthOption) *UserRoutes {
 	return &UserRoutes{
 		userController:  userController,
-		authMiddleware:  middleware.NewAuthMiddleware(jwtService),
+		authMiddleware:  middleware.NewAuthMiddleware(jwtService, authOpts...),
 		adminController: adminController,
 	}
 }
 
+// WithActivityController habilita GET /users/:id/activity
+func (ur *UserRoutes) WithActivityController(activityController *user.ActivityController) *UserRoutes {
+	ur.activityController = activityController
+	return ur
+}
+
+// WithAuditController habilita GET /admin/audit
+func (ur *UserRoutes) WithAuditController(auditController *user.AuditController) *UserRoutes {
+	ur.auditController = auditController
+	return ur
+}
+
+// WithNonceProtection exige um nonce de uso único (middleware.RequireNonce) no
+// registro, na troca e na redefinição de senha, recusando envios duplicados
+func (ur *UserRoutes) WithNonceProtection(requireNonce gin.HandlerFunc) *UserRoutes {
+	ur.requireNonce = requireNonce
+	return ur
+}
+
+// WithRateLimit aplica o limite por IP (middleware.RateLimit) ao login e ao registro
+func (ur *UserRoutes) WithRateLimit(rateLimit gin.HandlerFunc) *UserRoutes {
+	ur.rateLimit = rateLimit
+	return ur
+}
+
+// throttled antepõe o limite por IP, quando habilitado, ao handler
+func (ur *UserRoutes) throttled(handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if ur.rateLimit == nil {
+		return handlers
+	}
+	return append([]gin.HandlerFunc{ur.rateLimit}, handlers...)
+}
+
+// sensitive antepõe a verificação de nonce, quando habilitada, ao handler
+func (ur *UserRoutes) sensitive(handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if ur.requireNonce == nil {
+		return handlers
+	}
+	return append([]gin.HandlerFunc{ur.requireNonce}, handlers...)
+}
+
 // Setup configura as rotas no router fornecido
 func (ur *UserRoutes) Setup(router *gin.Engine) {
+	// IDs de usuário são UUIDs; valores malformados recebem 400 antes do handler
+	validID := middleware.ValidateUUIDParam("id")
+
 	// Rotas públicas (não autenticadas)
 	publicRoutes := router.Group("/users")
 	{
-		publicRoutes.POST("/register", ur.userController.Register)
-		publicRoutes.POST("/login", ur.userController.Login)
+		publicRoutes.POST("/register", ur.throttled(ur.sensitive(ur.userController.Register)...)...)
+		publicRoutes.POST("/login", ur.throttled(ur.userController.Login)...)
 		publicRoutes.POST("/refresh", ur.userController.RefreshToken)
+		publicRoutes.POST("/password-reset/request", ur.userController.RequestPasswordReset)
+		publicRoutes.POST("/password-reset/confirm", ur.sensitive(ur.userController.ConfirmPasswordReset)...)
+		publicRoutes.GET("/verify", ur.userController.VerifyEmail)
 	}
 
 	// Rotas protegidas (requerem autenticação)
 	protectedRoutes := router.Group("/users")
 	protectedRoutes.Use(ur.authMiddleware.GinAuthenticate())
 	{
+		protectedRoutes.GET("/me", ur.userController.Me)
+		protectedRoutes.GET("/:id", validID, ur.userController.GetByID)
+		protectedRoutes.POST("/refresh/initial", ur.userController.InitialRefreshToken)
 		protectedRoutes.POST("/logout", ur.userController.Logout)
+		protectedRoutes.GET("/sessions", ur.userController.ListSessions)
+		protectedRoutes.POST("/sessions/rotate", ur.userController.RotateSessions)
+		protectedRoutes.POST("/:id/logout-all", validID, ur.userController.LogoutAll)
+		protectedRoutes.POST("/:id/delete/request", validID, ur.userController.RequestDeletion)
+		protectedRoutes.POST("/:id/delete/confirm", ur.sensitive(validID, ur.userController.ConfirmDeletion)...)
+		if ur.activityController != nil {
+			protectedRoutes.GET("/:id/activity", validID, ur.activityController.List)
+		}
+	}
+
+	// Troca de senha aceita também o token restrito emitido a usuários com troca obrigatória
+	passwordRoutes := router.Group("/users")
+	passwordRoutes.Use(ur.authMiddleware.GinAuthenticateAllowingPasswordChange())
+	{
+		passwordRoutes.PUT("/:id/password", ur.sensitive(validID, ur.userController.ChangePassword)...)
 	}
 
 	// Rotas de admin (protegidas por autenticação e role 'admin')
 	adminRoutes := router.Group("/admin")
-	adminRoutes.Use(ur.authMiddleware.GinAuthenticate(), ur.authMiddleware.GinRequireRole("admin"))
+	adminRoutes.Use(ur.authMiddleware.GinAuthenticate(), ur.authMiddleware.GinRequireRole(domain.RoleAdmin))
 	{
+		adminRoutes.GET("/config", ur.adminController.Config)
+		adminRoutes.GET("/stats", ur.adminController.Stats)
 		adminRoutes.GET("/users", ur.adminController.ListAll)
-		adminRoutes.GET("/users/:id", ur.adminController.GetByID)
-		adminRoutes.PUT("/users/:id", ur.adminController.Update)
-		adminRoutes.DELETE("/users/:id", ur.adminController.Delete)
+		adminRoutes.GET("/users/by-email", ur.adminController.GetByEmail)
+		adminRoutes.GET("/users/:id", validID, ur.adminController.GetByID)
+		adminRoutes.PUT("/users/:id", validID, ur.adminController.Update)
+		adminRoutes.DELETE("/users/:id", validID, ur.adminController.Delete)
+		if ur.auditController != nil {
+			adminRoutes.GET("/audit", ur.authMiddleware.GinRequirePermission(domain.PermissionAuditRead), ur.auditController.List)
+		}
 	}
 }
@@ -0,0 +1,37 @@
+package scheduler
+
+import "sync"
+
+// Registry reúne as varreduras da aplicação para que a saúde delas seja
+// consultada em um só lugar
+type Registry struct {
+	mu       sync.RWMutex
+	sweepers []*Sweeper
+}
+
+// NewRegistry cria um registro vazio
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adiciona varreduras ao registro; valores nil são ignorados
+func (r *Registry) Register(sweepers ...*Sweeper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range sweepers {
+		if s != nil {
+			r.sweepers = append(r.sweepers, s)
+		}
+	}
+}
+
+// Statuses retorna o estado de cada varredura registrada, na ordem de registro
+func (r *Registry) Statuses() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	statuses := make([]Status, 0, len(r.sweepers))
+	for _, s := range r.sweepers {
+		statuses = append(statuses, s.Status())
+	}
+	return statuses
+}
@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// StallFactor é quantos intervalos sem execução concluída marcam uma varredura
+// em andamento como travada
+const StallFactor = 2
+
+// Task é uma rotina de limpeza que retorna quantos itens removeu
+type Task func() (int, error)
+
+// Option configura um Sweeper
+type Option func(*Sweeper)
+
+// WithClock define o relógio usado para registrar as execuções
+func WithClock(c clock.Clock) Option {
+	return func(s *Sweeper) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// Status é um retrato da saúde de uma varredura
+type Status struct {
+	Name        string
+	Interval    time.Duration
+	Running     bool
+	StartedAt   time.Time
+	LastRun     time.Time
+	LastRemoved int
+	LastError   string
+	Runs        int64
+	Stalled     bool
+}
+
+// Sweeper executa uma Task periodicamente em segundo plano
+type Sweeper struct {
+	name     string
+	interval time.Duration
+	task     Task
+	clock    clock.Clock
+
+	stop     chan struct{}
+	done     chan struct{}
+	startMu  sync.Mutex
+	started  bool
+	stopOnce sync.Once
+
+	statusMu    sync.Mutex
+	running     bool
+	startedAt   time.Time
+	lastRun     time.Time
+	lastRemoved int
+	lastErr     error
+	runs        int64
+}
+
+// NewSweeper cria uma varredura periódica; interval <= 0 a mantém desabilitada
+func NewSweeper(name string, interval time.Duration, task Task, opts ...Option) *Sweeper {
+	s := &Sweeper{
+		name:     name,
+		interval: interval,
+		task:     task,
+		clock:    clock.System(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start inicia a varredura em uma goroutine; chamadas repetidas são ignoradas
+func (s *Sweeper) Start() {
+	s.startMu.Lock()
+	defer s.startMu.Unlock()
+	if s.started || s.interval <= 0 {
+		return
+	}
+	s.started = true
+
+	s.statusMu.Lock()
+	s.running = true
+	s.startedAt = s.clock.Now()
+	s.statusMu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	logging.Info("Varredura %s iniciada (intervalo: %s)", s.name, s.interval)
+}
+
+// Stop interrompe a varredura e aguarda a execução em andamento terminar
+func (s *Sweeper) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.startMu.Lock()
+		started := s.started
+		s.startMu.Unlock()
+		if started {
+			<-s.done
+		}
+		s.statusMu.Lock()
+		s.running = false
+		s.statusMu.Unlock()
+	})
+}
+
+// RunOnce executa a tarefa imediatamente, registrando o resultado no log
+func (s *Sweeper) RunOnce() (int, error) {
+	removed, err := s.task()
+
+	s.statusMu.Lock()
+	s.lastRun = s.clock.Now()
+	s.lastRemoved = removed
+	s.lastErr = err
+	s.runs++
+	s.statusMu.Unlock()
+
+	if err != nil {
+		logging.Error("Erro na varredura %s: %v", s.name, err)
+		return removed, err
+	}
+	if removed > 0 {
+		logging.Info("Varredura %s removeu %d itens", s.name, removed)
+	}
+	return removed, nil
+}
+
+// Status retorna a última execução da varredura. Uma varredura em andamento
+// sem execução concluída há mais de StallFactor intervalos, contados a partir
+// da última execução ou do início, é reportada como travada.
+func (s *Sweeper) Status() Status {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	status := Status{
+		Name:        s.name,
+		Interval:    s.interval,
+		Running:     s.running,
+		StartedAt:   s.startedAt,
+		LastRun:     s.lastRun,
+		LastRemoved: s.lastRemoved,
+		Runs:        s.runs,
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	if s.running {
+		since := s.startedAt
+		if s.lastRun.After(since) {
+			since = s.lastRun
+		}
+		status.Stalled = s.clock.Now().Sub(since) > StallFactor*s.interval
+	}
+	return status
+}
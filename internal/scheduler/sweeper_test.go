@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweeper_RunsPeriodicallyUntilStopped(t *testing.T) {
+	var runs atomic.Int32
+	s := NewSweeper("teste", 5*time.Millisecond, func() (int, error) {
+		runs.Add(1)
+		return 1, nil
+	})
+
+	s.Start()
+	s.Start() // idempotente
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	s.Stop()
+	s.Stop() // idempotente
+
+	after := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, after, runs.Load(), "não deveria executar após Stop")
+}
+
+func TestSweeper_DisabledWithoutInterval(t *testing.T) {
+	var runs atomic.Int32
+	s := NewSweeper("desabilitada", 0, func() (int, error) {
+		runs.Add(1)
+		return 0, nil
+	})
+
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+
+	assert.Zero(t, runs.Load())
+}
+
+func TestSweeper_RunOnceReportsErrors(t *testing.T) {
+	s := NewSweeper("falha", time.Hour, func() (int, error) { return 0, errors.New("banco indisponível") })
+
+	_, err := s.RunOnce()
+
+	assert.Error(t, err)
+}
+
+func TestSweeper_StatusReportsLastRun(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSweeper("status", time.Minute, func() (int, error) { return 3, nil },
+		WithClock(clock.Func(func() time.Time { return now })))
+
+	assert.Zero(t, s.Status().LastRun, "sem execuções, não há última execução")
+
+	s.RunOnce()
+	first := s.Status()
+	assert.Equal(t, now, first.LastRun)
+	assert.Equal(t, 3, first.LastRemoved)
+	assert.EqualValues(t, 1, first.Runs)
+
+	now = now.Add(time.Minute)
+	s.RunOnce()
+	second := s.Status()
+	assert.True(t, second.LastRun.After(first.LastRun), "a última execução deveria avançar após um ciclo")
+	assert.EqualValues(t, 2, second.Runs)
+}
+
+func TestSweeper_StatusAdvancesAfterTick(t *testing.T) {
+	s := NewSweeper("tick", 5*time.Millisecond, func() (int, error) { return 0, nil })
+	s.Start()
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool { return !s.Status().LastRun.IsZero() }, time.Second, time.Millisecond)
+	first := s.Status().LastRun
+	assert.Eventually(t, func() bool { return s.Status().LastRun.After(first) }, time.Second, time.Millisecond)
+}
+
+func TestSweeper_StatusDetectsStall(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSweeper("travada", time.Hour, func() (int, error) { return 0, errors.New("banco indisponível") },
+		WithClock(clock.Func(func() time.Time { return now })))
+	s.Start()
+	defer s.Stop()
+
+	status := s.Status()
+	assert.True(t, status.Running)
+	assert.False(t, status.Stalled)
+
+	// Sem nenhuma execução por mais de StallFactor intervalos
+	now = now.Add(StallFactor*time.Hour + time.Second)
+	assert.True(t, s.Status().Stalled)
+
+	// Uma execução, mesmo com erro, reinicia a contagem
+	s.RunOnce()
+	status = s.Status()
+	assert.False(t, status.Stalled)
+	assert.Equal(t, "banco indisponível", status.LastError)
+}
+
+func TestSweeper_StoppedIsNotStalled(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSweeper("parada", time.Hour, func() (int, error) { return 0, nil },
+		WithClock(clock.Func(func() time.Time { return now })))
+	s.Start()
+	s.Stop()
+
+	now = now.Add(10 * time.Hour)
+	status := s.Status()
+	assert.False(t, status.Running)
+	assert.False(t, status.Stalled)
+}
+
+func TestRegistry_Statuses(t *testing.T) {
+	r := NewRegistry()
+	a := NewSweeper("a", time.Hour, func() (int, error) { return 0, nil })
+	b := NewSweeper("b", time.Hour, func() (int, error) { return 0, nil })
+	r.Register(a, nil, b)
+
+	statuses := r.Statuses()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.Equal(t, "b", statuses[1].Name)
+	assert.NotNil(t, NewRegistry().Statuses())
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/config"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// DefaultShutdownTimeout é o tempo máximo de espera pelas requisições em
+// andamento quando o encerramento é solicitado
+const DefaultShutdownTimeout = 15 * time.Second
+
+// New cria o servidor HTTP na porta e com os timeouts de leitura, escrita e
+// ociosidade da configuração
+func New(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+}
+
+// Serve atende conexões de listener até ctx ser cancelado (ex.: SIGINT/SIGTERM
+// via signal.NotifyContext). Então para de aceitar conexões e aguarda as
+// requisições em andamento por até timeout antes de retornar. Falhas ao servir
+// e o estouro do prazo de encerramento são retornados; o encerramento normal
+// retorna nil.
+func Serve(ctx context.Context, srv *http.Server, listener net.Listener, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	logging.Info("Encerrando o servidor; aguardando requisições em andamento por até %v", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Conexões que não terminaram a tempo são fechadas à força
+		srv.Close()
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_UsesConfiguredTimeouts(t *testing.T) {
+	handler := http.NotFoundHandler()
+	srv := New(config.ServerConfig{
+		Port:         9090,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  2 * time.Minute,
+	}, handler)
+
+	assert.Equal(t, ":9090", srv.Addr)
+	assert.Equal(t, 5*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 10*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, srv.IdleTimeout)
+	assert.NotNil(t, srv.Handler)
+}
+
+// startServer inicia Serve em background com um handler que demora delay e
+// retorna a URL, a função que solicita o encerramento e o canal com o resultado
+func startServer(t *testing.T, delay, timeout time.Duration) (string, context.CancelFunc, <-chan error, <-chan struct{}) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{}, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		time.Sleep(delay)
+		io.WriteString(w, "ok")
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, srv, listener, timeout) }()
+	return "http://" + listener.Addr().String(), cancel, done, started
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	url, cancel, done, started := startServer(t, 200*time.Millisecond, 2*time.Second)
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	// O encerramento é pedido com a requisição em andamento
+	<-started
+	begin := time.Now()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(begin), 2*time.Second)
+	case <-time.After(3 * time.Second):
+		t.Fatal("Serve não retornou dentro do prazo de encerramento")
+	}
+	assert.Equal(t, "ok", <-response)
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	url, cancel, done, started := startServer(t, 2*time.Second, 100*time.Millisecond)
+	go http.Get(url)
+
+	<-started
+	begin := time.Now()
+	cancel()
+
+	// A requisição lenta não impede o retorno após o prazo
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(begin), time.Second)
+	case <-time.After(time.Second):
+		t.Fatal("Serve deveria retornar ao estourar o prazo de encerramento")
+	}
+}
+
+func TestServe_ReturnsServeError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener.Close()
+
+	err = Serve(context.Background(), &http.Server{}, listener, time.Second)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, http.ErrServerClosed)
+}
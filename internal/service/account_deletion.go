@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// RequestDeletion emite o token que confirma a exclusão da conta do usuário. A
+// exclusão só ocorre quando o token é apresentado em ConfirmDeletion.
+func (us *UserService) RequestDeletion(userID string) (string, error) {
+	user, err := us.userRepo.GetByID(userID)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para exclusão: %v", err)
+		return "", errors.ErrInternalServer.WithError(err)
+	}
+	if user == nil {
+		return "", errors.ErrUserNotFound
+	}
+	if !user.IsActive() {
+		return "", errors.ErrAccountInactive
+	}
+
+	token, _, err := us.jwtService.IssueAccountDeletionToken(user.ID)
+	if err != nil {
+		logging.Error("Erro ao gerar token de confirmação de exclusão: %v", err)
+		return "", errors.ErrInternalServer.WithError(err)
+	}
+
+	logging.Info("Exclusão de conta solicitada pelo usuário %s", user.ID)
+	return token, nil
+}
+
+// ConfirmDeletion marca como excluída (exclusão lógica) a conta do usuário,
+// desde que o token de confirmação seja válido e emitido para ele, e encerra
+// suas sessões
+func (us *UserService) ConfirmDeletion(userID, token string) error {
+	claims, err := us.jwtService.ValidateAccountDeletionToken(token)
+	if err != nil {
+		logging.Warning("Token de confirmação de exclusão recusado: %v", err)
+		return errors.ErrInvalidDeletionToken
+	}
+	if claims.Subject != userID {
+		logging.Warning("Token de confirmação de exclusão da conta %s apresentado para a conta %s", claims.Subject, userID)
+		return errors.ErrInvalidDeletionToken
+	}
+
+	user, err := us.userRepo.GetByID(userID)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para exclusão: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	// Uma conta já excluída ou desativada não aceita o token novamente
+	if user == nil || !user.IsActive() {
+		return errors.ErrInvalidDeletionToken
+	}
+
+	err = us.UpdateFields(user.ID, map[string]any{
+		domain.UserFieldStatus:    domain.UserStatusDeleted,
+		domain.UserFieldUpdatedBy: domain.ActorSelf,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Falhas ao encerrar as sessões já são registradas; a conta segue excluída
+	if us.sessions != nil {
+		_, _ = us.endAllSessions(user.ID)
+	}
+
+	logging.Info("Conta do usuário %s excluída pelo próprio usuário", user.ID)
+	return nil
+}
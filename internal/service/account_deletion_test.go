@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/session"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeletionService(t *testing.T, opts ...UserServiceOption) (*UserService, *mockUserRepo) {
+	t.Helper()
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithAccountDeletionTTL(time.Minute))
+	us := NewUserService(repo, jwtService, opts...)
+	require.NoError(t, us.Create(&domain.User{ID: "del", Email: "del@b.com", Password: "senha123"}))
+	require.NoError(t, us.Create(&domain.User{ID: "other", Email: "other@b.com", Password: "senha123"}))
+	return us, repo
+}
+
+func TestUserService_DeletionRequiresConfirmation(t *testing.T) {
+	us, repo := newDeletionService(t)
+
+	token, err := us.RequestDeletion("del")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	// Solicitar não exclui a conta
+	assert.True(t, repo.users["del"].IsActive())
+
+	// Tokens inválidos ou de outra conta não excluem
+	assert.ErrorIs(t, us.ConfirmDeletion("del", "invalido"), pkgerrors.ErrInvalidDeletionToken)
+	assert.ErrorIs(t, us.ConfirmDeletion("other", token), pkgerrors.ErrInvalidDeletionToken)
+	assert.True(t, repo.users["del"].IsActive())
+	assert.True(t, repo.users["other"].IsActive())
+
+	// Com o token válido, a conta é excluída logicamente e deixa de autenticar
+	require.NoError(t, us.ConfirmDeletion("del", token))
+	assert.Equal(t, domain.UserStatusDeleted, repo.users["del"].Status)
+	assert.Equal(t, domain.ActorSelf, repo.users["del"].UpdatedBy)
+	_, _, err = us.Authenticate("del@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountInactive)
+
+	// O token não é aceito de novo
+	assert.ErrorIs(t, us.ConfirmDeletion("del", token), pkgerrors.ErrInvalidDeletionToken)
+}
+
+func TestUserService_ConfirmDeletion_EndsSessions(t *testing.T) {
+	store := session.NewMemoryStore()
+	us, _ := newDeletionService(t, WithSessionStore(store, 0))
+	_, _, err := us.Authenticate("del@b.com", "senha123")
+	require.NoError(t, err)
+
+	token, err := us.RequestDeletion("del")
+	require.NoError(t, err)
+	require.NoError(t, us.ConfirmDeletion("del", token))
+
+	sessions, err := store.ListByUser("del")
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestUserService_RequestDeletion_UnknownUser(t *testing.T) {
+	us, _ := newDeletionService(t)
+
+	_, err := us.RequestDeletion("nao-existe")
+
+	assert.ErrorIs(t, err, pkgerrors.ErrUserNotFound)
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// RecordAuditEvent registra na trilha de auditoria que actorID executou action
+// sobre targetID. Sem trilha configurada não faz nada.
+func (us *UserService) RecordAuditEvent(actorID, action, targetID string) error {
+	if us.audit == nil {
+		return nil
+	}
+	err := us.audit.Record(&domain.AuditEvent{ActorID: actorID, Action: action, TargetID: targetID, At: us.clock.Now()})
+	if err != nil {
+		return errors.ErrInternalServer.WithError(err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/audit"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserService_RecordAuditEvent_PersistsEvent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	trail := audit.NewMemoryStore(0)
+	us := NewUserService(newMockUserRepo(), nil,
+		WithClock(clock.Func(func() time.Time { return now })), WithAuditStore(trail))
+
+	assert.NoError(t, us.RecordAuditEvent("admin-1", domain.AuditActionUserDeleted, "u1"))
+
+	events, total, err := trail.List(0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, &domain.AuditEvent{ActorID: "admin-1", Action: domain.AuditActionUserDeleted, TargetID: "u1", At: now}, events[0])
+}
+
+func TestUserService_RecordAuditEvent_NoStoreIsNoop(t *testing.T) {
+	us := NewUserService(newMockUserRepo(), nil)
+	assert.NoError(t, us.RecordAuditEvent("admin-1", domain.AuditActionUserUpdated, "u1"))
+}
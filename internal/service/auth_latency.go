@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// authTimer mede a duração das etapas da autenticação com o relógio do serviço
+type authTimer struct {
+	clock clock.Clock
+	start time.Time
+	last  time.Time
+	steps []string
+}
+
+func newAuthTimer(c clock.Clock) *authTimer {
+	now := c.Now()
+	return &authTimer{clock: c, start: now, last: now}
+}
+
+// step registra o tempo decorrido desde a etapa anterior
+func (t *authTimer) step(name string) {
+	now := t.clock.Now()
+	t.steps = append(t.steps, fmt.Sprintf("%s=%s", name, now.Sub(t.last)))
+	t.last = now
+}
+
+// log emite as durações medidas em nível debug
+func (t *authTimer) log() {
+	logging.Debug("Latência de autenticação: %s total=%s", strings.Join(t.steps, " "), t.last.Sub(t.start))
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedClock devolve os horários enfileirados, simulando atrasos entre as etapas
+type scriptedClock struct {
+	base  time.Time
+	queue []time.Time
+}
+
+func (c *scriptedClock) after(delays ...time.Duration) {
+	at := c.base
+	c.queue = append(c.queue, at)
+	for _, d := range delays {
+		at = at.Add(d)
+		c.queue = append(c.queue, at)
+	}
+}
+
+func (c *scriptedClock) Now() time.Time {
+	if len(c.queue) == 0 {
+		return c.base
+	}
+	now := c.queue[0]
+	c.queue = c.queue[1:]
+	return now
+}
+
+func TestAuthenticate_LogsLatency(t *testing.T) {
+	t.Log("[INICIO] Teste de latência da autenticação")
+
+	// Arrange
+	clk := &scriptedClock{base: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithClock(clk))
+	_ = us.Create(&domain.User{ID: "lat", Email: "lat@b.com", Password: "senha123", Name: "Lat"})
+
+	var buf bytes.Buffer
+	logging.SetDebugOutput(&buf)
+	defer logging.SetDebugOutput(nil)
+	// A emissão do refresh token lê o relógio para a claim iat antes do fim da etapa
+	clk.after(40*time.Millisecond, 250*time.Millisecond, 0, 10*time.Millisecond)
+
+	// Act
+	_, _, err := us.Authenticate("lat@b.com", "senha123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "lookup=40ms compare=250ms token=10ms total=300ms")
+
+	t.Log("[FIM] Teste de latência da autenticação")
+}
+
+func TestAuthenticate_LogsLatencyOnFailure(t *testing.T) {
+	t.Log("[INICIO] Teste de latência da autenticação com senha inválida")
+
+	// Arrange
+	clk := &scriptedClock{base: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithClock(clk))
+	_ = us.Create(&domain.User{ID: "lat", Email: "lat@b.com", Password: "senha123", Name: "Lat"})
+
+	var buf bytes.Buffer
+	logging.SetDebugOutput(&buf)
+	defer logging.SetDebugOutput(nil)
+	clk.after(5*time.Millisecond, 200*time.Millisecond)
+
+	// Act
+	_, _, err := us.Authenticate("lat@b.com", "errada")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "lookup=5ms compare=200ms total=205ms")
+
+	t.Log("[FIM] Teste de latência da autenticação com senha inválida")
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// breachPrefixLen é o tamanho do prefixo do SHA-1 enviado ao verificador
+const breachPrefixLen = 5
+
+// WithBreachChecker recusa senhas presentes em vazamentos conhecidos no registro
+// e na troca de senha. Sem verificador configurado, nenhuma consulta é feita.
+func WithBreachChecker(checker domain.BreachChecker) UserServiceOption {
+	return func(us *UserService) {
+		us.breachChecker = checker
+	}
+}
+
+// checkPasswordBreach retorna ErrPasswordBreached quando o SHA-1 da senha consta
+// no verificador. Falhas na consulta não bloqueiam a operação, apenas são registradas.
+func (us *UserService) checkPasswordBreach(password string) error {
+	if us.breachChecker == nil {
+		return nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:breachPrefixLen], digest[breachPrefixLen:]
+
+	suffixes, err := us.breachChecker.BreachedSuffixes(prefix)
+	if err != nil {
+		logging.Warning("Verificação de senha vazada indisponível: %v", err)
+		return nil
+	}
+
+	for _, candidate := range suffixes {
+		if strings.EqualFold(candidate, suffix) {
+			return errors.ErrPasswordBreached
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubBreachChecker responde como a range API, a partir de senhas vazadas em texto puro
+type stubBreachChecker struct {
+	breached []string
+	err      error
+	prefixes []string
+}
+
+func (s *stubBreachChecker) BreachedSuffixes(prefix string) ([]string, error) {
+	s.prefixes = append(s.prefixes, prefix)
+	if s.err != nil {
+		return nil, s.err
+	}
+	var suffixes []string
+	for _, password := range s.breached {
+		sum := sha1.Sum([]byte(password))
+		digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+		if digest[:breachPrefixLen] == prefix {
+			suffixes = append(suffixes, digest[breachPrefixLen:])
+		}
+	}
+	return suffixes, nil
+}
+
+func TestUserService_BreachedPasswordRejected(t *testing.T) {
+	checker := &stubBreachChecker{breached: []string{"password1"}}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithBreachChecker(checker))
+
+	err := us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "password1"})
+	assert.ErrorIs(t, err, pkgerrors.ErrPasswordBreached)
+	// Apenas o prefixo de 5 caracteres do SHA-1 é enviado ao verificador
+	assert.Equal(t, []string{"E38AD"}, checker.prefixes)
+
+	assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "uma-senha-limpa1"}))
+
+	err = us.ChangePassword("1", "uma-senha-limpa1", "password1")
+	assert.ErrorIs(t, err, pkgerrors.ErrPasswordBreached)
+	assert.NoError(t, us.ChangePassword("1", "uma-senha-limpa1", "outra-senha-limpa1"))
+}
+
+func TestUserService_BreachCheckerFailureDoesNotBlock(t *testing.T) {
+	checker := &stubBreachChecker{err: errors.New("indisponível")}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithBreachChecker(checker))
+
+	assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "password1"}))
+}
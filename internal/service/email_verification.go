@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// sendEmailVerification emite o token de verificação do email do usuário e o
+// publica como EmailVerificationSentEvent, para entrega fora da API. Falhas são
+// apenas registradas, para que não desfaçam o cadastro já concluído.
+func (us *UserService) sendEmailVerification(user *domain.User) {
+	token, claims, err := us.jwtService.IssueEmailVerificationToken(user)
+	if err != nil {
+		logging.Error("Erro ao gerar token de verificação de email do usuário %s: %v", user.ID, err)
+		return
+	}
+
+	if us.events == nil {
+		logging.Warning("Token de verificação de email do usuário %s emitido sem publicador de eventos para entregá-lo", user.ID)
+		return
+	}
+	err = us.events.Publish(domain.EmailVerificationSentEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: claims.ExpiresAt.Time,
+		At:        us.clock.Now(),
+	})
+	if err != nil {
+		logging.Error("Erro ao publicar verificação de email do usuário %s: %v", user.ID, err)
+		return
+	}
+
+	logging.Info("Verificação de email enviada para o usuário %s", user.ID)
+}
+
+// VerifyEmail marca como verificado o email da conta dona do token. O token é
+// de uso único: o jti é consumido na primeira verificação aceita e também
+// revogado na blacklist. Um token emitido para um email que não é mais o da
+// conta é recusado.
+func (us *UserService) VerifyEmail(token string) error {
+	claims, err := us.jwtService.ValidateEmailVerificationToken(token)
+	if err != nil {
+		logging.Warning("Token de verificação de email recusado: %v", err)
+		return errors.ErrInvalidVerificationToken
+	}
+
+	user, err := us.userRepo.GetByID(claims.Subject)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para verificação de email: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if user == nil || !user.IsActive() || user.Email != claims.Email {
+		logging.Warning("Token de verificação de email não corresponde à conta %s", claims.Subject)
+		return errors.ErrInvalidVerificationToken
+	}
+
+	revoked, err := us.blacklist.Contains(claims.ID)
+	if err != nil {
+		logging.Error("Erro ao consultar blacklist de tokens: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if revoked {
+		return errors.ErrInvalidVerificationToken
+	}
+	if !us.usedVerificationTokens.AddIfAbsent(claims.ID, claims.ExpiresAt.Time) {
+		logging.Warning("Token de verificação de email reutilizado para o usuário %s", user.ID)
+		return errors.ErrInvalidVerificationToken
+	}
+	if err := us.revokeToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		logging.Error("Erro ao revogar token de verificação de email: %v", err)
+	}
+
+	err = us.UpdateFields(user.ID, map[string]any{
+		domain.UserFieldEmailVerified: true,
+		domain.UserFieldUpdatedBy:     domain.ActorSelf,
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Info("Email verificado para o usuário %s", user.ID)
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerificationService cria um serviço com um usuário auto-registrado e
+// retorna o token de verificação entregue pelo evento
+func newVerificationService(t *testing.T, opts ...UserServiceOption) (*UserService, *mockUserRepo, string) {
+	t.Helper()
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService, append([]UserServiceOption{WithEventPublisher(spy)}, opts...)...)
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", CreatedBy: domain.ActorSelf}))
+
+	require.Len(t, spy.events, 1)
+	event, ok := spy.events[0].(domain.EmailVerificationSentEvent)
+	require.True(t, ok)
+	assert.Equal(t, "1", event.UserID)
+	assert.Equal(t, "a@b.com", event.Email)
+	return us, repo, event.Token
+}
+
+func TestUserService_VerifyEmail_Valid(t *testing.T) {
+	us, repo, token := newVerificationService(t)
+	assert.False(t, repo.users["1"].EmailVerified)
+
+	assert.NoError(t, us.VerifyEmail(token))
+
+	assert.True(t, repo.users["1"].EmailVerified)
+	assert.Equal(t, domain.ActorSelf, repo.users["1"].UpdatedBy)
+}
+
+func TestUserService_VerifyEmail_ReusedTokenRejected(t *testing.T) {
+	us, _, token := newVerificationService(t)
+	require.NoError(t, us.VerifyEmail(token))
+
+	err := us.VerifyEmail(token)
+
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidVerificationToken)
+}
+
+func TestUserService_VerifyEmail_ExpiredTokenRejected(t *testing.T) {
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	// exp é truncado ao segundo, então um TTL de 1ns produz um token já vencido
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithEmailVerificationTTL(time.Nanosecond))
+	us := NewUserService(repo, jwtService, WithEventPublisher(spy))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", CreatedBy: domain.ActorSelf}))
+	require.Len(t, spy.events, 1)
+
+	err := us.VerifyEmail(spy.events[0].(domain.EmailVerificationSentEvent).Token)
+
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidVerificationToken)
+	assert.False(t, repo.users["1"].EmailVerified)
+}
+
+func TestUserService_VerifyEmail_EmailChangedRejected(t *testing.T) {
+	us, repo, token := newVerificationService(t)
+	require.NoError(t, us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "c@d.com"}))
+
+	err := us.VerifyEmail(token)
+
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidVerificationToken)
+	assert.False(t, repo.users["1"].EmailVerified)
+}
+
+func TestUserService_Create_AdminCreatedSkipsVerification(t *testing.T) {
+	spy := &spyPublisher{}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithEventPublisher(spy))
+
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", CreatedBy: "admin-1"}))
+
+	assert.Empty(t, spy.events)
+}
+
+func TestUserService_Authenticate_RequiresVerifiedEmail(t *testing.T) {
+	us, _, token := newVerificationService(t, WithEmailVerificationRequired(true))
+
+	_, _, err := us.Authenticate("a@b.com", "senha-antiga1")
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailNotVerified)
+
+	require.NoError(t, us.VerifyEmail(token))
+	access, refresh, err := us.Authenticate("a@b.com", "senha-antiga1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, access)
+	assert.NotEmpty(t, refresh)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spyPublisher guarda os eventos publicados para inspeção nos testes
+type spyPublisher struct {
+	events []domain.Event
+}
+
+func (s *spyPublisher) Publish(event domain.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestUserService_Update_PublishesEmailChanged(t *testing.T) {
+	t.Log("[INICIO] Teste de evento de troca de email no Update")
+
+	// Arrange
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithEventPublisher(spy))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "antigo@b.com", Password: "senha123", Name: "A"}))
+	changed := *repo.users["1"]
+	changed.Email = "novo@b.com"
+	changed.UpdatedBy = domain.ActorSelf
+
+	// Act
+	err := us.Update(&changed)
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, spy.events, 1)
+	event, ok := spy.events[0].(domain.EmailChangedEvent)
+	require.True(t, ok)
+	assert.Equal(t, domain.EventEmailChanged, event.EventName())
+	assert.Equal(t, "1", event.UserID)
+	assert.Equal(t, "antigo@b.com", event.OldEmail)
+	assert.Equal(t, "novo@b.com", event.NewEmail)
+	assert.Equal(t, domain.ActorSelf, event.ChangedBy)
+
+	t.Log("[FIM] Teste de evento de troca de email no Update")
+}
+
+func TestUserService_UpdateFields_PublishesEmailChanged(t *testing.T) {
+	t.Log("[INICIO] Teste de evento de troca de email no UpdateFields")
+
+	// Arrange
+	spy := &spyPublisher{}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithEventPublisher(spy))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "antigo@b.com", Password: "senha123", Name: "A"}))
+
+	// Act
+	err := us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "novo@b.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, spy.events, 1)
+	event := spy.events[0].(domain.EmailChangedEvent)
+	assert.Equal(t, "antigo@b.com", event.OldEmail)
+	assert.Equal(t, "novo@b.com", event.NewEmail)
+
+	t.Log("[FIM] Teste de evento de troca de email no UpdateFields")
+}
+
+func TestUserService_Update_NoEventWhenEmailUnchanged(t *testing.T) {
+	t.Log("[INICIO] Teste sem evento quando o email não muda")
+
+	// Arrange
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithEventPublisher(spy))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123", Name: "A"}))
+	changed := *repo.users["1"]
+	changed.Name = "Outro nome"
+
+	// Act
+	assert.NoError(t, us.Update(&changed))
+	assert.NoError(t, us.UpdateFields("1", map[string]any{domain.UserFieldName: "Mais um"}))
+
+	// Assert
+	assert.Empty(t, spy.events)
+
+	t.Log("[FIM] Teste sem evento quando o email não muda")
+}
+
+func TestUserService_EmailChangeNotificationDisabled(t *testing.T) {
+	t.Log("[INICIO] Teste de aviso de troca de email desligado")
+
+	// Arrange
+	spy := &spyPublisher{}
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1),
+		WithEventPublisher(spy), WithEmailChangeNotification(false))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "antigo@b.com", Password: "senha123", Name: "A", CreatedBy: domain.ActorSelf}))
+
+	// Act
+	err := us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "novo@b.com"})
+
+	// Assert: apenas o token de verificação do registro foi publicado
+	assert.NoError(t, err)
+	require.Len(t, spy.events, 1)
+	assert.IsType(t, domain.EmailVerificationSentEvent{}, spy.events[0])
+
+	t.Log("[FIM] Teste de aviso de troca de email desligado")
+}
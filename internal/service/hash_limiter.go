@@ -0,0 +1,125 @@
+package service
+
+import (
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/hashing"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// hashLimiter é um semáforo que limita as operações bcrypt simultâneas. Quem não
+// obtém uma vaga dentro de wait é recusado, evitando que uma rajada de logins
+// sature a CPU.
+type hashLimiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+// WithBcryptCost define o custo dos hashes bcrypt gerados a partir de agora.
+// Hashes existentes continuam válidos, pois o custo fica gravado em cada um.
+// cost <= 0 usa bcrypt.DefaultCost.
+func WithBcryptCost(cost int) UserServiceOption {
+	return func(us *UserService) {
+		us.bcryptCost = cost
+	}
+}
+
+// WithArgon2Hasher passa a gerar os novos hashes de senha com Argon2id. Hashes
+// bcrypt existentes continuam válidos: a verificação segue o formato de cada hash.
+func WithArgon2Hasher(hasher *hashing.Argon2Hasher) UserServiceOption {
+	return func(us *UserService) {
+		us.argon2 = hasher
+	}
+}
+
+// WithHashConcurrency limita a max as operações bcrypt (hash e comparação)
+// simultâneas. Excedentes aguardam até wait por uma vaga e, depois disso,
+// recebem ErrTooManyRequests; wait <= 0 recusa de imediato. max <= 0 desabilita o limite.
+func WithHashConcurrency(max int, wait time.Duration) UserServiceOption {
+	return func(us *UserService) {
+		if max <= 0 {
+			us.hashLimiter = nil
+			return
+		}
+		us.hashLimiter = &hashLimiter{slots: make(chan struct{}, max), wait: wait}
+	}
+}
+
+// acquire reserva uma vaga; sem limitador configurado sempre tem sucesso
+func (l *hashLimiter) acquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if l.wait <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// release devolve a vaga obtida em acquire
+func (l *hashLimiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
+
+// hashPassword gera o hash da senha (bcrypt ou, com WithArgon2Hasher, Argon2id)
+// respeitando o limite de concorrência
+func (us *UserService) hashPassword(password string) (string, error) {
+	if !us.hashLimiter.acquire() {
+		logging.Warning("Limite de operações bcrypt simultâneas atingido ao gerar hash")
+		return "", errors.ErrTooManyRequests
+	}
+	defer us.hashLimiter.release()
+
+	if us.argon2 != nil {
+		hashed, err := us.argon2.Hash(password)
+		if err != nil {
+			logging.Error("Erro ao gerar hash Argon2id da senha: %v", err)
+			return "", errors.ErrInternalServer.WithError(err)
+		}
+		return hashed, nil
+	}
+
+	cost := us.bcryptCost
+	if cost <= 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		logging.Error("Erro ao gerar hash da senha: %v", err)
+		return "", errors.ErrInternalServer.WithError(err)
+	}
+	return string(hashed), nil
+}
+
+// comparePassword compara a senha com o hash respeitando o limite de
+// concorrência; retorna ErrTooManyRequests se não houver vaga, ou o erro do
+// algoritmo do hash (Argon2id pelo prefixo, bcrypt caso contrário)
+func (us *UserService) comparePassword(hash, password string) error {
+	if !us.hashLimiter.acquire() {
+		logging.Warning("Limite de operações bcrypt simultâneas atingido ao comparar senha")
+		return errors.ErrTooManyRequests
+	}
+	defer us.hashLimiter.release()
+
+	if hashing.IsArgon2Hash(hash) {
+		return hashing.VerifyArgon2(hash, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
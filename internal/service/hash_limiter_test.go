@@ -0,0 +1,133 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/hashing"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashLimiter_SerializesBeyondCap(t *testing.T) {
+	limiter := &hashLimiter{slots: make(chan struct{}, 2), wait: 5 * time.Second}
+
+	// Espião: conta as operações em andamento e registra o pico
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !limiter.acquire() {
+				t.Error("nenhuma operação deveria ser recusada dentro da espera")
+				return
+			}
+			defer limiter.release()
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestHashLimiter_ShedsWhenFull(t *testing.T) {
+	limiter := &hashLimiter{slots: make(chan struct{}, 1), wait: 10 * time.Millisecond}
+	assert.True(t, limiter.acquire())
+	assert.False(t, limiter.acquire())
+
+	limiter.release()
+	assert.True(t, limiter.acquire())
+
+	var disabled *hashLimiter
+	assert.True(t, disabled.acquire())
+	disabled.release()
+}
+
+func TestUserService_HashConcurrencyShedsWith429(t *testing.T) {
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1), WithHashConcurrency(1, 0))
+	assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"}))
+
+	// Ocupa a única vaga, como um hash em andamento
+	assert.True(t, us.hashLimiter.acquire())
+	err := us.Create(&domain.User{ID: "2", Email: "c@d.com", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrTooManyRequests)
+	_, _, err = us.Authenticate("a@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrTooManyRequests)
+	err = us.ChangePassword("1", "senha123", "nova")
+	assert.ErrorIs(t, err, pkgerrors.ErrTooManyRequests)
+
+	us.hashLimiter.release()
+	_, _, err = us.Authenticate("a@b.com", "senha123")
+	assert.NoError(t, err)
+}
+
+func TestUserService_BcryptCostApplied(t *testing.T) {
+	for _, cost := range []int{bcrypt.MinCost, 6} {
+		repo := newMockUserRepo()
+		us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithBcryptCost(cost))
+		assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"}))
+
+		got, err := bcrypt.Cost([]byte(repo.users["1"].Password))
+		assert.NoError(t, err)
+		assert.Equal(t, cost, got)
+
+		// A troca de senha também usa o custo configurado
+		assert.NoError(t, us.ChangePassword("1", "senha123", "senha456"))
+		got, err = bcrypt.Cost([]byte(repo.users["1"].Password))
+		assert.NoError(t, err)
+		assert.Equal(t, cost, got)
+	}
+
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"}))
+	got, err := bcrypt.Cost([]byte(repo.users["1"].Password))
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, got)
+}
+
+func TestUserService_Argon2Hasher(t *testing.T) {
+	params := hashing.DefaultArgon2Params()
+	params.Memory = hashing.MinArgon2Memory
+	params.Iterations = 1
+	hasher, err := hashing.NewArgon2Hasher(params)
+	assert.NoError(t, err)
+
+	// Um usuário criado antes da troca de algoritmo mantém o hash bcrypt
+	repo := newMockUserRepo()
+	legacy := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NoError(t, legacy.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"}))
+
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithArgon2Hasher(hasher))
+	assert.NoError(t, us.Create(&domain.User{ID: "2", Email: "c@d.com", Password: "senha123"}))
+	assert.True(t, hashing.IsArgon2Hash(repo.users["2"].Password))
+	assert.Contains(t, repo.users["2"].Password, "m=8192,t=1,p=2")
+
+	// Os dois formatos autenticam e a troca de senha gera um hash Argon2id
+	_, _, err = us.Authenticate("a@b.com", "senha123")
+	assert.NoError(t, err)
+	_, _, err = us.Authenticate("c@d.com", "senha123")
+	assert.NoError(t, err)
+	_, _, err = us.Authenticate("c@d.com", "errada123")
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+
+	assert.NoError(t, us.ChangePassword("1", "senha123", "senha456"))
+	assert.True(t, hashing.IsArgon2Hash(repo.users["1"].Password))
+	_, _, err = us.Authenticate("a@b.com", "senha456")
+	assert.NoError(t, err)
+}
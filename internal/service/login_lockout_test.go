@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/lockout"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLockoutTestService(t *testing.T, now *time.Time) *UserService {
+	clk := clock.Func(func() time.Time { return *now })
+	store := lockout.NewMemoryStore(15*time.Minute, clk)
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1),
+		WithClock(clk), WithLoginLockout(store, 3, 15*time.Minute))
+	assert.NoError(t, us.Create(&domain.User{ID: "lk", Email: "lock@b.com", Password: "senha123"}))
+	return us
+}
+
+func TestUserService_Authenticate_LocksAfterRepeatedFailures(t *testing.T) {
+	now := time.Now()
+	us := newLockoutTestService(t, &now)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := us.Authenticate("lock@b.com", "errada1")
+		assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+	}
+
+	// Bloqueada, a conta recusa até a senha correta, inclusive com outra caixa
+	_, _, err := us.Authenticate("lock@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountLocked)
+	_, _, err = us.Authenticate(" LOCK@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountLocked)
+
+	// Encerrado o bloqueio, a senha correta volta a autenticar
+	now = now.Add(16 * time.Minute)
+	_, _, err = us.Authenticate("lock@b.com", "senha123")
+	assert.NoError(t, err)
+}
+
+func TestUserService_Authenticate_SuccessResetsFailures(t *testing.T) {
+	now := time.Now()
+	us := newLockoutTestService(t, &now)
+
+	for i := 0; i < 2; i++ {
+		_, _, _ = us.Authenticate("lock@b.com", "errada1")
+	}
+	_, _, err := us.Authenticate("lock@b.com", "senha123")
+	assert.NoError(t, err)
+
+	// O contador recomeça: duas novas falhas não bastam para bloquear
+	for i := 0; i < 2; i++ {
+		_, _, _ = us.Authenticate("lock@b.com", "errada1")
+	}
+	_, _, err = us.Authenticate("lock@b.com", "senha123")
+	assert.NoError(t, err)
+}
+
+func TestUserService_Authenticate_LocksUnknownIdentifiers(t *testing.T) {
+	now := time.Now()
+	us := newLockoutTestService(t, &now)
+
+	for i := 0; i < 3; i++ {
+		_, _, _ = us.Authenticate("nao@existe.com", "errada1")
+	}
+	_, _, err := us.Authenticate("nao@existe.com", "errada1")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountLocked)
+
+	// Outros identificadores não são afetados
+	_, _, err = us.Authenticate("lock@b.com", "senha123")
+	assert.NoError(t, err)
+}
+
+func TestUserService_Authenticate_LockoutScopedByOrg(t *testing.T) {
+	now := time.Now()
+	clk := clock.Func(func() time.Time { return now })
+	store := lockout.NewMemoryStore(15*time.Minute, clk)
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1),
+		WithClock(clk), WithLoginLockout(store, 3, 15*time.Minute), WithTenantScopedEmail(true))
+	assert.NoError(t, us.Create(&domain.User{ID: "a", OrgID: "org-a", Email: "lock@b.com", Password: "senha123"}))
+	assert.NoError(t, us.Create(&domain.User{ID: "b", OrgID: "org-b", Email: "lock@b.com", Password: "senha123"}))
+
+	for i := 0; i < 3; i++ {
+		_, _, err := us.AuthenticateInOrg("org-a", "lock@b.com", "errada1")
+		assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+	}
+
+	// O bloqueio vale apenas para a conta da organização atacada
+	_, _, err := us.AuthenticateInOrg("org-a", "lock@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountLocked)
+	_, _, err = us.AuthenticateInOrg("org-b", "lock@b.com", "senha123")
+	assert.NoError(t, err)
+}
+
+func TestUserService_Authenticate_LockoutIgnoresOrgWithoutTenantScope(t *testing.T) {
+	now := time.Now()
+	us := newLockoutTestService(t, &now)
+
+	// Fora do modo multi-tenant, variar a organização não contorna o bloqueio
+	for _, orgID := range []string{"x", "y", "z"} {
+		_, _, err := us.AuthenticateInOrg(orgID, "lock@b.com", "errada1")
+		assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+	}
+	_, _, err := us.AuthenticateInOrg("w", "lock@b.com", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrAccountLocked)
+}
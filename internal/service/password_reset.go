@@ -0,0 +1,122 @@
+package service
+
+import (
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+	"github.com/lucas-de-lima/go-auth-system/pkg/validator"
+)
+
+// RequestPasswordReset emite um token de redefinição de senha para a conta do
+// email (na organização informada, no modo multi-tenant) e o publica como
+// PasswordResetRequestedEvent, para entrega fora da API. Emails desconhecidos ou
+// de contas inativas não produzem erro, para que a resposta não revele quais
+// emails estão cadastrados. O destino do link (RedirectURI) precisa estar na
+// allowlist (ValidateResetRedirect) e é verificado antes da busca pela conta.
+func (us *UserService) RequestPasswordReset(req domain.PasswordResetRequest) error {
+	if err := us.ValidateResetRedirect(req.RedirectURI); err != nil {
+		return err
+	}
+
+	user, err := us.findEmailOwner(req.OrgID, req.Email)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para redefinição de senha: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if user == nil || !user.IsActive() {
+		logging.Info("Redefinição de senha solicitada para email sem conta ativa")
+		return nil
+	}
+
+	token, claims, err := us.jwtService.IssuePasswordResetToken(user)
+	if err != nil {
+		logging.Error("Erro ao gerar token de redefinição de senha: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	if us.events == nil {
+		logging.Warning("Token de redefinição de senha do usuário %s emitido sem publicador de eventos para entregá-lo", user.ID)
+		return nil
+	}
+	err = us.events.Publish(domain.PasswordResetRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: claims.ExpiresAt.Time,
+		At:        us.clock.Now(),
+
+		RedirectURI: req.RedirectURI,
+	})
+	if err != nil {
+		logging.Error("Erro ao publicar redefinição de senha do usuário %s: %v", user.ID, err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	logging.Info("Redefinição de senha solicitada para o usuário %s", user.ID)
+	return nil
+}
+
+// ConfirmPasswordReset troca a senha do dono do token de redefinição. O token é
+// de uso único: o jti é consumido na primeira confirmação aceita e também
+// revogado na blacklist. Um token emitido para um email que não é mais o da
+// conta é recusado. Após a troca, todos os refresh tokens da conta são revogados.
+func (us *UserService) ConfirmPasswordReset(token, newPassword string) error {
+	claims, err := us.jwtService.ValidatePasswordResetToken(token)
+	if err != nil {
+		logging.Warning("Token de redefinição de senha recusado: %v", err)
+		return errors.ErrInvalidResetToken
+	}
+
+	user, err := us.userRepo.GetByID(claims.Subject)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para redefinição de senha: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if user == nil || !user.IsActive() || user.Email != claims.Email {
+		logging.Warning("Token de redefinição de senha não corresponde à conta %s", claims.Subject)
+		return errors.ErrInvalidResetToken
+	}
+
+	// Validada antes de consumir o token, para que uma senha recusada não o desperdice
+	if err := validator.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+	if err := us.checkPasswordBreach(newPassword); err != nil {
+		logging.Warning("Senha vazada recusada na redefinição de senha do usuário %s", user.ID)
+		return err
+	}
+
+	revoked, err := us.blacklist.Contains(claims.ID)
+	if err != nil {
+		logging.Error("Erro ao consultar blacklist de tokens: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if revoked {
+		return errors.ErrInvalidResetToken
+	}
+	if !us.usedResetTokens.AddIfAbsent(claims.ID, claims.ExpiresAt.Time) {
+		logging.Warning("Token de redefinição de senha reutilizado para o usuário %s", user.ID)
+		return errors.ErrInvalidResetToken
+	}
+	if err := us.revokeToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		logging.Error("Erro ao revogar token de redefinição de senha: %v", err)
+	}
+
+	err = us.UpdateFields(user.ID, map[string]any{
+		domain.UserFieldPassword:           newPassword,
+		domain.UserFieldMustChangePassword: false,
+		domain.UserFieldUpdatedBy:          domain.ActorSelf,
+	})
+	if err != nil {
+		return err
+	}
+
+	// A redefinição recupera a conta: sessões abertas antes dela, possivelmente
+	// por quem tomou a conta, deixam de valer
+	if err := us.RevokeAllTokens(user.ID); err != nil {
+		return err
+	}
+
+	logging.Info("Senha redefinida para o usuário %s", user.ID)
+	return nil
+}
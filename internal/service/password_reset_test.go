@@ -0,0 +1,196 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newResetService cria um serviço com um usuário cadastrado e um publicador espião
+func newResetService(t *testing.T) (*UserService, *mockUserRepo, *spyPublisher) {
+	t.Helper()
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithPasswordReset("reset-key", time.Minute))
+	us := NewUserService(repo, jwtService, WithEventPublisher(spy))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", Name: "A"}))
+	return us, repo, spy
+}
+
+// requestResetToken solicita a redefinição e retorna o token entregue pelo evento
+func requestResetToken(t *testing.T, us *UserService, spy *spyPublisher, email string) string {
+	t.Helper()
+	require.NoError(t, us.RequestPasswordReset(domain.PasswordResetRequest{Email: email}))
+	require.NotEmpty(t, spy.events)
+	event, ok := spy.events[len(spy.events)-1].(domain.PasswordResetRequestedEvent)
+	require.True(t, ok)
+	return event.Token
+}
+
+func assertInvalidResetToken(t *testing.T, err error) {
+	t.Helper()
+	var appErr pkgerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, pkgerrors.ErrInvalidResetToken.Message, appErr.Message)
+}
+
+func TestUserService_PasswordReset_Valid(t *testing.T) {
+	us, repo, spy := newResetService(t)
+
+	token := requestResetToken(t, us, spy, "a@b.com")
+	event := spy.events[0].(domain.PasswordResetRequestedEvent)
+	assert.Equal(t, "1", event.UserID)
+	assert.Equal(t, "a@b.com", event.Email)
+
+	err := us.ConfirmPasswordReset(token, "senha-nova1")
+
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(repo.users["1"].Password), []byte("senha-nova1")))
+	assert.Equal(t, domain.ActorSelf, repo.users["1"].UpdatedBy)
+}
+
+func TestUserService_PasswordReset_RevokesExistingTokens(t *testing.T) {
+	us, _, spy := newResetService(t)
+	_, before, err := us.Authenticate("a@b.com", "senha-antiga1")
+	require.NoError(t, err)
+
+	token := requestResetToken(t, us, spy, "a@b.com")
+	require.NoError(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+
+	// O refresh token anterior à redefinição é recusado
+	_, _, err = us.RefreshTokens(before)
+	var appErr pkgerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, pkgerrors.ErrRefreshTokenReused.ErrorCode, appErr.ErrorCode)
+
+	// Um login com a nova senha segue renovando normalmente
+	_, after, err := us.Authenticate("a@b.com", "senha-nova1")
+	require.NoError(t, err)
+	_, _, err = us.RefreshTokens(after)
+	assert.NoError(t, err)
+}
+
+func TestUserService_PasswordReset_UnknownEmailIsSilent(t *testing.T) {
+	us, _, spy := newResetService(t)
+
+	assert.NoError(t, us.RequestPasswordReset(domain.PasswordResetRequest{Email: "ninguem@b.com"}))
+	assert.Empty(t, spy.events)
+}
+
+func TestUserService_PasswordReset_RedirectAllowlist(t *testing.T) {
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithPasswordReset("reset-key", time.Minute))
+	us := NewUserService(repo, jwtService, WithEventPublisher(spy), WithResetRedirectAllowlist([]string{"https://app.example.com/reset"}))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", Name: "A"}))
+
+	// O destino permitido segue no evento para compor o link
+	err := us.RequestPasswordReset(domain.PasswordResetRequest{Email: "a@b.com", RedirectURI: "https://app.example.com/reset/confirm"})
+	require.NoError(t, err)
+	require.Len(t, spy.events, 1)
+	assert.Equal(t, "https://app.example.com/reset/confirm", spy.events[0].(domain.PasswordResetRequestedEvent).RedirectURI)
+
+	// Destinos fora da allowlist são recusados antes da emissão, exista ou não a conta
+	for _, email := range []string{"a@b.com", "ninguem@b.com"} {
+		err = us.RequestPasswordReset(domain.PasswordResetRequest{Email: email, RedirectURI: "https://evil.example.com/reset"})
+		assert.ErrorIs(t, err, pkgerrors.ErrInvalidRedirect)
+	}
+	assert.Len(t, spy.events, 1)
+}
+
+func TestUserService_PasswordReset_Expired(t *testing.T) {
+	us, repo, _ := newResetService(t)
+	before := repo.users["1"].Password
+	claims := auth.PasswordResetClaims{
+		Email: "a@b.com",
+		Type:  auth.TokenTypePasswordReset,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-expirado",
+			Subject:   "1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("reset-key"))
+	require.NoError(t, err)
+
+	err = us.ConfirmPasswordReset(token, "senha-nova1")
+
+	assertInvalidResetToken(t, err)
+	assert.Equal(t, before, repo.users["1"].Password)
+}
+
+func TestUserService_PasswordReset_Reused(t *testing.T) {
+	us, _, spy := newResetService(t)
+	token := requestResetToken(t, us, spy, "a@b.com")
+	require.NoError(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+
+	err := us.ConfirmPasswordReset(token, "outra-senha1")
+
+	assertInvalidResetToken(t, err)
+}
+
+func TestUserService_PasswordReset_ReusedAcrossInstances(t *testing.T) {
+	// A blacklist durável impede o reuso mesmo em outra instância do serviço
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	blacklist := memoryBlacklist{}
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithPasswordReset("reset-key", time.Minute))
+	first := NewUserService(repo, jwtService, WithEventPublisher(spy), WithTokenBlacklist(blacklist))
+	require.NoError(t, first.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", Name: "A"}))
+	token := requestResetToken(t, first, spy, "a@b.com")
+	require.NoError(t, first.ConfirmPasswordReset(token, "senha-nova1"))
+
+	second := NewUserService(repo, jwtService, WithTokenBlacklist(blacklist))
+	err := second.ConfirmPasswordReset(token, "outra-senha1")
+
+	assertInvalidResetToken(t, err)
+}
+
+func TestUserService_PasswordReset_WrongUser(t *testing.T) {
+	t.Run("email da conta alterado", func(t *testing.T) {
+		us, repo, spy := newResetService(t)
+		token := requestResetToken(t, us, spy, "a@b.com")
+		repo.users["1"].Email = "novo@b.com"
+
+		assertInvalidResetToken(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+	})
+
+	t.Run("conta removida", func(t *testing.T) {
+		us, repo, spy := newResetService(t)
+		token := requestResetToken(t, us, spy, "a@b.com")
+		delete(repo.users, "1")
+
+		assertInvalidResetToken(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+	})
+
+	t.Run("token de outra chave", func(t *testing.T) {
+		us, _, _ := newResetService(t)
+		other := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithPasswordReset("outra-chave", time.Minute))
+		token, _, err := other.IssuePasswordResetToken(&domain.User{ID: "1", Email: "a@b.com"})
+		require.NoError(t, err)
+
+		assertInvalidResetToken(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+	})
+}
+
+func TestUserService_PasswordReset_BreachedPasswordKeepsToken(t *testing.T) {
+	spy := &spyPublisher{}
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1, auth.WithPasswordReset("reset-key", time.Minute))
+	us := NewUserService(repo, jwtService, WithEventPublisher(spy), WithBreachChecker(&stubBreachChecker{breached: []string{"password1"}}))
+	require.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha-antiga1", Name: "A"}))
+	token := requestResetToken(t, us, spy, "a@b.com")
+
+	err := us.ConfirmPasswordReset(token, "password1")
+	assert.ErrorIs(t, err, pkgerrors.ErrPasswordBreached)
+
+	// A senha recusada não consome o token
+	assert.NoError(t, us.ConfirmPasswordReset(token, "senha-nova1"))
+}
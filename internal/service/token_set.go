@@ -0,0 +1,83 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+)
+
+// tokenSet é um conjunto de chaves de token (jti) seguro para uso concorrente.
+// Cada chave guarda a expiração do próprio token: depois dela o token já seria
+// recusado de qualquer forma, então a chave deixa de contar e pode ser removida.
+type tokenSet struct {
+	mu    sync.RWMutex
+	clock clock.Clock
+	items map[string]time.Time
+}
+
+func newTokenSet(c clock.Clock) *tokenSet {
+	if c == nil {
+		c = clock.System()
+	}
+	return &tokenSet{clock: c, items: make(map[string]time.Time)}
+}
+
+// Add inclui a chave no conjunto até expiresAt
+func (s *tokenSet) Add(key string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[key] = expiresAt
+}
+
+// Contains indica se a chave está no conjunto e ainda não expirou
+func (s *tokenSet) Contains(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	expiresAt, ok := s.items[key]
+	return ok && s.clock.Now().Before(expiresAt)
+}
+
+// AddIfAbsent inclui a chave e indica se ela ainda não estava no conjunto,
+// permitindo consumir uma chave uma única vez mesmo sob concorrência
+func (s *tokenSet) AddIfAbsent(key string, expiresAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.items[key]; ok && s.clock.Now().Before(current) {
+		return false
+	}
+	s.items[key] = expiresAt
+	return true
+}
+
+// Take remove a chave e indica se ela estava no conjunto e ainda não havia
+// expirado, permitindo consumir uma chave concedida uma única vez
+func (s *tokenSet) Take(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.items[key]
+	delete(s.items, key)
+	return ok && s.clock.Now().Before(expiresAt)
+}
+
+// PurgeExpired remove as chaves expiradas e retorna quantas foram removidas
+func (s *tokenSet) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	purged := 0
+	for key, expiresAt := range s.items {
+		if !now.Before(expiresAt) {
+			delete(s.items, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Len retorna quantas chaves estão armazenadas, inclusive as expiradas ainda não removidas
+func (s *tokenSet) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSet_ExpiredEntriesAreIgnoredAndPurged(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	set := newTokenSet(clock.Func(func() time.Time { return now }))
+	set.Add("curto", now.Add(time.Minute))
+	set.Add("longo", now.Add(time.Hour))
+	assert.True(t, set.Contains("curto"))
+
+	// Após a expiração a entrada deixa de contar, mesmo antes da limpeza
+	now = now.Add(time.Minute)
+	assert.False(t, set.Contains("curto"))
+	assert.True(t, set.Contains("longo"))
+	assert.Equal(t, 2, set.Len())
+
+	purged, err := set.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, set.Len())
+}
+
+func TestTokenSet_AddIfAbsentReusesExpiredKey(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	set := newTokenSet(clock.Func(func() time.Time { return now }))
+	assert.True(t, set.AddIfAbsent("k", now.Add(time.Minute)))
+	assert.False(t, set.AddIfAbsent("k", now.Add(time.Minute)))
+
+	now = now.Add(time.Minute)
+	assert.True(t, set.AddIfAbsent("k", now.Add(time.Minute)))
+}
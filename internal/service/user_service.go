@@ -1,36 +1,241 @@
 package service
 
 import (
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/blacklist"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
 	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/lucas-de-lima/go-auth-system/pkg/hashing"
 	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/lucas-de-lima/go-auth-system/pkg/validator"
 )
 
 // UserService implementa a interface domain.UserService
 type UserService struct {
 	userRepo   domain.UserRepository
 	jwtService *auth.JWTService
+
+	resetRedirectAllowlist []*url.URL
+
+	sessions           domain.SessionStore
+	maxSessionsPerUser int
+
+	// revokeSessionsOnPasswordChange encerra as sessões do usuário após a troca de senha
+	revokeSessionsOnPasswordChange bool
+
+	activity domain.ActivityStore
+
+	audit domain.AuditStore
+
+	clock clock.Clock
+
+	events domain.EventPublisher
+	// notifyEmailChange publica EmailChangedEvent; os demais eventos não dependem dele
+	notifyEmailChange bool
+
+	blacklist domain.TokenBlacklist
+
+	usernameLogin bool
+
+	// deferRefreshToken omite o refresh token do login; o primeiro é obtido em
+	// IssueInitialRefreshToken, com o access token
+	deferRefreshToken bool
+	// initialRefreshGrants guarda os jti dos access tokens de login que ainda
+	// podem obter o refresh token inicial
+	initialRefreshGrants *tokenSet
+
+	// tenantScopedEmail restringe a unicidade do email à organização do usuário
+	tenantScopedEmail bool
+
+	breachChecker domain.BreachChecker
+
+	hashLimiter *hashLimiter
+	bcryptCost  int
+	// argon2 gera os novos hashes com Argon2id no lugar do bcrypt
+	argon2 *hashing.Argon2Hasher
+
+	// usedResetTokens guarda os jti dos tokens de redefinição já consumidos
+	usedResetTokens *tokenSet
+
+	// usedVerificationTokens guarda os jti dos tokens de verificação de email já consumidos
+	usedVerificationTokens *tokenSet
+	// requireEmailVerification recusa o login de contas com email não verificado
+	requireEmailVerification bool
+
+	loginAttempts    domain.LoginAttemptStore
+	maxLoginFailures int
+	lockoutDuration  time.Duration
+
+	// passwordMinAge é o intervalo mínimo entre trocas de senha pelo próprio usuário
+	passwordMinAge time.Duration
+}
+
+// UserServiceOption configura dependências e opções opcionais do UserService
+type UserServiceOption func(*UserService)
+
+// WithResetRedirectAllowlist define os destinos permitidos para o redirecionamento
+// dos links de redefinição de senha. Entradas inválidas são ignoradas.
+func WithResetRedirectAllowlist(uris []string) UserServiceOption {
+	return func(us *UserService) {
+		for _, raw := range uris {
+			u, err := url.Parse(raw)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				logging.Warning("Ignorando URI de redirecionamento inválida na allowlist: %q", raw)
+				continue
+			}
+			us.resetRedirectAllowlist = append(us.resetRedirectAllowlist, u)
+		}
+	}
+}
+
+// WithSessionStore registra cada refresh token emitido como uma sessão no store.
+// Com maxPerUser > 0, emitir uma sessão além do limite remove a mais antiga.
+func WithSessionStore(store domain.SessionStore, maxPerUser int) UserServiceOption {
+	return func(us *UserService) {
+		us.sessions = store
+		us.maxSessionsPerUser = maxPerUser
+	}
+}
+
+// WithSessionRevocationOnPasswordChange encerra todas as sessões do usuário após
+// a troca de senha, invalidando os refresh tokens já emitidos. Exige o store de
+// sessões; sem ele a opção não tem efeito.
+func WithSessionRevocationOnPasswordChange(enabled bool) UserServiceOption {
+	return func(us *UserService) {
+		us.revokeSessionsOnPasswordChange = enabled
+	}
+}
+
+// WithActivityStore registra logins e renovações de token no histórico de atividade
+func WithActivityStore(store domain.ActivityStore) UserServiceOption {
+	return func(us *UserService) {
+		us.activity = store
+	}
+}
+
+// WithAuditStore persiste a trilha de auditoria das ações administrativas
+func WithAuditStore(store domain.AuditStore) UserServiceOption {
+	return func(us *UserService) {
+		us.audit = store
+	}
+}
+
+// WithClock substitui o relógio usado nas medições de latência e marcações de tempo
+func WithClock(c clock.Clock) UserServiceOption {
+	return func(us *UserService) {
+		us.clock = c
+	}
+}
+
+// WithEventPublisher publica eventos de domínio, como a troca de email, no publicador
+func WithEventPublisher(publisher domain.EventPublisher) UserServiceOption {
+	return func(us *UserService) {
+		us.events = publisher
+	}
+}
+
+// WithEmailChangeNotification liga ou desliga o aviso ao email antigo quando o
+// email da conta muda (padrão ligado). Não afeta a entrega dos tokens de
+// redefinição de senha e de verificação de email.
+func WithEmailChangeNotification(enabled bool) UserServiceOption {
+	return func(us *UserService) {
+		us.notifyEmailChange = enabled
+	}
+}
+
+// WithTokenBlacklist define onde ficam as revogações de tokens pelo jti (rotação
+// e logout de refresh tokens, tokens de uso único). Sem ela, é usada uma
+// blacklist em memória, restrita à instância e perdida ao reiniciar.
+func WithTokenBlacklist(tokens domain.TokenBlacklist) UserServiceOption {
+	return func(us *UserService) {
+		us.blacklist = tokens
+	}
+}
+
+// WithPasswordMinAge exige um intervalo mínimo entre trocas de senha pelo próprio
+// usuário, impedindo que ele troque várias vezes seguidas para voltar a uma senha
+// antiga. Trocas obrigatórias e redefinições não são limitadas; minAge <= 0
+// desabilita a verificação.
+func WithPasswordMinAge(minAge time.Duration) UserServiceOption {
+	return func(us *UserService) {
+		us.passwordMinAge = minAge
+	}
+}
+
+// WithUsernameLogin permite que Authenticate aceite o username, além do email,
+// como identificador de login
+func WithUsernameLogin(enabled bool) UserServiceOption {
+	return func(us *UserService) {
+		us.usernameLogin = enabled
+	}
+}
+
+// WithDeferredRefreshToken faz o login (e o registro com login automático)
+// entregar apenas o access token; o primeiro refresh token passa a ser emitido
+// por IssueInitialRefreshToken, numa chamada autenticada à parte
+func WithDeferredRefreshToken(enabled bool) UserServiceOption {
+	return func(us *UserService) {
+		us.deferRefreshToken = enabled
+	}
+}
+
+// WithTenantScopedEmail torna o email único por organização (OrgID, Email) em
+// vez de globalmente, permitindo o mesmo email em organizações diferentes
+func WithTenantScopedEmail(enabled bool) UserServiceOption {
+	return func(us *UserService) {
+		us.tenantScopedEmail = enabled
+	}
+}
+
+// WithEmailVerificationRequired faz Authenticate recusar contas cujo email
+// ainda não foi verificado
+func WithEmailVerificationRequired(required bool) UserServiceOption {
+	return func(us *UserService) {
+		us.requireEmailVerification = required
+	}
 }
 
 // Garantir que UserService implementa domain.UserService
 var _ domain.UserService = (*UserService)(nil)
 
 // NewUserService cria uma nova instância do serviço de usuário
-func NewUserService(userRepo domain.UserRepository, jwtService *auth.JWTService) *UserService {
-	return &UserService{
+func NewUserService(userRepo domain.UserRepository, jwtService *auth.JWTService, opts ...UserServiceOption) *UserService {
+	us := &UserService{
 		userRepo:   userRepo,
 		jwtService: jwtService,
+		clock:      clock.System(),
+
+		notifyEmailChange: true,
+
+		usedResetTokens:        newTokenSet(nil),
+		usedVerificationTokens: newTokenSet(nil),
+		initialRefreshGrants:   newTokenSet(nil),
+	}
+	for _, opt := range opts {
+		opt(us)
 	}
+	if us.blacklist == nil {
+		us.blacklist = blacklist.NewMemoryStore(us.clock)
+	}
+	return us
 }
 
-// Create cria um novo usuário
+// Create cria um novo usuário. Fora do modo multi-tenant, o OrgID informado é
+// descartado: todas as contas ficam na organização padrão, e a restrição única
+// (OrgID, Email) do banco garante a unicidade global do email.
 func (us *UserService) Create(user *domain.User) error {
+	if !us.tenantScopedEmail {
+		user.OrgID = ""
+	}
+
 	// Verifica se já existe um usuário com o mesmo email
-	existingUser, err := us.userRepo.GetByEmail(user.Email)
+	user.Email = domain.NormalizeEmail(user.Email)
+	existingUser, err := us.findEmailOwner(user.OrgID, user.Email)
 	if err != nil {
 		logging.Error("Erro ao verificar email: %v", err)
 		return errors.ErrInternalServer.WithError(err)
@@ -40,17 +245,46 @@ func (us *UserService) Create(user *domain.User) error {
 		return errors.ErrEmailAlreadyExists
 	}
 
+	if user.Username != "" {
+		existingUser, err = us.userRepo.GetByUsername(user.Username)
+		if err != nil {
+			logging.Error("Erro ao verificar username: %v", err)
+			return errors.ErrInternalServer.WithError(err)
+		}
+
+		if existingUser != nil {
+			return errors.ErrUsernameAlreadyExists
+		}
+	}
+
+	if err := validator.ValidatePasswordStrength(user.Password); err != nil {
+		return err
+	}
+
+	if err := us.checkPasswordBreach(user.Password); err != nil {
+		return err
+	}
+
 	// Hash da senha
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := us.hashPassword(user.Password)
 	if err != nil {
-		logging.Error("Erro ao gerar hash da senha: %v", err)
-		return errors.ErrInternalServer.WithError(err)
+		return err
 	}
 
 	// Atualiza a senha com o hash
-	user.Password = string(hashedPassword)
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
+	user.Password = hashedPassword
+	user.CreatedAt = us.clock.Now()
+	user.UpdatedAt = us.clock.Now()
+
+	if user.Status == "" {
+		user.Status = domain.UserStatusActive
+	}
+
+	// Sem ator informado, a criação é atribuída à própria aplicação
+	if user.CreatedBy == "" {
+		user.CreatedBy = domain.ActorSystem
+	}
+	user.UpdatedBy = user.CreatedBy
 
 	// Salva o usuário no repositório
 	err = us.userRepo.Create(user)
@@ -59,9 +293,48 @@ func (us *UserService) Create(user *domain.User) error {
 		return errors.ErrInternalServer.WithError(err)
 	}
 
+	// Quem se cadastra sozinho precisa confirmar a posse do email
+	if user.CreatedBy == domain.ActorSelf && !user.EmailVerified {
+		us.sendEmailVerification(user)
+	}
+
 	return nil
 }
 
+// RegisterAndLogin cria o usuário e emite os tokens na mesma operação, evitando
+// o login em seguida ao cadastro. Quando a conta ainda não pode entrar (email não
+// verificado com REQUIRE_EMAIL_VERIFICATION ou troca de senha obrigatória), o
+// usuário é criado e o par retornado fica vazio.
+func (us *UserService) RegisterAndLogin(user *domain.User) (domain.TokenPair, error) {
+	if err := us.Create(user); err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	if (us.requireEmailVerification && !user.EmailVerified) || user.MustChangePassword {
+		logging.Info("Usuário %s registrado sem login automático: conta ainda não liberada para login", user.ID)
+		return domain.TokenPair{}, nil
+	}
+
+	accessToken, accessClaims, err := us.jwtService.IssueToken(user, auth.AuthMethodPassword)
+	if err != nil {
+		logging.Error("Erro ao gerar token JWT: %v", err)
+		return domain.TokenPair{}, errors.ErrInternalServer.WithError(err)
+	}
+	if us.deferRefreshToken {
+		us.grantInitialRefreshToken(accessClaims)
+		us.recordActivity(user.ID, domain.ActivityLogin)
+		return domain.TokenPair{AccessToken: accessToken}, nil
+	}
+	refreshToken, err := us.issueRefreshToken(user)
+	if err != nil {
+		logging.Error("Erro ao gerar refresh token: %v", err)
+		return domain.TokenPair{}, errors.ErrInternalServer.WithError(err)
+	}
+
+	us.recordActivity(user.ID, domain.ActivityLogin)
+	return domain.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
 // GetByID busca um usuário pelo ID
 func (us *UserService) GetByID(id string) (*domain.User, error) {
 	user, err := us.userRepo.GetByID(id)
@@ -79,7 +352,23 @@ func (us *UserService) GetByID(id string) (*domain.User, error) {
 
 // GetByEmail busca um usuário pelo email
 func (us *UserService) GetByEmail(email string) (*domain.User, error) {
-	user, err := us.userRepo.GetByEmail(email)
+	user, err := us.userRepo.GetByEmail(domain.NormalizeEmail(email))
+	if err != nil {
+		logging.Error("Erro ao buscar usuário por email: %v", err)
+		return nil, errors.ErrInternalServer.WithError(err)
+	}
+
+	if user == nil {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// GetByOrgAndEmail busca um usuário pelo email dentro da organização. Fora do
+// modo multi-tenant, o email é único e a organização é ignorada.
+func (us *UserService) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	user, err := us.findEmailOwner(orgID, email)
 	if err != nil {
 		logging.Error("Erro ao buscar usuário por email: %v", err)
 		return nil, errors.ErrInternalServer.WithError(err)
@@ -104,18 +393,181 @@ func (us *UserService) Update(user *domain.User) error {
 	if existingUser == nil {
 		return errors.ErrUserNotFound
 	}
+	oldEmail := existingUser.Email
+
+	user.Email = domain.NormalizeEmail(user.Email)
+	if err := us.ensureEmailAvailable(user.ID, existingUser.OrgID, user.Email); err != nil {
+		return err
+	}
+	if !user.IsAdmin() || !user.IsActive() {
+		if err := us.ensureNotLastAdmin(existingUser); err != nil {
+			return err
+		}
+	}
 
 	// Atualiza o usuário
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = us.clock.Now()
 	err = us.userRepo.Update(user)
 	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			logging.Warning("Conflito de versão ao atualizar usuário %s (versão %d)", user.ID, user.Version)
+			return errors.ErrUserVersionConflict
+		}
 		logging.Error("Erro ao atualizar usuário: %v", err)
 		return errors.ErrInternalServer.WithError(err)
 	}
 
+	us.publishEmailChanged(user.ID, oldEmail, user.Email, user.UpdatedBy)
+	return nil
+}
+
+// ensureEmailAvailable retorna ErrEmailAlreadyExists quando o email pertence a
+// outro usuário, evitando que a restrição única do banco vire um erro 500
+func (us *UserService) ensureEmailAvailable(userID, orgID, email string) error {
+	owner, err := us.findEmailOwner(orgID, email)
+	if err != nil {
+		logging.Error("Erro ao verificar email: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if owner != nil && owner.ID != userID {
+		logging.Warning("Usuário %s tentou usar o email de outra conta", userID)
+		return errors.ErrEmailAlreadyExists
+	}
+	return nil
+}
+
+// findEmailOwner busca quem já usa o email no escopo de unicidade: a organização
+// no modo multi-tenant ou todos os usuários caso contrário
+func (us *UserService) findEmailOwner(orgID, email string) (*domain.User, error) {
+	email = domain.NormalizeEmail(email)
+	if us.tenantScopedEmail {
+		return us.userRepo.GetByOrgAndEmail(orgID, email)
+	}
+	return us.userRepo.GetByEmail(email)
+}
+
+// UpdateFields atualiza apenas os campos informados (chaves domain.UserField*),
+// sem sobrescrever alterações concorrentes nos demais campos
+func (us *UserService) UpdateFields(id string, fields map[string]any) error {
+	existingUser, err := us.userRepo.GetByID(id)
+	if err != nil {
+		logging.Error("Erro ao verificar usuário: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	if existingUser == nil {
+		return errors.ErrUserNotFound
+	}
+
+	if newEmail, ok := fields[domain.UserFieldEmail].(string); ok {
+		if err := us.ensureEmailAvailable(id, existingUser.OrgID, newEmail); err != nil {
+			return err
+		}
+	}
+	if removesAdmin(fields) {
+		if err := us.ensureNotLastAdmin(existingUser); err != nil {
+			return err
+		}
+	}
+
+	// Copia o mapa para não alterar o do chamador ao aplicar o hash da senha
+	updates := make(map[string]any, len(fields))
+	for field, value := range fields {
+		updates[field] = value
+	}
+	if newEmail, ok := updates[domain.UserFieldEmail].(string); ok {
+		updates[domain.UserFieldEmail] = domain.NormalizeEmail(newEmail)
+	}
+
+	if password, ok := updates[domain.UserFieldPassword].(string); ok {
+		hashedPassword, err := us.hashPassword(password)
+		if err != nil {
+			return err
+		}
+		updates[domain.UserFieldPassword] = hashedPassword
+		updates[domain.UserFieldPasswordChangedAt] = us.clock.Now()
+	}
+
+	err = us.userRepo.UpdateFields(id, updates)
+	if err != nil {
+		logging.Error("Erro ao atualizar campos do usuário: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	if newEmail, ok := updates[domain.UserFieldEmail].(string); ok {
+		updatedBy, _ := updates[domain.UserFieldUpdatedBy].(string)
+		us.publishEmailChanged(id, existingUser.Email, newEmail, updatedBy)
+	}
 	return nil
 }
 
+// ChangePassword troca a senha do usuário após verificar a senha atual e limpa a
+// exigência de troca de senha, se houver
+func (us *UserService) ChangePassword(userID, currentPassword, newPassword string) error {
+	user, err := us.userRepo.GetByID(userID)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para troca de senha: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	if user == nil {
+		return errors.ErrUserNotFound
+	}
+
+	if err := us.comparePassword(user.Password, currentPassword); err != nil {
+		if errors.Is(err, errors.ErrTooManyRequests) {
+			return err
+		}
+		logging.Warning("Senha atual inválida na troca de senha do usuário %s", userID)
+		return errors.ErrInvalidCredentials
+	}
+
+	if us.changedPasswordTooRecently(user) {
+		logging.Warning("Troca de senha recusada para o usuário %s: intervalo mínimo não atingido", userID)
+		return errors.ErrPasswordChangeTooSoon
+	}
+
+	if err := validator.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	if err := us.checkPasswordBreach(newPassword); err != nil {
+		logging.Warning("Senha vazada recusada na troca de senha do usuário %s", userID)
+		return err
+	}
+
+	err = us.UpdateFields(userID, map[string]any{
+		domain.UserFieldPassword:           newPassword,
+		domain.UserFieldMustChangePassword: false,
+		domain.UserFieldUpdatedBy:          domain.ActorSelf,
+	})
+	if err != nil {
+		return err
+	}
+
+	if us.revokeSessionsOnPasswordChange && us.sessions != nil {
+		ended, err := us.endAllSessions(userID)
+		if err != nil {
+			// A senha já foi trocada; as sessões restantes expiram normalmente
+			logging.Error("Erro ao encerrar sessões após troca de senha do usuário %s: %v", userID, err)
+		} else {
+			logging.Info("%d sessões do usuário %s encerradas após troca de senha", ended, userID)
+		}
+	}
+
+	logging.Info("Senha alterada para o usuário %s", userID)
+	return nil
+}
+
+// changedPasswordTooRecently indica se a última troca de senha ocorreu há menos
+// que o intervalo mínimo. Usuários obrigados a trocar a senha nunca são barrados.
+func (us *UserService) changedPasswordTooRecently(user *domain.User) bool {
+	if us.passwordMinAge <= 0 || user.MustChangePassword || user.PasswordChangedAt.IsZero() {
+		return false
+	}
+	return us.clock.Now().Before(user.PasswordChangedAt.Add(us.passwordMinAge))
+}
+
 // Delete remove um usuário
 func (us *UserService) Delete(id string) error {
 	// Verifica se o usuário existe
@@ -128,6 +580,9 @@ func (us *UserService) Delete(id string) error {
 	if existingUser == nil {
 		return errors.ErrUserNotFound
 	}
+	if err := us.ensureNotLastAdmin(existingUser); err != nil {
+		return err
+	}
 
 	// Remove o usuário
 	err = us.userRepo.Delete(id)
@@ -139,55 +594,315 @@ func (us *UserService) Delete(id string) error {
 	return nil
 }
 
-// Authenticate autentica um usuário e retorna access token e refresh token
-func (us *UserService) Authenticate(email, password string) (string, string, error) {
-	// Busca o usuário pelo email
-	user, err := us.userRepo.GetByEmail(email)
+// ensureNotLastAdmin retorna ErrLastAdmin quando o usuário é o único
+// administrador ativo, para que a operação não deixe o sistema sem admin
+func (us *UserService) ensureNotLastAdmin(user *domain.User) error {
+	if !user.IsAdmin() || !user.IsActive() {
+		return nil
+	}
+
+	users, err := us.userRepo.List()
+	if err != nil {
+		logging.Error("Erro ao contar administradores: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	for _, other := range users {
+		if other.ID != user.ID && other.IsAdmin() && other.IsActive() {
+			return nil
+		}
+	}
+
+	logging.Warning("Operação recusada: o usuário %s é o último administrador ativo", user.ID)
+	return errors.ErrLastAdmin
+}
+
+// removesAdmin indica se a atualização parcial retira o papel de admin ou
+// desativa a conta
+func removesAdmin(fields map[string]any) bool {
+	if roles, ok := fields[domain.UserFieldRoles].([]string); ok && !domain.ContainsRole(roles, domain.RoleAdmin) {
+		return true
+	}
+	if status, ok := fields[domain.UserFieldStatus].(string); ok && !(&domain.User{Status: status}).IsActive() {
+		return true
+	}
+	return false
+}
+
+// Stats retorna as contagens agregadas de usuários
+func (us *UserService) Stats() (*domain.UserStats, error) {
+	stats, err := us.userRepo.Stats()
+	if err != nil {
+		logging.Error("Erro ao calcular estatísticas de usuários: %v", err)
+		return nil, errors.ErrInternalServer.WithError(err)
+	}
+	return stats, nil
+}
+
+// findByIdentifier resolve o identificador de login: valores com "@" são sempre
+// tratados como email, dentro da organização no modo multi-tenant; os demais
+// como username, quando o login por username está habilitado
+func (us *UserService) findByIdentifier(orgID, identifier string) (*domain.User, error) {
+	if us.usernameLogin && !strings.Contains(identifier, "@") {
+		return us.userRepo.GetByUsername(identifier)
+	}
+	return us.findEmailOwner(orgID, identifier)
+}
+
+// loginScope retorna a organização considerada no login: a informada no modo
+// multi-tenant e nenhuma caso contrário, para que variar org_id não contorne o
+// bloqueio por tentativas
+func (us *UserService) loginScope(orgID string) string {
+	if !us.tenantScopedEmail {
+		return ""
+	}
+	return orgID
+}
+
+// Authenticate autentica um usuário e retorna access token e refresh token. No
+// modo multi-tenant, o email é buscado na organização padrão (sem OrgID).
+func (us *UserService) Authenticate(identifier, password string) (string, string, error) {
+	return us.AuthenticateInOrg("", identifier, password)
+}
+
+// AuthenticateInOrg autentica um usuário da organização informada; fora do modo
+// multi-tenant, equivale a Authenticate
+func (us *UserService) AuthenticateInOrg(orgID, identifier, password string) (string, string, error) {
+	timer := newAuthTimer(us.clock)
+	defer timer.log()
+
+	// Contas bloqueadas são recusadas antes mesmo de conferir a senha
+	orgID = us.loginScope(orgID)
+	key := lockoutKey(orgID, identifier)
+	if err := us.checkLockout(key); err != nil {
+		logging.Warning("Tentativa de login em conta bloqueada")
+		return "", "", err
+	}
+
+	// Busca o usuário pelo email ou, se habilitado, pelo username
+	user, err := us.findByIdentifier(orgID, identifier)
+	timer.step("lookup")
 	if err != nil {
 		logging.Error("Erro ao buscar usuário para autenticação: %v", err)
 		return "", "", errors.ErrInternalServer.WithError(err)
 	}
 
 	if user == nil {
+		us.recordLoginFailure(key)
 		return "", "", errors.ErrInvalidCredentials
 	}
 
 	// Verifica a senha
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = us.comparePassword(user.Password, password)
+	timer.step("compare")
+	if errors.Is(err, errors.ErrTooManyRequests) {
+		return "", "", err
+	}
 	if err != nil {
-		logging.Error("Senha inválida para usuário %s: %v", email, err)
+		logging.Error("Senha inválida para usuário %s: %v", identifier, err)
+		us.recordLoginFailure(key)
 		return "", "", errors.ErrInvalidCredentials
 	}
+	us.resetLoginFailures(key)
+
+	if !user.IsActive() {
+		logging.Warning("Tentativa de login em conta desativada: %s", user.ID)
+		return "", "", errors.ErrAccountInactive
+	}
+
+	if us.requireEmailVerification && !user.EmailVerified {
+		logging.Warning("Tentativa de login com email não verificado: %s", user.ID)
+		return "", "", errors.ErrEmailNotVerified
+	}
+
+	// Usuários marcados recebem apenas um token restrito à troca de senha, sem sessão
+	if user.MustChangePassword {
+		accessToken, err := us.jwtService.GeneratePasswordChangeToken(user)
+		timer.step("token")
+		if err != nil {
+			logging.Error("Erro ao gerar token de troca de senha: %v", err)
+			return "", "", errors.ErrInternalServer.WithError(err)
+		}
+		logging.Info("Usuário %s autenticado com troca de senha obrigatória", user.ID)
+		return accessToken, "", nil
+	}
 
 	// Gera o token JWT
-	accessToken, err := us.jwtService.GenerateToken(user)
+	accessToken, accessClaims, err := us.jwtService.IssueToken(user, auth.AuthMethodPassword)
 	if err != nil {
 		logging.Error("Erro ao gerar token JWT: %v", err)
 		return "", "", errors.ErrInternalServer.WithError(err)
 	}
 
-	refreshToken, err := us.jwtService.GenerateRefreshToken(user.ID)
+	// Com emissão adiada, o refresh token é obtido depois em IssueInitialRefreshToken
+	if us.deferRefreshToken {
+		us.grantInitialRefreshToken(accessClaims)
+		timer.step("token")
+		us.recordActivity(user.ID, domain.ActivityLogin)
+		return accessToken, "", nil
+	}
+
+	refreshToken, err := us.issueRefreshToken(user)
+	timer.step("token")
 	if err != nil {
 		logging.Error("Erro ao gerar refresh token: %v", err)
 		return "", "", errors.ErrInternalServer.WithError(err)
 	}
 
+	us.recordActivity(user.ID, domain.ActivityLogin)
 	return accessToken, refreshToken, nil
 }
 
-// refreshTokenBlacklist é um mapa em memória para blacklist de refresh tokens
-var refreshTokenBlacklist = make(map[string]struct{})
+// IssueInitialRefreshToken emite o primeiro refresh token para o usuário já
+// autenticado pelo access token, quando o login não o entrega
+// (WithDeferredRefreshToken). Fora desse modo, a chamada é recusada. Apenas o
+// access token entregue no login ou no registro, identificado pelo seu jti,
+// obtém o refresh token, e uma única vez.
+func (us *UserService) IssueInitialRefreshToken(userID, accessTokenID string) (string, error) {
+	if !us.deferRefreshToken {
+		return "", errors.ErrForbidden.WithMessage("Emissão dedicada de refresh token não está habilitada")
+	}
+
+	user, err := us.GetByID(userID)
+	if err != nil {
+		return "", err
+	}
+	if !user.IsActive() {
+		logging.Warning("Tentativa de emitir refresh token para conta desativada: %s", user.ID)
+		return "", errors.ErrAccountInactive
+	}
+	if user.MustChangePassword {
+		return "", errors.ErrPasswordChangeRequired
+	}
+	if !us.initialRefreshGrants.Take(initialRefreshKey(user.ID, accessTokenID)) {
+		logging.Warning("Refresh token inicial recusado para o usuário %s: access token já usado ou não emitido no login", user.ID)
+		return "", errors.ErrForbidden.WithMessage("O refresh token inicial só é emitido uma vez, com o access token do login")
+	}
+
+	refreshToken, err := us.issueRefreshToken(user)
+	if err != nil {
+		logging.Error("Erro ao gerar refresh token: %v", err)
+		return "", errors.ErrInternalServer.WithError(err)
+	}
+
+	logging.Info("Refresh token inicial emitido para o usuário %s", user.ID)
+	return refreshToken, nil
+}
+
+// grantInitialRefreshToken permite que o access token de login informado obtenha
+// o refresh token inicial enquanto for válido
+func (us *UserService) grantInitialRefreshToken(claims *auth.TokenClaims) {
+	us.initialRefreshGrants.Add(initialRefreshKey(claims.UserID, claims.ID), claims.ExpiresAt.Time)
+}
+
+func initialRefreshKey(userID, accessTokenID string) string {
+	return userID + "/" + accessTokenID
+}
+
+// issueRefreshToken gera um refresh token e, com sessões habilitadas, registra a
+// sessão correspondente, removendo as mais antigas além do limite por usuário
+func (us *UserService) issueRefreshToken(user *domain.User) (string, error) {
+	userID := user.ID
+	token, claims, err := us.jwtService.IssueRefreshTokenAt(userID, us.clock.Now(), revocationMark(user))
+	if err != nil {
+		return "", err
+	}
+	if us.sessions == nil {
+		return token, nil
+	}
+
+	if us.maxSessionsPerUser > 0 {
+		active, err := us.sessions.ListByUser(userID)
+		if err != nil {
+			return "", err
+		}
+		for i := 0; i <= len(active)-us.maxSessionsPerUser; i++ {
+			logging.Info("Limite de sessões atingido para o usuário %s, encerrando a sessão %s", userID, active[i].ID)
+			if err := us.sessions.Delete(active[i].ID); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	err = us.sessions.Create(&domain.Session{
+		ID:        claims.ID,
+		UserID:    userID,
+		CreatedAt: us.clock.Now(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// publishEmailChanged publica EmailChangedEvent quando o email realmente mudou.
+// Falhas na publicação são apenas registradas, sem desfazer a alteração.
+func (us *UserService) publishEmailChanged(userID, oldEmail, newEmail, changedBy string) {
+	if us.events == nil || !us.notifyEmailChange || oldEmail == newEmail {
+		return
+	}
+	err := us.events.Publish(domain.EmailChangedEvent{
+		UserID:    userID,
+		OldEmail:  oldEmail,
+		NewEmail:  newEmail,
+		ChangedBy: changedBy,
+		At:        us.clock.Now(),
+	})
+	if err != nil {
+		logging.Error("Erro ao publicar troca de email do usuário %s: %v", userID, err)
+	}
+}
+
+// recordActivity registra um evento no histórico de atividade, se configurado.
+// Falhas são apenas logadas para não impedir a autenticação.
+func (us *UserService) recordActivity(userID, eventType string) {
+	if us.activity == nil {
+		return
+	}
+	err := us.activity.Record(&domain.ActivityEvent{UserID: userID, Type: eventType, At: us.clock.Now()})
+	if err != nil {
+		logging.Error("Erro ao registrar atividade %s do usuário %s: %v", eventType, userID, err)
+	}
+}
+
+// blacklistKey retorna a chave do refresh token na blacklist: o jti ou, para
+// tokens antigos sem jti, o próprio token
+func blacklistKey(jti, token string) string {
+	if jti != "" {
+		return jti
+	}
+	return token
+}
 
 // RefreshTokens realiza a rotação do refresh token e gera novos tokens
 func (us *UserService) RefreshTokens(refreshToken string) (string, string, error) {
-	// Verifica se o token está na blacklist
-	if _, blacklisted := refreshTokenBlacklist[refreshToken]; blacklisted {
-		return "", "", errors.ErrUnauthorized.WithMessage("Refresh token inválido ou já utilizado")
+	claims, err := us.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return "", "", errors.ErrRefreshTokenInvalid.WithError(err)
 	}
 
-	claims, err := us.jwtService.ValidateRefreshToken(refreshToken)
+	// Tokens já rotacionados ou revogados no logout recebem um código próprio,
+	// para que o cliente refaça o login em vez de tratar o token como corrompido
+	key := blacklistKey(claims.ID, refreshToken)
+	revoked, err := us.blacklist.Contains(key)
 	if err != nil {
-		return "", "", errors.ErrUnauthorized.WithError(err)
+		logging.Error("Erro ao consultar revogação do refresh token: %v", err)
+		return "", "", errors.ErrInternalServer.WithError(err)
+	}
+	if revoked {
+		return "", "", errors.ErrRefreshTokenReused
+	}
+
+	// Com sessões habilitadas, o token precisa corresponder a uma sessão ativa
+	if us.sessions != nil {
+		session, err := us.sessions.GetByID(claims.ID)
+		if err != nil {
+			logging.Error("Erro ao buscar sessão: %v", err)
+			return "", "", errors.ErrInternalServer.WithError(err)
+		}
+		if session == nil || session.UserID != claims.Subject {
+			return "", "", errors.ErrRefreshTokenReused.WithMessage("Sessão encerrada ou inexistente")
+		}
 	}
 
 	userID := claims.Subject
@@ -195,31 +910,235 @@ func (us *UserService) RefreshTokens(refreshToken string) (string, string, error
 	if err != nil || user == nil {
 		return "", "", errors.ErrUserNotFound
 	}
+	if !user.IsActive() {
+		return "", "", errors.ErrAccountInactive
+	}
+	if issuedBeforeRevocation(claims, user) {
+		return "", "", errors.ErrRefreshTokenReused.WithMessage("Refresh token revogado pelo encerramento de todas as sessões")
+	}
 
-	// Gera novos tokens
-	accessToken, err := us.jwtService.GenerateToken(user)
+	// Gera novos tokens. O refresh token não guarda o método original, então o
+	// novo access token registra apenas a senha: rotas que exigem autenticação
+	// mais forte pedem um novo login
+	accessToken, err := us.jwtService.GenerateToken(user, auth.AuthMethodPassword)
 	if err != nil {
 		return "", "", errors.ErrInternalServer.WithError(err)
 	}
-	newRefreshToken, err := us.jwtService.GenerateRefreshToken(user.ID)
+	newRefreshToken, err := us.issueRefreshToken(user)
 	if err != nil {
 		return "", "", errors.ErrInternalServer.WithError(err)
 	}
 
-	// Adiciona o refresh token antigo à blacklist
-	refreshTokenBlacklist[refreshToken] = struct{}{}
+	// Adiciona o refresh token antigo à blacklist e encerra a sua sessão
+	if err := us.revokeToken(key, claims.ExpiresAt.Time); err != nil {
+		logging.Error("Erro ao revogar refresh token rotacionado: %v", err)
+	}
+	if us.sessions != nil {
+		if err := us.sessions.Delete(claims.ID); err != nil {
+			logging.Error("Erro ao remover sessão rotacionada: %v", err)
+		}
+	}
+
+	us.recordActivity(user.ID, domain.ActivityTokenRefresh)
 
 	return accessToken, newRefreshToken, nil
 }
 
-// BlacklistRefreshToken adiciona um refresh token à blacklist em memória
-func BlacklistRefreshToken(token string) {
-	refreshTokenBlacklist[token] = struct{}{}
+// RevokeRefreshToken revoga o refresh token no logout: na blacklist até a sua
+// expiração e, se configurado, também no store de sessões, de onde a sessão
+// correspondente é removida
+func (us *UserService) RevokeRefreshToken(refreshToken string) error {
+	// Tokens inválidos ou expirados já não podem ser usados
+	claims, err := us.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil
+	}
+	if us.sessions != nil && claims.ID != "" {
+		if err := us.sessions.Delete(claims.ID); err != nil {
+			logging.Error("Erro ao encerrar sessão %s no logout: %v", claims.ID, err)
+			return errors.ErrInternalServer.WithError(err)
+		}
+	}
+	if err := us.revokeToken(blacklistKey(claims.ID, refreshToken), claims.ExpiresAt.Time); err != nil {
+		logging.Error("Erro ao revogar refresh token: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	return nil
 }
 
-// ClearRefreshTokenBlacklist limpa a blacklist de refresh tokens (usado apenas para testes)
-func ClearRefreshTokenBlacklist() {
-	refreshTokenBlacklist = make(map[string]struct{})
+// ListSessions lista as sessões ativas do usuário, da mais antiga para a mais
+// recente. Exige o store de sessões.
+func (us *UserService) ListSessions(userID string) ([]*domain.Session, error) {
+	if us.sessions == nil {
+		return nil, errors.ErrSessionsUnavailable
+	}
+	sessions, err := us.sessions.ListByUser(userID)
+	if err != nil {
+		logging.Error("Erro ao listar sessões do usuário %s: %v", userID, err)
+		return nil, errors.ErrInternalServer.WithError(err)
+	}
+	return sessions, nil
+}
+
+// RotateSessions encerra todas as sessões do usuário, inclusive a do refresh token
+// informado, e emite um novo par de tokens para quem fez a chamada ("sair de todos
+// os outros dispositivos"). Exige o store de sessões.
+func (us *UserService) RotateSessions(userID, refreshToken string) (string, string, error) {
+	if us.sessions == nil {
+		return "", "", errors.ErrSessionsUnavailable
+	}
+
+	claims, err := us.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil || claims.Subject != userID {
+		return "", "", errors.ErrUnauthorized.WithMessage("Refresh token inválido para o usuário autenticado")
+	}
+	current, err := us.sessions.GetByID(claims.ID)
+	if err != nil {
+		logging.Error("Erro ao buscar sessão: %v", err)
+		return "", "", errors.ErrInternalServer.WithError(err)
+	}
+	if current == nil || current.UserID != userID {
+		return "", "", errors.ErrUnauthorized.WithMessage("Sessão encerrada ou inexistente")
+	}
+
+	user, err := us.userRepo.GetByID(userID)
+	if err != nil || user == nil {
+		return "", "", errors.ErrUserNotFound
+	}
+
+	ended, err := us.endAllSessions(userID)
+	if err != nil {
+		return "", "", errors.ErrInternalServer.WithError(err)
+	}
+
+	accessToken, err := us.jwtService.GenerateToken(user, auth.AuthMethodPassword)
+	if err != nil {
+		return "", "", errors.ErrInternalServer.WithError(err)
+	}
+	newRefreshToken, err := us.issueRefreshToken(user)
+	if err != nil {
+		return "", "", errors.ErrInternalServer.WithError(err)
+	}
+
+	logging.Info("Sessões do usuário %s rotacionadas (%d encerradas)", userID, ended)
+	return accessToken, newRefreshToken, nil
+}
+
+// RevokeAllTokens recusa todos os refresh tokens do usuário emitidos até agora
+// ("sair de todos os dispositivos"), registrando o instante da revogação no
+// usuário. Sessões registradas no store também são encerradas. Access tokens
+// já emitidos continuam válidos até expirar.
+func (us *UserService) RevokeAllTokens(userID string) error {
+	user, err := us.userRepo.GetByID(userID)
+	if err != nil {
+		logging.Error("Erro ao buscar usuário para revogar tokens: %v", err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+	if user == nil {
+		return errors.ErrUserNotFound
+	}
+
+	err = us.userRepo.UpdateFields(userID, map[string]any{
+		domain.UserFieldTokensRevokedAt: us.clock.Now(),
+	})
+	if err != nil {
+		logging.Error("Erro ao registrar revogação dos tokens do usuário %s: %v", userID, err)
+		return errors.ErrInternalServer.WithError(err)
+	}
+
+	if us.sessions != nil {
+		ended, err := us.endAllSessions(userID)
+		if err != nil {
+			// A revogação por instante já recusa os tokens das sessões restantes
+			logging.Error("Erro ao encerrar sessões do usuário %s: %v", userID, err)
+		} else {
+			logging.Info("%d sessões do usuário %s encerradas", ended, userID)
+		}
+	}
+
+	logging.Info("Todos os refresh tokens do usuário %s foram revogados", userID)
+	return nil
+}
+
+// issuedBeforeRevocation indica se o refresh token foi emitido antes da última
+// revogação de todos os tokens do usuário. O token guarda a marca da revogação
+// vigente na emissão: qualquer revogação posterior a altera, mesmo no mesmo
+// segundo. Sem marca, nenhuma revogação precedia a emissão, e a claim iat (com
+// precisão de segundos) nunca é posterior à revogação; tokens sem iat são
+// anteriores a este controle.
+func issuedBeforeRevocation(claims *auth.RefreshClaims, user *domain.User) bool {
+	if user.TokensRevokedAt.IsZero() {
+		return false
+	}
+	if claims.RevocationMark != 0 {
+		return claims.RevocationMark != revocationMark(user)
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return !claims.IssuedAt.Time.After(user.TokensRevokedAt.Truncate(time.Second))
+}
+
+// revocationMark identifica a última revogação de todos os tokens do usuário
+// (0 quando não houve nenhuma)
+func revocationMark(user *domain.User) int64 {
+	if user.TokensRevokedAt.IsZero() {
+		return 0
+	}
+	return user.TokensRevokedAt.UnixNano()
+}
+
+// endAllSessions remove todas as sessões do usuário do store e revoga os seus
+// refresh tokens, retornando quantas foram encerradas
+func (us *UserService) endAllSessions(userID string) (int, error) {
+	active, err := us.sessions.ListByUser(userID)
+	if err != nil {
+		logging.Error("Erro ao listar sessões: %v", err)
+		return 0, err
+	}
+	for _, s := range active {
+		if err := us.sessions.Delete(s.ID); err != nil {
+			logging.Error("Erro ao encerrar sessão %s: %v", s.ID, err)
+			return 0, err
+		}
+		if err := us.revokeToken(s.ID, s.ExpiresAt); err != nil {
+			logging.Error("Erro ao revogar sessão %s: %v", s.ID, err)
+		}
+	}
+	return len(active), nil
+}
+
+// revokeToken registra o jti na blacklist até expiresAt, a expiração do token
+func (us *UserService) revokeToken(jti string, expiresAt time.Time) error {
+	return us.blacklist.Add(jti, expiresAt.Sub(us.clock.Now()))
+}
+
+// ValidateResetRedirect verifica se o destino de redirecionamento de um link de
+// redefinição de senha está na allowlist, evitando open redirects. O destino deve
+// ter o mesmo esquema e host de uma entrada e estar sob o seu caminho.
+// Um destino vazio significa "sem redirecionamento" e é sempre aceito.
+func (us *UserService) ValidateResetRedirect(redirect string) error {
+	if redirect == "" {
+		return nil
+	}
+
+	target, err := url.Parse(redirect)
+	if err != nil || target.Scheme == "" || target.Host == "" || target.User != nil {
+		return errors.ErrInvalidRedirect
+	}
+
+	for _, allowed := range us.resetRedirectAllowlist {
+		if !strings.EqualFold(target.Scheme, allowed.Scheme) || !strings.EqualFold(target.Host, allowed.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(allowed.Path, "/")
+		if target.Path == prefix || strings.HasPrefix(target.Path, prefix+"/") || prefix == "" {
+			return nil
+		}
+	}
+
+	logging.Warning("Redirecionamento de redefinição de senha recusado: %s", redirect)
+	return errors.ErrInvalidRedirect
 }
 
 // GetJWTService retorna o ponteiro do JWTService (uso exclusivo para testes)
@@ -234,10 +1153,42 @@ func (us *UserService) ListAll() ([]*domain.User, error) {
 		logging.Error("Erro ao listar usuários: %v", err)
 		return nil, errors.ErrInternalServer.WithError(err)
 	}
-	return users, nil
+	return nonNilUsers(users), nil
 }
 
 // List implementa a interface domain.UserService
 func (us *UserService) List() ([]*domain.User, error) {
 	return us.ListAll()
 }
+
+// ListPaginated retorna uma página de usuários (admin) e o total cadastrado
+func (us *UserService) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, 0, errors.ErrBadRequest.WithMessage("Paginação inválida")
+	}
+	users, total, err := us.userRepo.ListPaginated(offset, limit)
+	if err != nil {
+		logging.Error("Erro ao listar página de usuários: %v", err)
+		return nil, 0, errors.ErrInternalServer.WithError(err)
+	}
+	return nonNilUsers(users), total, nil
+}
+
+// ListCreatedBetween lista os usuários criados no intervalo [from, to)
+func (us *UserService) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	users, err := us.userRepo.ListCreatedBetween(from, to)
+	if err != nil {
+		logging.Error("Erro ao listar usuários por data de criação: %v", err)
+		return nil, errors.ErrInternalServer.WithError(err)
+	}
+	return nonNilUsers(users), nil
+}
+
+// nonNilUsers normaliza listagens vazias para um slice vazio: alguns repositórios
+// retornam nil e outros []*domain.User{}
+func nonNilUsers(users []*domain.User) []*domain.User {
+	if users == nil {
+		return []*domain.User{}
+	}
+	return users
+}
@@ -1,12 +1,22 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/session"
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	pkgerrors "github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type mockUserRepo struct {
@@ -41,11 +51,70 @@ func (m *mockUserRepo) GetByEmail(email string) (*domain.User, error) {
 	}
 	return nil, nil
 }
+func (m *mockUserRepo) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	for _, u := range m.users {
+		if u.OrgID == orgID && u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockUserRepo) GetByUsername(username string) (*domain.User, error) {
+	for _, u := range m.users {
+		if u.Username != "" && u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
 func (m *mockUserRepo) Update(user *domain.User) error {
-	if _, ok := m.users[user.ID]; !ok {
+	stored, ok := m.users[user.ID]
+	if !ok {
 		return errors.New("not found")
 	}
-	m.users[user.ID] = user
+	if stored != user && stored.Version != user.Version {
+		return domain.ErrVersionConflict
+	}
+	user.Version++
+	saved := *user
+	m.users[user.ID] = &saved
+	return nil
+}
+func (m *mockUserRepo) UpdateFields(id string, fields map[string]any) error {
+	u, ok := m.users[id]
+	if !ok {
+		return errors.New("not found")
+	}
+	// Grava uma cópia, como um banco faria, atualizando apenas as colunas informadas
+	updated := *u
+	updated.Version++
+	for field, value := range fields {
+		switch field {
+		case domain.UserFieldEmail:
+			updated.Email = value.(string)
+		case domain.UserFieldPassword:
+			updated.Password = value.(string)
+		case domain.UserFieldName:
+			updated.Name = value.(string)
+		case domain.UserFieldRoles:
+			updated.Roles = value.([]string)
+		case domain.UserFieldUpdatedBy:
+			updated.UpdatedBy = value.(string)
+		case domain.UserFieldMustChangePassword:
+			updated.MustChangePassword = value.(bool)
+		case domain.UserFieldStatus:
+			updated.Status = value.(string)
+		case domain.UserFieldPasswordChangedAt:
+			updated.PasswordChangedAt = value.(time.Time)
+		case domain.UserFieldTokensRevokedAt:
+			updated.TokensRevokedAt = value.(time.Time)
+		case domain.UserFieldEmailVerified:
+			updated.EmailVerified = value.(bool)
+		default:
+			return errors.New("unknown field")
+		}
+	}
+	m.users[id] = &updated
 	return nil
 }
 func (m *mockUserRepo) Delete(id string) error {
@@ -62,6 +131,43 @@ func (m *mockUserRepo) List() ([]*domain.User, error) {
 	}
 	return list, nil
 }
+func (m *mockUserRepo) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	var list []*domain.User
+	for _, u := range m.users {
+		if (from.IsZero() || !u.CreatedAt.Before(from)) && (to.IsZero() || u.CreatedAt.Before(to)) {
+			list = append(list, u)
+		}
+	}
+	return list, nil
+}
+func (m *mockUserRepo) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	list := make([]*domain.User, 0, len(m.users))
+	for _, u := range m.users {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	if offset >= len(list) {
+		return []*domain.User{}, len(list), nil
+	}
+	return list[offset:min(offset+limit, len(list))], len(list), nil
+}
+func (m *mockUserRepo) Stats() (*domain.UserStats, error) {
+	stats := &domain.UserStats{Total: len(m.users)}
+	for _, u := range m.users {
+		if u.EmailVerified {
+			stats.Verified++
+		}
+		if u.IsAdmin() {
+			stats.Admins++
+		}
+	}
+	return stats, nil
+}
 
 type errorRepo struct{}
 
@@ -70,15 +176,37 @@ func (e *errorRepo) GetByID(id string) (*domain.User, error) { return nil, error
 func (e *errorRepo) GetByEmail(email string) (*domain.User, error) {
 	return nil, errors.New("repo error")
 }
+func (e *errorRepo) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	return nil, errors.New("repo error")
+}
+func (e *errorRepo) GetByUsername(username string) (*domain.User, error) {
+	return nil, errors.New("repo error")
+}
 func (e *errorRepo) Update(user *domain.User) error { return errors.New("repo error") }
-func (e *errorRepo) Delete(id string) error         { return errors.New("repo error") }
-func (e *errorRepo) List() ([]*domain.User, error)  { return nil, errors.New("repo error") }
+func (e *errorRepo) UpdateFields(id string, fields map[string]any) error {
+	return errors.New("repo error")
+}
+func (e *errorRepo) Delete(id string) error        { return errors.New("repo error") }
+func (e *errorRepo) List() ([]*domain.User, error) { return nil, errors.New("repo error") }
+func (e *errorRepo) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	return nil, errors.New("repo error")
+}
+func (e *errorRepo) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	return nil, 0, errors.New("repo error")
+}
+func (e *errorRepo) Stats() (*domain.UserStats, error) { return nil, errors.New("repo error") }
+
+func TestUserService_ImplementsDomainInterface(t *testing.T) {
+	// Falha na compilação se o contrato de domain.UserService divergir da implementação
+	var svc domain.UserService = NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NotNil(t, svc)
+}
 
 func TestUserService_CreateAndGet(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	user := &domain.User{ID: "1", Email: "a@b.com", Password: "senha", Name: "A"}
+	user := &domain.User{ID: "1", Email: "a@b.com", Password: "senha123", Name: "A"}
 	err := us.Create(user)
 	assert.NoError(t, err)
 	// Não permite duplicado
@@ -94,11 +222,107 @@ func TestUserService_CreateAndGet(t *testing.T) {
 	assert.Equal(t, "1", u.ID)
 }
 
+func TestUserService_Create_TenantScopedEmail(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithTenantScopedEmail(true))
+
+	// O mesmo email coexiste em organizações diferentes
+	assert.NoError(t, us.Create(&domain.User{ID: "1", OrgID: "org-a", Email: "a@b.com", Password: "senha123"}))
+	assert.NoError(t, us.Create(&domain.User{ID: "2", OrgID: "org-b", Email: "a@b.com", Password: "senha123"}))
+
+	// Dentro da mesma organização o email continua único
+	err := us.Create(&domain.User{ID: "3", OrgID: "org-a", Email: "a@b.com", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+
+	// A troca de email também respeita o escopo da organização
+	assert.NoError(t, us.Create(&domain.User{ID: "4", OrgID: "org-b", Email: "c@b.com", Password: "senha123"}))
+	err = us.UpdateFields("4", map[string]any{domain.UserFieldEmail: "a@b.com"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+	assert.NoError(t, us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "c@b.com"}))
+}
+
+func TestUserService_AuthenticateInOrg_TenantScopedEmail(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	spy := &spyPublisher{}
+	us := NewUserService(repo, jwtService, WithTenantScopedEmail(true), WithEventPublisher(spy))
+	assert.NoError(t, us.Create(&domain.User{ID: "1", OrgID: "org-a", Email: "a@b.com", Password: "org-a-senha1"}))
+	assert.NoError(t, us.Create(&domain.User{ID: "2", OrgID: "org-b", Email: "a@b.com", Password: "org-b-senha1"}))
+
+	// Cada conta entra pela sua organização, com a sua senha
+	for orgID, want := range map[string]string{"org-a": "1", "org-b": "2"} {
+		access, _, err := us.AuthenticateInOrg(orgID, "a@b.com", orgID+"-senha1")
+		assert.NoError(t, err, orgID)
+		claims, err := jwtService.ValidateToken(access)
+		assert.NoError(t, err)
+		assert.Equal(t, want, claims.UserID)
+	}
+
+	// A senha de uma organização não vale na outra
+	_, _, err := us.AuthenticateInOrg("org-a", "a@b.com", "org-b-senha1")
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+
+	// A redefinição e a busca administrativa também resolvem o email na organização
+	assert.NoError(t, us.RequestPasswordReset(domain.PasswordResetRequest{OrgID: "org-b", Email: "a@b.com"}))
+	if assert.Len(t, spy.events, 1) {
+		assert.Equal(t, "2", spy.events[0].(domain.PasswordResetRequestedEvent).UserID)
+	}
+	u, err := us.GetByOrgAndEmail("org-b", "A@b.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", u.ID)
+}
+
+func TestUserService_Create_GlobalEmailWithoutTenantScope(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+
+	assert.NoError(t, us.Create(&domain.User{ID: "1", OrgID: "org-a", Email: "a@b.com", Password: "senha123"}))
+	err := us.Create(&domain.User{ID: "2", OrgID: "org-b", Email: "a@b.com", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+
+	// O org_id do cliente é descartado, então a restrição (OrgID, Email) do
+	// banco também recusa emails repetidos
+	assert.Empty(t, repo.users["1"].OrgID)
+}
+
+func TestUserService_RegisterAndLogin(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+
+	pair, err := us.RegisterAndLogin(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"})
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", claims.UserID)
+	refresh, err := jwtService.ValidateRefreshToken(pair.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", refresh.Subject)
+	assert.NotNil(t, repo.users["1"])
+
+	// Email duplicado falha como em Create, sem emitir tokens
+	pair, err = us.RegisterAndLogin(&domain.User{ID: "2", Email: "a@b.com", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+	assert.Empty(t, pair.AccessToken)
+}
+
+func TestUserService_RegisterAndLogin_EmailVerificationPending(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1), WithEmailVerificationRequired(true))
+
+	pair, err := us.RegisterAndLogin(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123", CreatedBy: domain.ActorSelf})
+
+	// O usuário é criado, mas só recebe tokens após confirmar o email
+	assert.NoError(t, err)
+	assert.Equal(t, domain.TokenPair{}, pair)
+	assert.NotNil(t, repo.users["1"])
+}
+
 func TestUserService_UpdateAndDelete(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	user := &domain.User{ID: "2", Email: "b@b.com", Password: "senha", Name: "B"}
+	user := &domain.User{ID: "2", Email: "b@b.com", Password: "senha123", Name: "B"}
 	_ = us.Create(user)
 	user.Name = "Novo Nome"
 	err := us.Update(user)
@@ -127,17 +351,137 @@ func TestUserService_Authenticate(t *testing.T) {
 	_, _, err = us.Authenticate("c@b.com", "errada")
 	assert.Error(t, err)
 	// Email não existe
-	_, _, err = us.Authenticate("nao@existe.com", "senha")
+	_, _, err = us.Authenticate("nao@existe.com", "senha123")
 	assert.Error(t, err)
 }
 
+func TestUserService_Authenticate_MixedCaseEmail(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NoError(t, us.Create(&domain.User{ID: "mc", Email: "Foo@Bar.com", Password: "senha123"}))
+	assert.Equal(t, "foo@bar.com", repo.users["mc"].Email)
+
+	// O email informado no login e na redefinição é normalizado como no registro
+	for _, identifier := range []string{"Foo@Bar.com", " FOO@BAR.COM ", "foo@bar.com"} {
+		_, _, err := us.Authenticate(identifier, "senha123")
+		assert.NoError(t, err, identifier)
+	}
+	assert.NoError(t, us.RequestPasswordReset(domain.PasswordResetRequest{Email: "Foo@Bar.com"}))
+
+	// E a verificação de duplicidade ignora a caixa
+	err := us.Create(&domain.User{ID: "mc2", Email: "FOO@bar.com", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+}
+
+func TestUserService_Authenticate_DeferredRefreshToken(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService, WithDeferredRefreshToken(true))
+	assert.NoError(t, us.Create(&domain.User{ID: "d1", Email: "d@b.com", Password: "senha123"}))
+
+	// O login entrega apenas o access token
+	access, refresh, err := us.Authenticate("d@b.com", "senha123")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, access)
+	assert.Empty(t, refresh)
+
+	// O refresh token inicial é emitido à parte e pode ser renovado normalmente
+	accessClaims, err := jwtService.ValidateToken(access)
+	assert.NoError(t, err)
+	refresh, err = us.IssueInitialRefreshToken("d1", accessClaims.ID)
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateRefreshToken(refresh)
+	assert.NoError(t, err)
+	assert.Equal(t, "d1", claims.Subject)
+	_, _, err = us.RefreshTokens(refresh)
+	assert.NoError(t, err)
+
+	// O registro com login automático segue o mesmo modo
+	pair, err := us.RegisterAndLogin(&domain.User{ID: "d2", Email: "d2@b.com", Password: "senha123"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.Empty(t, pair.RefreshToken)
+	registerClaims, err := jwtService.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+	_, err = us.IssueInitialRefreshToken("d2", registerClaims.ID)
+	assert.NoError(t, err)
+
+	_, err = us.IssueInitialRefreshToken("inexistente", accessClaims.ID)
+	assert.ErrorIs(t, err, pkgerrors.ErrUserNotFound)
+}
+
+func TestUserService_IssueInitialRefreshToken_SingleUse(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService, WithDeferredRefreshToken(true))
+	assert.NoError(t, us.Create(&domain.User{ID: "d1", Email: "d@b.com", Password: "senha123"}))
+	assert.NoError(t, us.Create(&domain.User{ID: "d2", Email: "d2@b.com", Password: "senha123"}))
+
+	access, _, err := us.Authenticate("d@b.com", "senha123")
+	assert.NoError(t, err)
+	accessClaims, err := jwtService.ValidateToken(access)
+	assert.NoError(t, err)
+
+	// O jti do login não serve para outro usuário
+	_, err = us.IssueInitialRefreshToken("d2", accessClaims.ID)
+	assert.Equal(t, http.StatusForbidden, pkgerrors.GetStatusCode(err))
+
+	refresh, err := us.IssueInitialRefreshToken("d1", accessClaims.ID)
+	assert.NoError(t, err)
+
+	// Uma segunda chamada com o mesmo access token é recusada
+	_, err = us.IssueInitialRefreshToken("d1", accessClaims.ID)
+	assert.Equal(t, http.StatusForbidden, pkgerrors.GetStatusCode(err))
+
+	// Assim como access tokens obtidos pela renovação
+	refreshed, _, err := us.RefreshTokens(refresh)
+	assert.NoError(t, err)
+	refreshedClaims, err := jwtService.ValidateToken(refreshed)
+	assert.NoError(t, err)
+	_, err = us.IssueInitialRefreshToken("d1", refreshedClaims.ID)
+	assert.Equal(t, http.StatusForbidden, pkgerrors.GetStatusCode(err))
+}
+
+func TestUserService_IssueInitialRefreshToken_Disabled(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NoError(t, us.Create(&domain.User{ID: "d1", Email: "d@b.com", Password: "senha123"}))
+
+	// Fora do modo adiado, o login já entrega o refresh token e a emissão dedicada é recusada
+	_, err := us.IssueInitialRefreshToken("d1", "jti")
+	assert.Equal(t, http.StatusForbidden, pkgerrors.GetStatusCode(err))
+}
+
+func TestUserService_Authenticate_Username(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService, WithUsernameLogin(true))
+	user := &domain.User{ID: "un", Email: "un@b.com", Username: "joao.silva", Password: "senha123"}
+	assert.NoError(t, us.Create(user))
+
+	// O mesmo usuário autentica pelo username e pelo email
+	_, _, err := us.Authenticate("joao.silva", "senha123")
+	assert.NoError(t, err)
+	_, _, err = us.Authenticate("un@b.com", "senha123")
+	assert.NoError(t, err)
+
+	// Username duplicado é rejeitado
+	err = us.Create(&domain.User{ID: "un2", Email: "outro@b.com", Username: "joao.silva", Password: "senha123"})
+	assert.ErrorIs(t, err, pkgerrors.ErrUsernameAlreadyExists)
+
+	// Sem a opção, o username não é aceito como identificador
+	disabled := NewUserService(repo, jwtService)
+	_, _, err = disabled.Authenticate("joao.silva", "senha123")
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+}
+
 func TestUserService_RefreshTokens(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	user := &domain.User{ID: "4", Email: "d@b.com", Password: "senha", Name: "D"}
+	user := &domain.User{ID: "4", Email: "d@b.com", Password: "senha123", Name: "D"}
 	_ = us.Create(user)
-	_, refresh, _ := us.Authenticate("d@b.com", "senha")
+	_, refresh, _ := us.Authenticate("d@b.com", "senha123")
 	access2, refresh2, err := us.RefreshTokens(refresh)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, access2)
@@ -147,23 +491,156 @@ func TestUserService_RefreshTokens(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestUserService_BlacklistAndClear(t *testing.T) {
-	BlacklistRefreshToken("token1")
-	_, _, err := NewUserService(newMockUserRepo(), auth.NewJWTService("s", 1, "r", 1)).RefreshTokens("token1")
-	assert.Error(t, err)
-	ClearRefreshTokenBlacklist()
-	// Agora não está mais na blacklist
-	// Não retorna erro de blacklist, mas sim de token inválido
-	_, _, err = NewUserService(newMockUserRepo(), auth.NewJWTService("s", 1, "r", 1)).RefreshTokens("token1")
+func TestUserService_RefreshTokens_DistinctErrorCodes(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "codes", Email: "codes@b.com", Password: "senha123"})
+	_, refresh, err := us.Authenticate("codes@b.com", "senha123")
+	require.NoError(t, err)
+	_, _, err = us.RefreshTokens(refresh)
+	require.NoError(t, err)
+
+	codeOf := func(err error) string {
+		var appErr pkgerrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusUnauthorized, appErr.StatusCode())
+		return appErr.ErrorCode
+	}
+
+	// Token já rotacionado (na blacklist)
+	_, _, err = us.RefreshTokens(refresh)
+	assert.Equal(t, "REFRESH_REUSED", codeOf(err))
+
+	// Token revogado no logout
+	_, loggedOut, _ := us.Authenticate("codes@b.com", "senha123")
+	require.NoError(t, us.RevokeRefreshToken(loggedOut))
+	_, _, err = us.RefreshTokens(loggedOut)
+	assert.Equal(t, "REFRESH_REUSED", codeOf(err))
+
+	// Token malformado
+	_, _, err = us.RefreshTokens("lixo")
+	assert.Equal(t, "REFRESH_INVALID", codeOf(err))
+}
+
+func TestUserService_DefaultBlacklistIsPerInstance(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "mem", Email: "mem@b.com", Password: "senha123"})
+
+	_, token, err := us.Authenticate("mem@b.com", "senha123")
+	assert.NoError(t, err)
+	assert.NoError(t, us.RevokeRefreshToken(token))
+	_, _, err = us.RefreshTokens(token)
+	var appErr pkgerrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "REFRESH_REUSED", appErr.ErrorCode)
+
+	// Sem WithTokenBlacklist cada serviço tem a sua blacklist em memória
+	_, _, err = NewUserService(repo, jwtService).RefreshTokens(token)
+	assert.NoError(t, err)
+}
+
+func TestUserService_RefreshTokensRevokedIndependentlyByJTI(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "jti", Email: "jti@b.com", Password: "senha123"})
+
+	_, first, err := us.Authenticate("jti@b.com", "senha123")
+	assert.NoError(t, err)
+	_, second, err := us.Authenticate("jti@b.com", "senha123")
+	assert.NoError(t, err)
+	firstJTI, secondJTI := auth.TokenID(first), auth.TokenID(second)
+	assert.NotEmpty(t, firstJTI)
+	assert.NotEqual(t, firstJTI, secondJTI)
+
+	// Revogar o jti de um token não afeta o outro token do mesmo usuário
+	assert.NoError(t, us.RevokeRefreshToken(first))
+	_, _, err = us.RefreshTokens(first)
 	assert.Error(t, err)
+	_, _, err = us.RefreshTokens(second)
+	assert.NoError(t, err)
+}
+
+func TestUserService_BlacklistConcurrentAccess(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "conc", Email: "conc@b.com", Password: "senha123"})
+
+	refreshTokens := make([]string, 100)
+	for i := range refreshTokens {
+		_, refreshTokens[i], _ = us.Authenticate("conc@b.com", "senha123")
+	}
+
+	// 100 goroutines revogando e renovando ao mesmo tempo não devem causar pânico
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(token string) {
+			defer wg.Done()
+			_ = us.RevokeRefreshToken(token)
+		}(refreshTokens[(i+1)%len(refreshTokens)])
+		go func(token string) {
+			defer wg.Done()
+			_, _, _ = us.RefreshTokens(token)
+		}(refreshTokens[i])
+	}
+	wg.Wait()
+
+	// Ao final, todos os tokens foram rotacionados ou revogados
+	for _, token := range refreshTokens {
+		_, _, err := us.RefreshTokens(token)
+		assert.Error(t, err)
+	}
+}
+
+func TestUserService_RevokeAllTokens(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	store := session.NewMemoryStore()
+	// Relógio parado: emissão, revogação e novo login caem no mesmo segundo
+	now := time.Now()
+	us := NewUserService(repo, jwtService, WithSessionStore(store, 0), WithClock(clock.Func(func() time.Time { return now })))
+	_ = us.Create(&domain.User{ID: "la", Email: "la@b.com", Password: "senha123", Name: "LA"})
+
+	_, first, err := us.Authenticate("la@b.com", "senha123")
+	require.NoError(t, err)
+	_, second, err := us.Authenticate("la@b.com", "senha123")
+	require.NoError(t, err)
+
+	// Após sair de todos os dispositivos, nenhum dos refresh tokens é aceito
+	require.NoError(t, us.RevokeAllTokens("la"))
+	for _, token := range []string{first, second} {
+		_, _, err = us.RefreshTokens(token)
+		assert.ErrorIs(t, err, pkgerrors.ErrRefreshTokenReused)
+	}
+	sessions, _ := store.ListByUser("la")
+	assert.Empty(t, sessions)
+
+	// Tokens emitidos depois da revogação continuam válidos, mesmo no mesmo segundo
+	_, third, err := us.Authenticate("la@b.com", "senha123")
+	require.NoError(t, err)
+	_, fourth, err := us.RefreshTokens(third)
+	assert.NoError(t, err)
+
+	// Até uma nova revogação
+	now = now.Add(time.Millisecond)
+	require.NoError(t, us.RevokeAllTokens("la"))
+	_, _, err = us.RefreshTokens(fourth)
+	assert.ErrorIs(t, err, pkgerrors.ErrRefreshTokenReused)
+
+	assert.ErrorIs(t, us.RevokeAllTokens("inexistente"), pkgerrors.ErrUserNotFound)
 }
 
 func TestUserService_ListAll(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	_ = us.Create(&domain.User{ID: "5", Email: "e@b.com", Password: "senha", Name: "E"})
-	_ = us.Create(&domain.User{ID: "6", Email: "f@b.com", Password: "senha", Name: "F"})
+	_ = us.Create(&domain.User{ID: "5", Email: "e@b.com", Password: "senha123", Name: "E"})
+	_ = us.Create(&domain.User{ID: "6", Email: "f@b.com", Password: "senha123", Name: "F"})
 	users, err := us.ListAll()
 	assert.NoError(t, err)
 	assert.Len(t, users, 2)
@@ -172,7 +649,7 @@ func TestUserService_ListAll(t *testing.T) {
 func TestUserService_Create_RepoError(t *testing.T) {
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(&errorRepo{}, jwtService)
-	user := &domain.User{ID: "x", Email: "x@x.com", Password: "senha"}
+	user := &domain.User{ID: "x", Email: "x@x.com", Password: "senha123"}
 	err := us.Create(user)
 	assert.Error(t, err)
 }
@@ -194,7 +671,7 @@ func TestUserService_GetByEmail_RepoError(t *testing.T) {
 func TestUserService_Update_RepoError(t *testing.T) {
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(&errorRepo{}, jwtService)
-	user := &domain.User{ID: "x", Email: "x@x.com", Password: "senha"}
+	user := &domain.User{ID: "x", Email: "x@x.com", Password: "senha123"}
 	err := us.Update(user)
 	assert.Error(t, err)
 }
@@ -242,15 +719,59 @@ func TestUserService_RefreshTokens_UserNotFound(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestUserService_Update_EmailUniqueness(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	assert.NoError(t, us.Create(&domain.User{ID: "1", Email: "a@b.com", Password: "senha123"}))
+	assert.NoError(t, us.Create(&domain.User{ID: "2", Email: "c@d.com", Password: "senha123"}))
+
+	// Manter o próprio email é permitido
+	assert.NoError(t, us.Update(&domain.User{ID: "1", Email: "a@b.com", Name: "A"}))
+	assert.NoError(t, us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "a@b.com"}))
+
+	// Email de outra conta é recusado com 409
+	err := us.Update(&domain.User{ID: "1", Email: "c@d.com"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+	err = us.UpdateFields("1", map[string]any{domain.UserFieldEmail: "c@d.com"})
+	assert.ErrorIs(t, err, pkgerrors.ErrEmailAlreadyExists)
+	assert.Equal(t, "a@b.com", repo.users["1"].Email)
+}
+
 func TestUserService_Update_UserNotFound(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	user := &domain.User{ID: "naoexiste", Email: "x@x.com", Password: "senha"}
+	user := &domain.User{ID: "naoexiste", Email: "x@x.com", Password: "senha123"}
 	err := us.Update(user)
 	assert.Error(t, err)
 }
 
+func TestUserService_LastAdminGuard(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	admin := []string{domain.RoleUser, domain.RoleAdmin}
+	require.NoError(t, us.Create(&domain.User{ID: "a1", Email: "a1@b.com", Password: "senha123", Roles: admin}))
+	require.NoError(t, us.Create(&domain.User{ID: "a2", Email: "a2@b.com", Password: "senha123", Roles: admin, Status: domain.UserStatusDisabled}))
+
+	// a2 está desativado, então a1 é o último administrador ativo
+	assert.ErrorIs(t, us.Delete("a1"), pkgerrors.ErrLastAdmin)
+	assert.ErrorIs(t, us.UpdateFields("a1", map[string]any{domain.UserFieldRoles: []string{domain.RoleUser}}), pkgerrors.ErrLastAdmin)
+	assert.ErrorIs(t, us.UpdateFields("a1", map[string]any{domain.UserFieldStatus: domain.UserStatusDisabled}), pkgerrors.ErrLastAdmin)
+	demoted := *repo.users["a1"]
+	demoted.Roles = []string{domain.RoleUser}
+	assert.ErrorIs(t, us.Update(&demoted), pkgerrors.ErrLastAdmin)
+	assert.True(t, repo.users["a1"].IsAdmin())
+
+	// Alterações que mantêm o papel seguem permitidas
+	assert.NoError(t, us.UpdateFields("a1", map[string]any{domain.UserFieldName: "Admin"}))
+
+	// Com outro administrador ativo, o primeiro pode ser rebaixado e removido
+	assert.NoError(t, us.UpdateFields("a2", map[string]any{domain.UserFieldStatus: domain.UserStatusActive}))
+	assert.NoError(t, us.UpdateFields("a1", map[string]any{domain.UserFieldRoles: []string{domain.RoleUser}}))
+	assert.NoError(t, us.Delete("a1"))
+	assert.ErrorIs(t, us.Delete("a2"), pkgerrors.ErrLastAdmin)
+}
+
 func TestUserService_Delete_UserNotFound(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
@@ -263,7 +784,7 @@ func TestUserService_Authenticate_UserNotFound(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	_, _, err := us.Authenticate("naoexiste@x.com", "senha")
+	_, _, err := us.Authenticate("naoexiste@x.com", "senha123")
 	assert.Error(t, err)
 }
 
@@ -274,17 +795,6 @@ func TestUserService_GetJWTService(t *testing.T) {
 	assert.Equal(t, jwtService, us.GetJWTService())
 }
 
-func TestUserService_ClearAndBlacklistRefreshToken(t *testing.T) {
-	BlacklistRefreshToken("tokentest")
-	// Está na blacklist
-	_, _, err := NewUserService(newMockUserRepo(), auth.NewJWTService("s", 1, "r", 1)).RefreshTokens("tokentest")
-	assert.Error(t, err)
-	ClearRefreshTokenBlacklist()
-	// Não está mais na blacklist, mas token inválido
-	_, _, err = NewUserService(newMockUserRepo(), auth.NewJWTService("s", 1, "r", 1)).RefreshTokens("tokentest")
-	assert.Error(t, err)
-}
-
 func TestUserService_ListAll_ErrorAndEmpty(t *testing.T) {
 	// Erro
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
@@ -297,15 +807,386 @@ func TestUserService_ListAll_ErrorAndEmpty(t *testing.T) {
 	users, err := us2.ListAll()
 	assert.NoError(t, err)
 	assert.Len(t, users, 0)
+	// O mock retorna nil; o serviço normaliza para um slice vazio
+	assert.NotNil(t, users)
+	body, _ := json.Marshal(users)
+	assert.JSONEq(t, `[]`, string(body))
+}
+
+func TestUserService_ListPaginated(t *testing.T) {
+	repo := newMockUserRepo()
+	us := NewUserService(repo, auth.NewJWTService("secret", 1, "refresh", 1))
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		repo.users[string(rune('a'+i))] = &domain.User{ID: string(rune('a' + i)), CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+	}
+
+	page, total, err := us.ListPaginated(0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []string{"a", "b"}, []string{page[0].ID, page[1].ID})
+
+	page, _, err = us.ListPaginated(4, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, "e", page[0].ID)
+
+	page, total, err = us.ListPaginated(10, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, page)
+	assert.Equal(t, 5, total)
+
+	_, _, err = us.ListPaginated(-1, 2)
+	var appErr pkgerrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode())
+	_, _, err = NewUserService(&errorRepo{}, auth.NewJWTService("secret", 1, "refresh", 1)).ListPaginated(0, 2)
+	assert.ErrorIs(t, err, pkgerrors.ErrInternalServer)
 }
 
 func TestUserService_List(t *testing.T) {
 	repo := newMockUserRepo()
 	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
 	us := NewUserService(repo, jwtService)
-	_ = us.Create(&domain.User{ID: "7", Email: "g@b.com", Password: "senha", Name: "G"})
-	_ = us.Create(&domain.User{ID: "8", Email: "h@b.com", Password: "senha", Name: "H"})
+	_ = us.Create(&domain.User{ID: "7", Email: "g@b.com", Password: "senha123", Name: "G"})
+	_ = us.Create(&domain.User{ID: "8", Email: "h@b.com", Password: "senha123", Name: "H"})
 	users, err := us.List()
 	assert.NoError(t, err, "Erro inesperado ao listar usuários")
 	assert.Len(t, users, 2, "Deveria retornar 2 usuários")
 }
+
+func TestUserService_Create_DefaultsAuditFields(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	// Sem ator informado, a criação é atribuída ao sistema
+	sysUser := &domain.User{ID: "9", Email: "i@b.com", Password: "senha123"}
+	assert.NoError(t, us.Create(sysUser))
+	assert.Equal(t, domain.ActorSystem, sysUser.CreatedBy)
+	assert.Equal(t, domain.ActorSystem, sysUser.UpdatedBy)
+	// Auto-registro preserva o ator informado
+	selfUser := &domain.User{ID: "10", Email: "j@b.com", Password: "senha123", CreatedBy: domain.ActorSelf}
+	assert.NoError(t, us.Create(selfUser))
+	assert.Equal(t, domain.ActorSelf, selfUser.CreatedBy)
+	assert.Equal(t, domain.ActorSelf, selfUser.UpdatedBy)
+}
+
+func TestUserService_ValidateResetRedirect(t *testing.T) {
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(newMockUserRepo(), jwtService,
+		WithResetRedirectAllowlist([]string{"https://app.example.com/reset", "not a uri"}))
+
+	// Permitidos
+	assert.NoError(t, us.ValidateResetRedirect(""))
+	assert.NoError(t, us.ValidateResetRedirect("https://app.example.com/reset"))
+	assert.NoError(t, us.ValidateResetRedirect("https://app.example.com/reset/confirm?x=1"))
+
+	// Rejeitados
+	for _, redirect := range []string{
+		"https://evil.com/reset",
+		"https://app.example.com.evil.com/reset",
+		"http://app.example.com/reset",
+		"https://app.example.com/resetter",
+		"https://user@app.example.com/reset",
+		"//evil.com/reset",
+		"/reset",
+	} {
+		err := us.ValidateResetRedirect(redirect)
+		assert.ErrorIs(t, err, pkgerrors.ErrInvalidRedirect, redirect)
+	}
+
+	// Sem allowlist, qualquer destino é rejeitado
+	assert.Error(t, NewUserService(newMockUserRepo(), jwtService).ValidateResetRedirect("https://app.example.com/reset"))
+}
+
+func TestUserService_MaxSessionsEvictsOldest(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	store := session.NewMemoryStore()
+	us := NewUserService(repo, jwtService, WithSessionStore(store, 2))
+	_ = us.Create(&domain.User{ID: "s1", Email: "s@b.com", Password: "senha123", Name: "S"})
+
+	_, oldest, err := us.Authenticate("s@b.com", "senha123")
+	assert.NoError(t, err)
+	_, second, err := us.Authenticate("s@b.com", "senha123")
+	assert.NoError(t, err)
+	_, third, err := us.Authenticate("s@b.com", "senha123")
+	assert.NoError(t, err)
+
+	sessions, _ := store.ListByUser("s1")
+	assert.Len(t, sessions, 2)
+
+	// A sessão mais antiga foi removida e não pode mais ser renovada
+	_, _, err = us.RefreshTokens(oldest)
+	assert.Error(t, err)
+
+	// As demais continuam válidas, e a rotação não aumenta o número de sessões
+	_, _, err = us.RefreshTokens(second)
+	assert.NoError(t, err)
+	_, _, err = us.RefreshTokens(third)
+	assert.NoError(t, err)
+	sessions, _ = store.ListByUser("s1")
+	assert.Len(t, sessions, 2)
+}
+
+func TestUserService_RevokeRefreshToken_RemovesSession(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	store := session.NewMemoryStore()
+	us := NewUserService(repo, jwtService, WithSessionStore(store, 0))
+	_ = us.Create(&domain.User{ID: "lo", Email: "lo@b.com", Password: "senha123", Name: "LO"})
+	_, kept, err := us.Authenticate("lo@b.com", "senha123")
+	assert.NoError(t, err)
+	_, leaving, err := us.Authenticate("lo@b.com", "senha123")
+	assert.NoError(t, err)
+
+	assert.NoError(t, us.RevokeRefreshToken(leaving))
+
+	sessions, err := us.ListSessions("lo")
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, auth.TokenID(kept), sessions[0].ID)
+	}
+	_, _, err = us.RefreshTokens(leaving)
+	assert.Error(t, err)
+}
+
+func TestUserService_ListSessions_RequiresStore(t *testing.T) {
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1))
+
+	_, err := us.ListSessions("qualquer")
+
+	assert.ErrorIs(t, err, pkgerrors.ErrSessionsUnavailable)
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	store := session.NewMemoryStore()
+	us := NewUserService(repo, jwtService, WithSessionStore(store, 0), WithSessionRevocationOnPasswordChange(true))
+	_ = us.Create(&domain.User{ID: "cp", Email: "cp@b.com", Password: "senha-atual1", Name: "CP"})
+	_, refresh, err := us.Authenticate("cp@b.com", "senha-atual1")
+	assert.NoError(t, err)
+
+	// Senha atual incorreta
+	err = us.ChangePassword("cp", "errada", "senha-nova1")
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+
+	// Nova senha fraca
+	err = us.ChangePassword("cp", "senha-atual1", "ab")
+	assert.ErrorIs(t, err, pkgerrors.ErrPasswordTooWeak)
+
+	// Troca bem-sucedida encerra as sessões existentes
+	assert.NoError(t, us.ChangePassword("cp", "senha-atual1", "senha-nova1"))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(repo.users["cp"].Password), []byte("senha-nova1")))
+	sessions, _ := store.ListByUser("cp")
+	assert.Empty(t, sessions)
+	_, _, err = us.RefreshTokens(refresh)
+	assert.Error(t, err)
+}
+
+func TestUserService_ChangePassword_KeepsSessionsByDefault(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	store := session.NewMemoryStore()
+	us := NewUserService(repo, jwtService, WithSessionStore(store, 0))
+	_ = us.Create(&domain.User{ID: "cp", Email: "cp@b.com", Password: "senha-atual1", Name: "CP"})
+	_, refresh, err := us.Authenticate("cp@b.com", "senha-atual1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, us.ChangePassword("cp", "senha-atual1", "senha-nova1"))
+
+	_, _, err = us.RefreshTokens(refresh)
+	assert.NoError(t, err)
+}
+
+func TestUserService_ChangePassword_MinAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService,
+		WithPasswordMinAge(24*time.Hour),
+		WithClock(clock.Func(func() time.Time { return now })),
+	)
+	_ = us.Create(&domain.User{ID: "ma", Email: "ma@b.com", Password: "senha-inicial1", Name: "MA"})
+
+	// A primeira troca não tem troca anterior para limitar
+	assert.NoError(t, us.ChangePassword("ma", "senha-inicial1", "senha-nova1"))
+	assert.Equal(t, now, repo.users["ma"].PasswordChangedAt)
+
+	// Uma nova troca antes do intervalo mínimo é recusada
+	now = now.Add(time.Hour)
+	err := us.ChangePassword("ma", "senha-nova1", "senha-outra1")
+	assert.ErrorIs(t, err, pkgerrors.ErrPasswordChangeTooSoon)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(repo.users["ma"].Password), []byte("senha-nova1")))
+
+	// Passado o intervalo, a troca é aceita
+	now = now.Add(23 * time.Hour)
+	assert.NoError(t, us.ChangePassword("ma", "senha-nova1", "senha-outra1"))
+}
+
+func TestUserService_ChangePassword_MinAgeSkipsRequiredChange(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService,
+		WithPasswordMinAge(24*time.Hour),
+		WithClock(clock.Func(func() time.Time { return now })),
+	)
+	_ = us.Create(&domain.User{ID: "mr", Email: "mr@b.com", Password: "senha-inicial1", Name: "MR"})
+	assert.NoError(t, us.ChangePassword("mr", "senha-inicial1", "senha-nova1"))
+
+	// A troca exigida pelo admin não espera o intervalo mínimo
+	repo.users["mr"].MustChangePassword = true
+	assert.NoError(t, us.ChangePassword("mr", "senha-nova1", "senha-outra1"))
+}
+
+func TestUserService_UpdateFields_OnlyTouchesGivenFields(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "uf", Email: "old@b.com", Password: "senha123", Name: "Old"})
+	originalHash := repo.users["uf"].Password
+
+	// Uma requisição concorrente altera o email entre a leitura e a escrita do nome
+	loaded, _ := us.GetByID("uf")
+	assert.NoError(t, us.UpdateFields("uf", map[string]any{domain.UserFieldEmail: "new@b.com"}))
+	assert.NoError(t, us.UpdateFields(loaded.ID, map[string]any{domain.UserFieldName: "New"}))
+
+	stored := repo.users["uf"]
+	assert.Equal(t, "New", stored.Name)
+	assert.Equal(t, "new@b.com", stored.Email, "email alterado concorrentemente não deve ser sobrescrito")
+	assert.Equal(t, originalHash, stored.Password)
+}
+
+func TestUserService_UpdateFields_HashesPassword(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "up", Email: "p@b.com", Password: "senha123", Name: "P"})
+
+	fields := map[string]any{domain.UserFieldPassword: "novasenha1"}
+	assert.NoError(t, us.UpdateFields("up", fields))
+	assert.Equal(t, "novasenha1", fields[domain.UserFieldPassword], "o mapa do chamador não deve ser alterado")
+
+	_, _, err := us.Authenticate("p@b.com", "novasenha1")
+	assert.NoError(t, err)
+}
+
+func TestUserService_UpdateFields_UserNotFound(t *testing.T) {
+	us := NewUserService(newMockUserRepo(), auth.NewJWTService("secret", 1, "refresh", 1))
+	err := us.UpdateFields("naoexiste", map[string]any{domain.UserFieldName: "X"})
+	assert.ErrorIs(t, err, pkgerrors.ErrUserNotFound)
+}
+
+func TestUserService_Update_VersionConflict(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "v1", Email: "v@b.com", Password: "senha123", Name: "V"})
+
+	// Duas requisições carregam a mesma versão do usuário
+	first := *repo.users["v1"]
+	second := *repo.users["v1"]
+
+	first.Name = "Primeira"
+	assert.NoError(t, us.Update(&first))
+
+	second.Name = "Segunda"
+	err := us.Update(&second)
+	assert.ErrorIs(t, err, pkgerrors.ErrUserVersionConflict)
+	assert.Equal(t, 409, pkgerrors.GetStatusCode(err))
+	assert.Equal(t, "Primeira", repo.users["v1"].Name)
+}
+
+func TestUserService_MustChangePassword(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	us := NewUserService(repo, jwtService)
+	_ = us.Create(&domain.User{ID: "mc", Email: "mc@b.com", Password: "temporaria1", Name: "MC", MustChangePassword: true})
+
+	// Usuário marcado recebe apenas o token restrito, sem refresh token
+	access, refresh, err := us.Authenticate("mc@b.com", "temporaria1")
+	assert.NoError(t, err)
+	assert.Empty(t, refresh)
+	claims, err := jwtService.ValidateToken(access)
+	assert.NoError(t, err)
+	assert.True(t, claims.PasswordChangeRequired)
+
+	// Senha atual incorreta é recusada e mantém a exigência
+	err = us.ChangePassword("mc", "errada", "definitiva1")
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidCredentials)
+	assert.True(t, repo.users["mc"].MustChangePassword)
+
+	// Após a troca a exigência é removida e o login volta a emitir tokens completos
+	assert.NoError(t, us.ChangePassword("mc", "temporaria1", "definitiva1"))
+	assert.False(t, repo.users["mc"].MustChangePassword)
+	access, refresh, err = us.Authenticate("mc@b.com", "definitiva1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refresh)
+	assert.False(t, auth.IsPasswordChangeToken(access))
+}
+
+// memoryBlacklist simula uma blacklist compartilhada entre instâncias,
+// guardando o TTL de cada jti
+type memoryBlacklist map[string]time.Duration
+
+func (m memoryBlacklist) Add(jti string, ttl time.Duration) error {
+	m[jti] = ttl
+	return nil
+}
+
+func (m memoryBlacklist) Contains(jti string) (bool, error) {
+	_, ok := m[jti]
+	return ok, nil
+}
+
+func TestUserService_TokenBlacklist_RevokesByJTI(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	blacklist := memoryBlacklist{}
+	now := time.Now()
+	us := NewUserService(repo, jwtService, WithTokenBlacklist(blacklist), WithClock(clock.Func(func() time.Time { return now })))
+	_ = us.Create(&domain.User{ID: "bl", Email: "bl@b.com", Password: "senha123", Name: "BL"})
+
+	_, first, err := us.Authenticate("bl@b.com", "senha123")
+	assert.NoError(t, err)
+	_, second, err := us.Authenticate("bl@b.com", "senha123")
+	assert.NoError(t, err)
+
+	// Rotação e logout registram o jti pelo tempo restante até a expiração do token
+	_, _, err = us.RefreshTokens(first)
+	assert.NoError(t, err)
+	assert.NoError(t, us.RevokeRefreshToken(second))
+	firstClaims, _ := jwtService.ValidateRefreshToken(first)
+	assert.Len(t, blacklist, 2)
+	assert.Equal(t, firstClaims.ExpiresAt.Sub(now), blacklist[firstClaims.ID])
+
+	// Outra instância com a mesma blacklist (ou a mesma após reiniciar) continua recusando
+	restarted := NewUserService(repo, jwtService, WithTokenBlacklist(blacklist))
+	for _, token := range []string{first, second} {
+		_, _, err = restarted.RefreshTokens(token)
+		assert.Error(t, err)
+	}
+}
+
+func TestUserService_BlacklistByJTI(t *testing.T) {
+	repo := newMockUserRepo()
+	jwtService := auth.NewJWTService("secret", 1, "refresh", 1)
+	blacklist := memoryBlacklist{}
+	us := NewUserService(repo, jwtService, WithTokenBlacklist(blacklist))
+	_ = us.Create(&domain.User{ID: "jti", Email: "jti@b.com", Password: "senha123", Name: "JTI"})
+
+	_, first, err := us.Authenticate("jti@b.com", "senha123")
+	assert.NoError(t, err)
+	_, second, err := us.Authenticate("jti@b.com", "senha123")
+	assert.NoError(t, err)
+	assert.NotEqual(t, auth.TokenID(first), auth.TokenID(second))
+
+	// Revogar um jti não afeta o outro token do mesmo usuário
+	assert.NoError(t, blacklist.Add(auth.TokenID(first), time.Hour))
+	_, _, err = us.RefreshTokens(first)
+	assert.Error(t, err)
+	_, _, err = us.RefreshTokens(second)
+	assert.NoError(t, err)
+}
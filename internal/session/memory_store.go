@@ -0,0 +1,85 @@
+package session
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+)
+
+// MemoryStore é uma implementação em memória de domain.SessionStore.
+// Adequada para uma única instância da aplicação; sessões se perdem ao reiniciar.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*domain.Session
+	order    map[string]uint64 // ordem de inserção, desempata sessões criadas no mesmo instante
+	seq      uint64
+}
+
+// Garantir que MemoryStore implementa domain.SessionStore
+var _ domain.SessionStore = (*MemoryStore)(nil)
+
+// NewMemoryStore cria um novo armazenamento de sessões em memória
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]*domain.Session),
+		order:    make(map[string]uint64),
+	}
+}
+
+// Create registra uma nova sessão
+func (s *MemoryStore) Create(session *domain.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *session
+	s.sessions[session.ID] = &copied
+	s.seq++
+	s.order[session.ID] = s.seq
+	return nil
+}
+
+// GetByID busca uma sessão pelo ID, retornando nil se não existir
+func (s *MemoryStore) GetByID(id string) (*domain.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	return &copied, nil
+}
+
+// Delete remove uma sessão; remover uma sessão inexistente não é erro
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	delete(s.order, id)
+	return nil
+}
+
+// ListByUser lista as sessões de um usuário, da mais antiga para a mais recente
+func (s *MemoryStore) ListByUser(userID string) ([]*domain.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sessions []*domain.Session
+	for _, session := range s.sessions {
+		if session.UserID == userID {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+		}
+		return s.order[sessions[i].ID] < s.order[sessions[j].ID]
+	})
+	return sessions, nil
+}
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_CRUD(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+
+	assert.NoError(t, store.Create(&domain.Session{ID: "s2", UserID: "u1", CreatedAt: now.Add(time.Second)}))
+	assert.NoError(t, store.Create(&domain.Session{ID: "s1", UserID: "u1", CreatedAt: now}))
+	assert.NoError(t, store.Create(&domain.Session{ID: "s3", UserID: "u2", CreatedAt: now}))
+
+	got, err := store.GetByID("s1")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", got.UserID)
+
+	sessions, err := store.ListByUser("u1")
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, "s1", sessions[0].ID, "sessões devem vir da mais antiga para a mais recente")
+		assert.Equal(t, "s2", sessions[1].ID)
+	}
+
+	assert.NoError(t, store.Delete("s1"))
+	got, err = store.GetByID("s1")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Remover novamente não é erro
+	assert.NoError(t, store.Delete("s1"))
+}
@@ -0,0 +1,66 @@
+// Package cache fornece caches em memória simples, seguros para uso concorrente
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTL é um cache em que cada valor expira após um tempo fixo desde a gravação.
+// Entradas expiradas são descartadas na leitura.
+type TTL[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	clock clock.Clock
+	items map[K]entry[V]
+}
+
+// NewTTL cria um cache com a validade informada. ttl <= 0 desabilita o cache:
+// nenhuma gravação é mantida.
+func NewTTL[K comparable, V any](ttl time.Duration, c clock.Clock) *TTL[K, V] {
+	if c == nil {
+		c = clock.System()
+	}
+	return &TTL[K, V]{ttl: ttl, clock: c, items: make(map[K]entry[V])}
+}
+
+// Get retorna o valor da chave se ele ainda estiver dentro da validade
+func (t *TTL[K, V]) Get(key K) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !t.clock.Now().Before(e.expiresAt) {
+		delete(t.items, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set grava o valor da chave, renovando a sua validade
+func (t *TTL[K, V]) Set(key K, value V) {
+	if t.ttl <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.items[key] = entry[V]{value: value, expiresAt: t.clock.Now().Add(t.ttl)}
+}
+
+// Delete remove a chave do cache
+func (t *TTL[K, V]) Delete(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.items, key)
+}
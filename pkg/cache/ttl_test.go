@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTL_ExpiresEntries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTTL[string, int](time.Minute, clock.Func(func() time.Time { return now }))
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(59 * time.Second)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestTTL_Delete(t *testing.T) {
+	c := NewTTL[string, bool](time.Minute, nil)
+	c.Set("a", true)
+	c.Delete("a")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestTTL_DisabledWithZeroTTL(t *testing.T) {
+	c := NewTTL[string, bool](0, nil)
+	c.Set("a", true)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,24 @@
+package clock
+
+import "time"
+
+// Clock abstrai a leitura do horário atual, permitindo relógios controlados em testes
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock usa o relógio do sistema operacional
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// System retorna o relógio do sistema
+func System() Clock {
+	return systemClock{}
+}
+
+// Func adapta uma função ao Clock
+type Func func() time.Time
+
+// Now retorna o horário produzido pela função
+func (f Func) Now() time.Time { return f() }
@@ -0,0 +1,24 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := System().Now()
+
+	if now.Before(before) || now.After(time.Now()) {
+		t.Errorf("System deveria retornar o horário atual, mas retornou %v", now)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := Func(func() time.Time { return fixed })
+
+	if !c.Now().Equal(fixed) {
+		t.Errorf("Func deveria retornar %v, mas retornou %v", fixed, c.Now())
+	}
+}
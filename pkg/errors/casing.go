@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyCasingHeader permite ao cliente escolher o formato das chaves JSON da
+// resposta: "camel" (createdAt) ou "snake" (created_at)
+const KeyCasingHeader = "X-JSON-Key-Casing"
+
+// camelCaseKeys habilita chaves camelCase por padrão em todas as respostas
+var camelCaseKeys atomic.Bool
+
+// SetCamelCaseKeys define se as respostas usam chaves camelCase por padrão
+func SetCamelCaseKeys(enabled bool) {
+	camelCaseKeys.Store(enabled)
+}
+
+// CamelCaseKeysEnabled indica se as respostas usam chaves camelCase por padrão
+func CamelCaseKeysEnabled() bool {
+	return camelCaseKeys.Load()
+}
+
+// wantsCamelCase aplica a preferência do cabeçalho, se houver, sobre a configuração global
+func wantsCamelCase(c *gin.Context) bool {
+	switch strings.ToLower(c.GetHeader(KeyCasingHeader)) {
+	case "camel":
+		return true
+	case "snake":
+		return false
+	default:
+		return CamelCaseKeysEnabled()
+	}
+}
+
+// CamelCaseKeys reescreve recursivamente as chaves snake_case do payload
+// serializado em camelCase, preservando valores e a ordem dos arrays
+func CamelCaseKeys(payload interface{}) (interface{}, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return camelizeValue(generic), nil
+}
+
+func camelizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			out[snakeToCamel(k)] = camelizeValue(item)
+		}
+		return out
+	case []interface{}:
+		for i, item := range value {
+			value[i] = camelizeValue(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// snakeToCamel converte created_at em createdAt; chaves sem "_" não mudam
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, p := range parts[1:] {
+		if p == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type casingPayload struct {
+	ID        string            `json:"id"`
+	CreatedAt string            `json:"created_at"`
+	Nested    map[string]string `json:"nested_items"`
+	Count     int64             `json:"total_count"`
+}
+
+func respondWithCasing(t *testing.T, header string) map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	if header != "" {
+		c.Request.Header.Set(KeyCasingHeader, header)
+	}
+
+	GinRespondWithJSON(c, http.StatusOK, casingPayload{
+		ID:        "1",
+		CreatedAt: "2024-01-01",
+		Nested:    map[string]string{"updated_by": "self"},
+		Count:     9007199254740993,
+	})
+
+	var body map[string]interface{}
+	decoder := json.NewDecoder(w.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		t.Fatalf("Resposta inválida: %v", err)
+	}
+	return body
+}
+
+func TestGinRespondWithJSON_SnakeCaseByDefault(t *testing.T) {
+	body := respondWithCasing(t, "")
+
+	if _, ok := body["created_at"]; !ok {
+		t.Errorf("Chave created_at deveria ser mantida, mas foi: %v", body)
+	}
+}
+
+func TestGinRespondWithJSON_CamelCaseHeader(t *testing.T) {
+	body := respondWithCasing(t, "camel")
+
+	for _, key := range []string{"id", "createdAt", "nestedItems", "totalCount"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("Chave %s deveria existir, mas a resposta foi: %v", key, body)
+		}
+	}
+	if nested, _ := body["nestedItems"].(map[string]interface{}); nested["updatedBy"] != "self" {
+		t.Errorf("Chaves aninhadas deveriam ser convertidas, mas foi: %v", body["nestedItems"])
+	}
+	if body["totalCount"] != json.Number("9007199254740993") {
+		t.Errorf("Números deveriam ser preservados, mas foi: %v", body["totalCount"])
+	}
+}
+
+func TestGinRespondWithJSON_GlobalCamelCase(t *testing.T) {
+	SetCamelCaseKeys(true)
+	defer SetCamelCaseKeys(false)
+
+	if _, ok := respondWithCasing(t, "")["createdAt"]; !ok {
+		t.Error("Com o modo global habilitado a chave deveria ser createdAt")
+	}
+	if _, ok := respondWithCasing(t, "snake")["created_at"]; !ok {
+		t.Error("O cabeçalho snake deveria prevalecer sobre o modo global")
+	}
+}
+
+func TestSnakeToCamel(t *testing.T) {
+	cases := map[string]string{
+		"created_at":           "createdAt",
+		"id":                   "id",
+		"must_change_password": "mustChangePassword",
+		"refresh_expires_in":   "refreshExpiresIn",
+		"already_camel":        "alreadyCamel",
+		"trailing_":            "trailing",
+	}
+	for in, want := range cases {
+		if got := snakeToCamel(in); got != want {
+			t.Errorf("snakeToCamel(%q) = %q, esperado %q", in, got, want)
+		}
+	}
+}
@@ -21,6 +21,22 @@ var (
 		Message: "Não autorizado",
 	}
 
+	// ErrRefreshTokenReused indica um refresh token válido, mas já rotacionado,
+	// revogado no logout ou de sessão encerrada: o cliente deve refazer o login
+	ErrRefreshTokenReused = AppError{
+		Code:      http.StatusUnauthorized,
+		Message:   "Refresh token já utilizado ou revogado",
+		ErrorCode: "REFRESH_REUSED",
+	}
+
+	// ErrRefreshTokenInvalid indica um refresh token malformado, expirado ou com
+	// assinatura inválida
+	ErrRefreshTokenInvalid = AppError{
+		Code:      http.StatusUnauthorized,
+		Message:   "Refresh token inválido ou expirado",
+		ErrorCode: "REFRESH_INVALID",
+	}
+
 	// ErrForbidden representa um erro de permissão
 	ErrForbidden = AppError{
 		Code:    http.StatusForbidden,
@@ -33,6 +49,12 @@ var (
 		Message: "Recurso não encontrado",
 	}
 
+	// ErrRouteNotFound indica um caminho sem rota registrada, como /users/ sem o ID
+	ErrRouteNotFound = AppError{
+		Code:    http.StatusNotFound,
+		Message: "Rota não encontrada; verifique barras finais e parâmetros obrigatórios do caminho",
+	}
+
 	// ErrConflict representa um erro de conflito
 	ErrConflict = AppError{
 		Code:    http.StatusConflict,
@@ -45,6 +67,45 @@ var (
 		Message: "Erro de validação",
 	}
 
+	// ErrServiceUnavailable representa um servidor temporariamente sobrecarregado
+	ErrTooManyRequests = AppError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Muitas requisições simultâneas, tente novamente em instantes",
+	}
+
+	// ErrRateLimited indica que o cliente excedeu o limite de requisições por IP
+	ErrRateLimited = AppError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Muitas requisições deste endereço, tente novamente mais tarde",
+	}
+
+	ErrServiceUnavailable = AppError{
+		Code:    http.StatusServiceUnavailable,
+		Message: "Serviço temporariamente indisponível, tente novamente",
+	}
+
+	// ErrMaintenance indica que a aplicação está em modo de manutenção
+	ErrMaintenance = AppError{
+		Code:    http.StatusServiceUnavailable,
+		Message: "Serviço em manutenção, tente novamente em alguns minutos",
+	}
+
+	// ErrClientClosedRequest indica uma requisição abandonada pelo cliente
+	// (context.Canceled) antes da resposta
+	ErrClientClosedRequest = AppError{
+		Code:      StatusClientClosedRequest,
+		Message:   "Requisição cancelada pelo cliente",
+		ErrorCode: "CLIENT_CLOSED_REQUEST",
+	}
+
+	// ErrRequestTimeout indica que o prazo da requisição (context.DeadlineExceeded)
+	// terminou antes da resposta
+	ErrRequestTimeout = AppError{
+		Code:      http.StatusGatewayTimeout,
+		Message:   "Tempo limite da requisição esgotado, tente novamente",
+		ErrorCode: "REQUEST_TIMEOUT",
+	}
+
 	// Erros específicos de usuário
 	ErrUserNotFound = AppError{
 		Code:    http.StatusNotFound,
@@ -56,11 +117,61 @@ var (
 		Message: "Email já está em uso",
 	}
 
+	ErrUsernameAlreadyExists = AppError{
+		Code:    http.StatusConflict,
+		Message: "Username já está em uso",
+	}
+
+	ErrUserVersionConflict = AppError{
+		Code:    http.StatusConflict,
+		Message: "O usuário foi alterado por outra requisição, recarregue e tente novamente",
+	}
+
+	ErrLastAdmin = AppError{
+		Code:    http.StatusConflict,
+		Message: "Não é possível remover, rebaixar ou desativar o último administrador ativo",
+	}
+
+	ErrAccountInactive = AppError{
+		Code:    http.StatusForbidden,
+		Message: "Conta desativada ou removida",
+	}
+
+	ErrNonceRequired = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Nonce obrigatório no cabeçalho X-Nonce",
+	}
+
+	ErrNonceInvalid = AppError{
+		Code:    http.StatusConflict,
+		Message: "Nonce expirado ou já utilizado, solicite um novo",
+	}
+
 	ErrInvalidCredentials = AppError{
 		Code:    http.StatusUnauthorized,
 		Message: "Credenciais inválidas",
 	}
 
+	ErrAccountLocked = AppError{
+		Code:    http.StatusTooManyRequests,
+		Message: "Conta temporariamente bloqueada por excesso de tentativas de login",
+	}
+
+	ErrSessionsUnavailable = AppError{
+		Code:    http.StatusServiceUnavailable,
+		Message: "Gerenciamento de sessões não está habilitado",
+	}
+
+	ErrSignatureRequired = AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "Assinatura obrigatória no cabeçalho X-Signature",
+	}
+
+	ErrInvalidSignature = AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "Assinatura da requisição inválida",
+	}
+
 	ErrInvalidToken = AppError{
 		Code:    http.StatusUnauthorized,
 		Message: "Token inválido ou expirado",
@@ -71,10 +182,50 @@ var (
 		Message: "Token de autenticação não fornecido",
 	}
 
+	ErrPasswordChangeRequired = AppError{
+		Code:    http.StatusForbidden,
+		Message: "Troca de senha obrigatória antes de continuar",
+	}
+
 	ErrPasswordTooWeak = AppError{
 		Code:    http.StatusBadRequest,
 		Message: "A senha não atende aos requisitos mínimos de segurança",
 	}
 
+	ErrPasswordChangeTooSoon = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "A senha foi trocada recentemente, aguarde antes de trocá-la novamente",
+	}
+
+	ErrPasswordBreached = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Esta senha aparece em vazamentos de dados conhecidos, escolha outra",
+	}
+
+	ErrInvalidRedirect = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Destino de redirecionamento não permitido",
+	}
+
+	ErrInvalidResetToken = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Token de redefinição de senha inválido ou expirado",
+	}
+
+	ErrInvalidVerificationToken = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Token de verificação de email inválido, expirado ou já utilizado",
+	}
+
+	ErrEmailNotVerified = AppError{
+		Code:    http.StatusForbidden,
+		Message: "Confirme o seu email antes de fazer login",
+	}
+
+	ErrInvalidDeletionToken = AppError{
+		Code:    http.StatusBadRequest,
+		Message: "Token de confirmação de exclusão inválido ou expirado",
+	}
+
 	// Outros erros específicos da aplicação podem ser adicionados aqui
 )
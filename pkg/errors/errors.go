@@ -1,17 +1,25 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
+// StatusClientClosedRequest é o status não padronizado (convenção do nginx) para
+// requisições encerradas pelo cliente antes da resposta
+const StatusClientClosedRequest = 499
+
 // AppError é o tipo de erro personalizado da aplicação.
 type AppError struct {
 	// Code é o código de status HTTP
 	Code int `json:"-"`
 	// Message é a mensagem amigável para o cliente
 	Message string `json:"message"`
+	// ErrorCode é um código estável, legível por máquina, para clientes que
+	// precisam distinguir erros com o mesmo status HTTP; opcional
+	ErrorCode string `json:"code,omitempty"`
 	// Internal é o erro original para logging/debugging
 	Internal error `json:"-"`
 }
@@ -37,18 +45,20 @@ func (e AppError) StatusCode() int {
 // WithError cria uma cópia do erro com um erro interno adicionado
 func (e AppError) WithError(err error) AppError {
 	return AppError{
-		Code:     e.Code,
-		Message:  e.Message,
-		Internal: err,
+		Code:      e.Code,
+		Message:   e.Message,
+		ErrorCode: e.ErrorCode,
+		Internal:  err,
 	}
 }
 
 // WithMessage cria uma cópia do erro com uma mensagem personalizada
 func (e AppError) WithMessage(message string) AppError {
 	return AppError{
-		Code:     e.Code,
-		Message:  message,
-		Internal: e.Internal,
+		Code:      e.Code,
+		Message:   message,
+		ErrorCode: e.ErrorCode,
+		Internal:  e.Internal,
 	}
 }
 
@@ -88,21 +98,36 @@ func Wrap(err error, code int, message string) AppError {
 	return NewAppError(code, message, err)
 }
 
+// Classify converte um erro qualquer no AppError usado na resposta. AppErrors
+// são mantidos, exceto os 500 causados por contexto cancelado (499) ou prazo
+// esgotado (504), como os repassados pelos serviços com ErrInternalServer; os
+// demais erros viram ErrInternalServer.
+func Classify(err error) AppError {
+	var appErr AppError
+	isAppErr := As(err, &appErr)
+	if isAppErr && appErr.Code != http.StatusInternalServerError {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// O cliente já desistiu: não há o que investigar no log de erros
+		return ErrClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrRequestTimeout.WithError(err)
+	case isAppErr:
+		return appErr
+	}
+	return ErrInternalServer.WithError(err)
+}
+
 // GetStatusCode obtém o código de status HTTP de um erro
 // Se o erro não for um AppError, retorna Internal Server Error
 func GetStatusCode(err error) int {
-	var appErr AppError
-	if As(err, &appErr) {
-		return appErr.Code
-	}
-	return http.StatusInternalServerError
+	return Classify(err).Code
 }
 
 // GetMessage obtém a mensagem amigável de um erro
 func GetMessage(err error) string {
-	var appErr AppError
-	if As(err, &appErr) {
-		return appErr.Message
-	}
-	return "Erro interno do servidor"
+	return Classify(err).Message
 }
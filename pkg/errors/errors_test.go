@@ -1,7 +1,9 @@
 package errors
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 )
@@ -155,3 +157,45 @@ func TestGetMessage(t *testing.T) {
 			GetMessage(stdErr))
 	}
 }
+
+func TestClassify_ContextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantTag  string
+	}{
+		{"cancelado", context.Canceled, StatusClientClosedRequest, "CLIENT_CLOSED_REQUEST"},
+		{"cancelado encapsulado", fmt.Errorf("consulta: %w", context.Canceled), StatusClientClosedRequest, "CLIENT_CLOSED_REQUEST"},
+		{"cancelado via serviço", ErrInternalServer.WithError(context.Canceled), StatusClientClosedRequest, "CLIENT_CLOSED_REQUEST"},
+		{"prazo esgotado", context.DeadlineExceeded, http.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+		{"prazo esgotado via serviço", ErrInternalServer.WithError(context.DeadlineExceeded), http.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := Classify(tt.err)
+			if appErr.Code != tt.wantCode {
+				t.Errorf("Status esperado %d, obteve %d", tt.wantCode, appErr.Code)
+			}
+			if appErr.ErrorCode != tt.wantTag {
+				t.Errorf("Código esperado %s, obteve '%s'", tt.wantTag, appErr.ErrorCode)
+			}
+		})
+	}
+
+	// Cancelamento pelo cliente não carrega erro interno, para não poluir o log de erros
+	if Classify(context.Canceled).Internal != nil {
+		t.Error("Cancelamento não deveria preservar o erro interno")
+	}
+}
+
+func TestClassify_KeepsOtherErrors(t *testing.T) {
+	// AppErrors que não são 500 prevalecem mesmo envolvendo erros de contexto
+	if got := Classify(ErrNotFound.WithError(context.Canceled)); got.Code != http.StatusNotFound {
+		t.Errorf("Esperava 404, obteve %d", got.Code)
+	}
+	if got := Classify(errors.New("falha")); got.Code != http.StatusInternalServerError {
+		t.Errorf("Erro genérico deveria virar 500, obteve %d", got.Code)
+	}
+}
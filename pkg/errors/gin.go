@@ -9,19 +9,30 @@ import (
 
 // GinHandleError processa o erro e responde adequadamente em contexto Gin
 func GinHandleError(c *gin.Context, err error) {
-	var appErr AppError
-	if !As(err, &appErr) {
-		// Se não for um AppError, envolve com ErrInternalServer
-		appErr = ErrInternalServer.WithError(err)
-	}
+	appErr := Classify(err)
 
 	// Loga o erro com o erro interno, se existir
 	if appErr.Internal != nil {
 		logging.Error("Erro na requisição: %v", appErr)
 	}
 
+	// No modo RFC 7807 todos os erros seguem o contrato problem+json
+	if ProblemJSONEnabled() {
+		GinRespondWithProblem(c, appErr)
+		return
+	}
+
+	// Erros de validação incluem os detalhes por campo
+	if _, ok := GetValidationDetails(appErr); ok {
+		GinRespondWithJSON(c, appErr.Code, GinValidationResponse(appErr))
+		return
+	}
+
 	// Responde com o erro apropriado
-	GinRespondWithError(c, appErr.Code, appErr.Message)
+	GinRespondWithJSON(c, appErr.Code, ErrorResponse{
+		Message: appErr.Message,
+		Code:    appErr.ErrorCode,
+	})
 }
 
 // GinRespondWithError responde com um erro em formato JSON
@@ -39,8 +50,17 @@ func GinRespondWithValidationError(c *gin.Context, message string, details map[s
 	})
 }
 
-// GinRespondWithJSON responde com um objeto em formato JSON
+// GinRespondWithJSON responde com um objeto em formato JSON, com chaves em
+// camelCase quando configurado ou solicitado pelo cabeçalho KeyCasingHeader
 func GinRespondWithJSON(c *gin.Context, code int, payload interface{}) {
+	if wantsCamelCase(c) {
+		shaped, err := CamelCaseKeys(payload)
+		if err == nil {
+			c.JSON(code, shaped)
+			return
+		}
+		logging.Warning("Falha ao converter chaves da resposta para camelCase: %v", err)
+	}
 	c.JSON(code, payload)
 }
 
@@ -1,10 +1,13 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -213,3 +216,96 @@ func assertStatus(t *testing.T, got, want int) {
 		t.Errorf("Status esperado %d, mas foi %d", want, got)
 	}
 }
+
+func TestGinHandleError_ValidationDetails(t *testing.T) {
+	router := setupGinTest()
+	router.GET("/test/validation-error", func(c *gin.Context) {
+		details := []ValidationDetail{{Field: "password", Message: "Senha é obrigatória"}}
+		GinHandleError(c, NewValidationError("Campos obrigatórios não preenchidos", details))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test/validation-error", nil)
+	router.ServeHTTP(w, req)
+
+	assertStatus(t, w.Code, http.StatusBadRequest)
+
+	var response struct {
+		Message string `json:"message"`
+		Details struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta JSON: %v", err)
+	}
+	if response.Message != "Campos obrigatórios não preenchidos" {
+		t.Errorf("Mensagem inesperada: '%s'", response.Message)
+	}
+	if response.Details.Fields["password"] != "Senha é obrigatória" {
+		t.Errorf("Esperava detalhe para o campo 'password', obteve %v", response.Details.Fields)
+	}
+}
+
+func TestGinHandleError_ErrorCode(t *testing.T) {
+	router := setupGinTest()
+	router.GET("/test/reused", func(c *gin.Context) {
+		GinHandleError(c, ErrRefreshTokenReused.WithError(errors.New("jti revogado")))
+	})
+	router.GET("/test/plain", func(c *gin.Context) {
+		GinHandleError(c, ErrUnauthorized)
+	})
+
+	var response ErrorResponse
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test/reused", nil)
+	router.ServeHTTP(w, req)
+	assertStatus(t, w.Code, http.StatusUnauthorized)
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta JSON: %v", err)
+	}
+	if response.Code != "REFRESH_REUSED" {
+		t.Errorf("Código esperado REFRESH_REUSED, obteve '%s'", response.Code)
+	}
+
+	// Erros sem código omitem o campo
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/test/plain", nil)
+	router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), `"code"`) {
+		t.Errorf("Erro sem código não deveria incluir o campo: %s", w.Body.String())
+	}
+}
+
+func TestGinHandleError_ContextErrors(t *testing.T) {
+	router := setupGinTest()
+	router.GET("/test/canceled", func(c *gin.Context) {
+		GinHandleError(c, ErrInternalServer.WithError(context.Canceled))
+	})
+	router.GET("/test/timeout", func(c *gin.Context) {
+		GinHandleError(c, fmt.Errorf("consulta: %w", context.DeadlineExceeded))
+	})
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantTag  string
+	}{
+		{"/test/canceled", StatusClientClosedRequest, "CLIENT_CLOSED_REQUEST"},
+		{"/test/timeout", http.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assertStatus(t, w.Code, tt.wantCode)
+
+		var response ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Resposta de %s deveria ser JSON válido: %v", tt.path, err)
+		}
+		if response.Code != tt.wantTag {
+			t.Errorf("Código esperado %s, obteve '%s'", tt.wantTag, response.Code)
+		}
+	}
+}
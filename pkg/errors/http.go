@@ -10,16 +10,13 @@ import (
 // ErrorResponse é a estrutura da resposta de erro
 type ErrorResponse struct {
 	Message string                 `json:"message"`
+	Code    string                 `json:"code,omitempty"`
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
 // HandleError processa o erro e responde adequadamente
 func HandleError(w http.ResponseWriter, err error) {
-	var appErr AppError
-	if !As(err, &appErr) {
-		// Se não for um AppError, envolve com ErrInternalServer
-		appErr = ErrInternalServer.WithError(err)
-	}
+	appErr := Classify(err)
 
 	// Loga o erro com o erro interno, se existir
 	if appErr.Internal != nil {
@@ -27,7 +24,25 @@ func HandleError(w http.ResponseWriter, err error) {
 	}
 
 	// Responde com o erro apropriado
-	RespondWithError(w, appErr.Code, appErr.Message)
+	RespondWithJSON(w, appErr.Code, ErrorResponse{
+		Message: appErr.Message,
+		Code:    appErr.ErrorCode,
+	})
+}
+
+// HandleErrorWithRequest processa o erro como HandleError, respondendo em
+// problem+json quando esse modo estiver habilitado
+func HandleErrorWithRequest(w http.ResponseWriter, r *http.Request, err error) {
+	if !ProblemJSONEnabled() {
+		HandleError(w, err)
+		return
+	}
+
+	appErr := Classify(err)
+	if appErr.Internal != nil {
+		logging.Error("Erro na requisição: %v", appErr)
+	}
+	RespondWithProblem(w, r, appErr)
 }
 
 // RespondWithError responde com um erro em formato JSON
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/pkg/logging"
+)
+
+// ProblemContentType é o tipo de mídia de documentos RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// problemJSON habilita respostas de erro no formato RFC 7807
+var problemJSON atomic.Bool
+
+// SetProblemJSON habilita ou desabilita as respostas de erro no formato problem+json
+func SetProblemJSON(enabled bool) {
+	problemJSON.Store(enabled)
+}
+
+// ProblemJSONEnabled indica se as respostas de erro usam o formato problem+json
+func ProblemJSONEnabled() bool {
+	return problemJSON.Load()
+}
+
+// ProblemDetails representa um documento de erro RFC 7807.
+// Erros de validação preenchem Errors com os detalhes por campo.
+type ProblemDetails struct {
+	Type     string             `json:"type"`
+	Title    string             `json:"title"`
+	Status   int                `json:"status"`
+	Detail   string             `json:"detail,omitempty"`
+	Instance string             `json:"instance,omitempty"`
+	Code     string             `json:"code,omitempty"` // membro de extensão com AppError.ErrorCode
+	Errors   []ValidationDetail `json:"errors,omitempty"`
+}
+
+// NewProblemDetails converte um erro em um documento problem+json
+func NewProblemDetails(err error, instance string) ProblemDetails {
+	appErr := Classify(err)
+
+	title := http.StatusText(appErr.Code)
+	if appErr.Code == StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+
+	problem := ProblemDetails{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   appErr.Code,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.ErrorCode,
+	}
+
+	if details, ok := GetValidationDetails(appErr); ok {
+		problem.Errors = details
+	}
+
+	return problem
+}
+
+// RespondWithProblem responde com um documento problem+json
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblemDetails(err, r.URL.Path)
+	response, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		logging.Error("Erro ao serializar resposta problem+json: %v", marshalErr)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if _, writeErr := w.Write(response); writeErr != nil {
+		logging.Error("Erro ao escrever resposta: %v", writeErr)
+	}
+}
+
+// GinRespondWithProblem responde com um documento problem+json em contexto Gin
+func GinRespondWithProblem(c *gin.Context, err error) {
+	problem := NewProblemDetails(err, c.Request.URL.Path)
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewProblemDetails_Validation(t *testing.T) {
+	details := []ValidationDetail{
+		{Field: "email", Message: "Email inválido"},
+		{Field: "password", Message: "Senha muito curta"},
+	}
+	problem := NewProblemDetails(NewValidationError("Erro de validação", details), "/users/register")
+
+	if problem.Status != http.StatusBadRequest {
+		t.Errorf("Status esperado %d, obteve %d", http.StatusBadRequest, problem.Status)
+	}
+	if problem.Title != "Bad Request" || problem.Detail != "Erro de validação" {
+		t.Errorf("Título/detalhe inesperados: %+v", problem)
+	}
+	if problem.Instance != "/users/register" {
+		t.Errorf("Instance esperado '/users/register', obteve '%s'", problem.Instance)
+	}
+	if len(problem.Errors) != 2 || problem.Errors[0].Field != "email" || problem.Errors[1].Field != "password" {
+		t.Errorf("Esperava uma entrada por campo, obteve %+v", problem.Errors)
+	}
+}
+
+func TestNewProblemDetails_GenericError(t *testing.T) {
+	problem := NewProblemDetails(errors.New("falha"), "")
+	if problem.Status != http.StatusInternalServerError {
+		t.Errorf("Erro genérico deveria virar 500, obteve %d", problem.Status)
+	}
+	if problem.Errors != nil {
+		t.Errorf("Erro genérico não deveria ter entradas por campo: %+v", problem.Errors)
+	}
+}
+
+func TestNewProblemDetails_ContextErrors(t *testing.T) {
+	problem := NewProblemDetails(ErrInternalServer.WithError(context.Canceled), "")
+	if problem.Status != StatusClientClosedRequest || problem.Title != "Client Closed Request" {
+		t.Errorf("Cancelamento deveria virar 499, obteve %+v", problem)
+	}
+
+	problem = NewProblemDetails(context.DeadlineExceeded, "")
+	if problem.Status != http.StatusGatewayTimeout || problem.Title != "Gateway Timeout" {
+		t.Errorf("Prazo esgotado deveria virar 504, obteve %+v", problem)
+	}
+}
+
+func TestGinHandleError_ProblemJSONMode(t *testing.T) {
+	SetProblemJSON(true)
+	defer SetProblemJSON(false)
+
+	router := setupGinTest()
+	router.POST("/users/register", func(c *gin.Context) {
+		details := []ValidationDetail{{Field: "email", Message: "Email inválido"}}
+		GinHandleError(c, NewValidationError("Erro de validação", details))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users/register", nil)
+	router.ServeHTTP(w, req)
+
+	assertStatus(t, w.Code, http.StatusBadRequest)
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type esperado %s, obteve %s", ProblemContentType, ct)
+	}
+
+	var problem ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Erro ao decodificar resposta JSON: %v", err)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" || problem.Errors[0].Message != "Email inválido" {
+		t.Errorf("Esperava entrada para o campo 'email', obteve %+v", problem.Errors)
+	}
+	if problem.Instance != "/users/register" {
+		t.Errorf("Instance esperado '/users/register', obteve '%s'", problem.Instance)
+	}
+}
+
+func TestHandleErrorWithRequest_ProblemJSONMode(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/users/me", nil)
+
+	// Modo desabilitado mantém o formato padrão
+	w := httptest.NewRecorder()
+	HandleErrorWithRequest(w, req, ErrUnauthorized)
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type esperado application/json, obteve %s", ct)
+	}
+
+	SetProblemJSON(true)
+	defer SetProblemJSON(false)
+
+	w = httptest.NewRecorder()
+	HandleErrorWithRequest(w, req, ErrUnauthorized)
+	assertStatus(t, w.Code, http.StatusUnauthorized)
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type esperado %s, obteve %s", ProblemContentType, ct)
+	}
+}
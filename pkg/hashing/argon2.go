@@ -0,0 +1,162 @@
+package hashing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Limites aceitos para os parâmetros do Argon2id
+const (
+	MinArgon2Memory      uint32 = 8 * 1024        // 8 MiB (em KiB)
+	MaxArgon2Memory      uint32 = 4 * 1024 * 1024 // 4 GiB (em KiB)
+	MinArgon2Iterations  uint32 = 1
+	MaxArgon2Iterations  uint32 = 10
+	MinArgon2Parallelism uint8  = 1
+	MaxArgon2Parallelism uint8  = 64
+)
+
+var (
+	// ErrInvalidArgon2Params indica parâmetros fora dos limites seguros
+	ErrInvalidArgon2Params = errors.New("parâmetros do Argon2 inválidos")
+	// ErrInvalidHash indica um hash que não está no formato esperado
+	ErrInvalidHash = errors.New("hash em formato inválido")
+	// ErrMismatchedHashAndPassword indica que a senha não corresponde ao hash
+	ErrMismatchedHashAndPassword = errors.New("senha não corresponde ao hash")
+)
+
+// Argon2Params define os parâmetros de custo do Argon2id
+type Argon2Params struct {
+	Memory      uint32 // memória em KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params retorna os parâmetros padrão do Argon2id
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Validate verifica se os parâmetros estão dentro dos limites seguros
+func (p Argon2Params) Validate() error {
+	if p.Memory < MinArgon2Memory || p.Memory > MaxArgon2Memory {
+		return fmt.Errorf("%w: memória deve estar entre %d e %d KiB, recebido %d", ErrInvalidArgon2Params, MinArgon2Memory, MaxArgon2Memory, p.Memory)
+	}
+	if p.Iterations < MinArgon2Iterations || p.Iterations > MaxArgon2Iterations {
+		return fmt.Errorf("%w: iterações devem estar entre %d e %d, recebido %d", ErrInvalidArgon2Params, MinArgon2Iterations, MaxArgon2Iterations, p.Iterations)
+	}
+	if p.Parallelism < MinArgon2Parallelism || p.Parallelism > MaxArgon2Parallelism {
+		return fmt.Errorf("%w: paralelismo deve estar entre %d e %d, recebido %d", ErrInvalidArgon2Params, MinArgon2Parallelism, MaxArgon2Parallelism, p.Parallelism)
+	}
+	if p.SaltLength < 16 || p.KeyLength < 16 {
+		return fmt.Errorf("%w: salt e chave devem ter pelo menos 16 bytes", ErrInvalidArgon2Params)
+	}
+	return nil
+}
+
+// Argon2Hasher gera e verifica hashes de senha com Argon2id
+type Argon2Hasher struct {
+	params Argon2Params
+}
+
+// NewArgon2Hasher cria um hasher Argon2id, rejeitando parâmetros fora dos limites seguros
+func NewArgon2Hasher(params Argon2Params) (*Argon2Hasher, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return &Argon2Hasher{params: params}, nil
+}
+
+// Params retorna os parâmetros usados pelo hasher
+func (h *Argon2Hasher) Params() Argon2Params {
+	return h.params
+}
+
+// Hash gera o hash da senha no formato PHC ($argon2id$v=19$m=...,t=...,p=...$salt$hash)
+func (h *Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify compara a senha com um hash gerado por Hash, usando os parâmetros gravados no próprio hash
+func (h *Argon2Hasher) Verify(encodedHash, password string) error {
+	return VerifyArgon2(encodedHash, password)
+}
+
+// IsArgon2Hash indica se o hash está no formato PHC do Argon2id gerado por Hash
+func IsArgon2Hash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+// VerifyArgon2 compara a senha com um hash Argon2id, sem depender dos parâmetros
+// de um hasher: os do próprio hash são usados
+func VerifyArgon2(encodedHash, password string) error {
+	params, salt, key, err := decodeArgon2Hash(encodedHash)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// decodeArgon2Hash extrai parâmetros, salt e chave de um hash no formato PHC
+func decodeArgon2Hash(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var params Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	// Evita que um hash adulterado force um custo absurdo na verificação
+	if err := params.Validate(); err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,96 @@
+package hashing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fastParams usa o mínimo de memória para manter os testes rápidos
+func fastParams() Argon2Params {
+	return Argon2Params{Memory: MinArgon2Memory, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2Hasher_UsesConfiguredParams(t *testing.T) {
+	params := Argon2Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 3, SaltLength: 16, KeyLength: 32}
+	hasher, err := NewArgon2Hasher(params)
+	if err != nil {
+		t.Fatalf("Não esperava erro: %v", err)
+	}
+
+	hash, err := hasher.Hash("senha-secreta")
+	if err != nil {
+		t.Fatalf("Não esperava erro ao gerar hash: %v", err)
+	}
+
+	if !strings.Contains(hash, "$m=16384,t=2,p=3$") {
+		t.Errorf("Hash deveria conter os parâmetros configurados, mas foi %s", hash)
+	}
+
+	if hasher.Params() != params {
+		t.Errorf("Params esperado %+v, mas foi %+v", params, hasher.Params())
+	}
+}
+
+func TestArgon2Hasher_HashAndVerify(t *testing.T) {
+	hasher, err := NewArgon2Hasher(fastParams())
+	if err != nil {
+		t.Fatalf("Não esperava erro: %v", err)
+	}
+
+	hash, err := hasher.Hash("senha-secreta")
+	if err != nil {
+		t.Fatalf("Não esperava erro ao gerar hash: %v", err)
+	}
+
+	if err := hasher.Verify(hash, "senha-secreta"); err != nil {
+		t.Errorf("Senha correta deveria ser aceita: %v", err)
+	}
+
+	if err := hasher.Verify(hash, "errada"); !errors.Is(err, ErrMismatchedHashAndPassword) {
+		t.Errorf("Senha errada deveria retornar ErrMismatchedHashAndPassword, mas foi %v", err)
+	}
+
+	if err := hasher.Verify("nao-e-um-hash", "senha-secreta"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("Hash malformado deveria retornar ErrInvalidHash, mas foi %v", err)
+	}
+}
+
+func TestNewArgon2Hasher_RejectsAbsurdParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Argon2Params)
+	}{
+		{"memória baixa demais", func(p *Argon2Params) { p.Memory = 1 }},
+		{"memória alta demais", func(p *Argon2Params) { p.Memory = MaxArgon2Memory + 1 }},
+		{"zero iterações", func(p *Argon2Params) { p.Iterations = 0 }},
+		{"iterações demais", func(p *Argon2Params) { p.Iterations = 1000 }},
+		{"zero paralelismo", func(p *Argon2Params) { p.Parallelism = 0 }},
+		{"paralelismo demais", func(p *Argon2Params) { p.Parallelism = 255 }},
+		{"salt curto", func(p *Argon2Params) { p.SaltLength = 4 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DefaultArgon2Params()
+			tt.modify(&params)
+
+			_, err := NewArgon2Hasher(params)
+			if !errors.Is(err, ErrInvalidArgon2Params) {
+				t.Errorf("Esperava ErrInvalidArgon2Params, mas foi %v", err)
+			}
+		})
+	}
+}
+
+func TestVerify_RejectsTamperedCost(t *testing.T) {
+	hasher, _ := NewArgon2Hasher(fastParams())
+	hash, _ := hasher.Hash("senha")
+
+	// Adultera o custo de memória para um valor absurdo
+	tampered := strings.Replace(hash, "m=8192", "m=999999999", 1)
+
+	if err := hasher.Verify(tampered, "senha"); !errors.Is(err, ErrInvalidArgon2Params) {
+		t.Errorf("Hash com custo absurdo deveria ser rejeitado, mas foi %v", err)
+	}
+}
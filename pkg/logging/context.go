@@ -0,0 +1,178 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Chaves usadas para ler os campos de log de um context.Context. São strings
+// para que o *gin.Context as resolva a partir dos valores definidos com Set.
+const (
+	FieldsKey    = "log_fields"
+	UserIDKey    = "user_id"
+	RequestIDKey = "request_id"
+)
+
+// Fields são os campos de contexto de uma requisição incluídos em cada linha de log
+type Fields struct {
+	RequestID string
+	ClientIP  string
+	UserID    string
+	Route     string
+}
+
+// fieldsKey guarda os campos em contextos que não são do Gin
+type fieldsKey struct{}
+
+// NewContext retorna uma cópia de ctx com os campos de log informados
+func NewContext(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// WithRequestID retorna uma cópia de ctx cujos campos de log trazem o ID da
+// requisição, preservando os demais campos já definidos
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	fields := FieldsFrom(ctx)
+	fields.RequestID = requestID
+	return NewContext(ctx, fields)
+}
+
+// FieldsFrom extrai os campos de log de ctx. O usuário autenticado e o ID da
+// requisição definidos à parte (sob UserIDKey e RequestIDKey) são usados quando
+// os campos não os trazem.
+func FieldsFrom(ctx context.Context) Fields {
+	var fields Fields
+	if ctx == nil {
+		return fields
+	}
+	switch f := ctx.Value(FieldsKey).(type) {
+	case Fields:
+		fields = f
+	case *Fields:
+		fields = *f
+	default:
+		if f, ok := ctx.Value(fieldsKey{}).(Fields); ok {
+			fields = f
+		}
+	}
+	if fields.UserID == "" {
+		if id, ok := ctx.Value(UserIDKey).(string); ok {
+			fields.UserID = id
+		}
+	}
+	if fields.RequestID == "" {
+		if id, ok := ctx.Value(RequestIDKey).(string); ok {
+			fields.RequestID = id
+		}
+	}
+	return fields
+}
+
+// prefix formata os campos preenchidos como "[request_id=... ip=... user=... route=...] "
+func (f Fields) prefix() string {
+	var parts []string
+	for _, field := range []struct{ key, value string }{
+		{"request_id", f.RequestID},
+		{"ip", f.ClientIP},
+		{"user", f.UserID},
+		{"route", f.Route},
+	} {
+		if field.value != "" {
+			parts = append(parts, field.key+"="+field.value)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, " ") + "] "
+}
+
+// kv retorna os campos preenchidos como pares chave/valor, com as mesmas chaves
+// do prefixo de texto
+func (f Fields) kv() map[string]any {
+	kv := make(map[string]any, 4)
+	for _, field := range []struct{ key, value string }{
+		{"request_id", f.RequestID},
+		{"ip", f.ClientIP},
+		{"user", f.UserID},
+		{"route", f.Route},
+	} {
+		if field.value != "" {
+			kv[field.key] = field.value
+		}
+	}
+	return kv
+}
+
+// ContextLogger registra mensagens precedidas pelos campos de contexto da requisição
+type ContextLogger struct {
+	fields Fields
+	prefix string
+}
+
+// With retorna um logger que inclui em cada mensagem os campos de log de ctx
+// (ver FieldsFrom), dispensando os handlers de formatar prefixos manualmente.
+// Em JSON os campos entram no objeto em vez do prefixo.
+func With(ctx context.Context) *ContextLogger {
+	fields := FieldsFrom(ctx)
+	// Os campos entram no formato; "%" vindo de cabeçalhos não pode virar verbo
+	return &ContextLogger{fields: fields, prefix: strings.ReplaceAll(fields.prefix(), "%", "%%")}
+}
+
+// logf emite a mensagem formatada com os campos de contexto. O logger só é lido
+// após setupIfNeeded: antes disso pode ser nil, como logo após ResetLogger.
+func (l *ContextLogger) logf(logger **log.Logger, level, format string, v ...interface{}) {
+	setupIfNeeded()
+	if jsonFormat {
+		output(*logger, level, fmt.Sprintf(format, v...), l.fields.kv())
+		return
+	}
+	output(*logger, level, fmt.Sprintf(l.prefix+format, v...), nil)
+}
+
+// logKV emite a mensagem com os campos de contexto somados aos informados
+func (l *ContextLogger) logKV(logger **log.Logger, level, msg string, kv map[string]any) {
+	setupIfNeeded()
+	merged := l.fields.kv()
+	for k, v := range kv {
+		merged[k] = v
+	}
+	output(*logger, level, msg, merged)
+}
+
+// Debug registra uma mensagem de depuração com os campos de contexto
+func (l *ContextLogger) Debug(format string, v ...interface{}) {
+	l.logf(&debugLogger, "debug", format, v...)
+}
+
+// Info registra uma mensagem de informação com os campos de contexto
+func (l *ContextLogger) Info(format string, v ...interface{}) {
+	l.logf(&infoLogger, "info", format, v...)
+}
+
+// Warning registra uma mensagem de aviso com os campos de contexto
+func (l *ContextLogger) Warning(format string, v ...interface{}) {
+	l.logf(&warningLogger, "warning", format, v...)
+}
+
+// Error registra uma mensagem de erro com os campos de contexto
+func (l *ContextLogger) Error(format string, v ...interface{}) {
+	l.logf(&errorLogger, "error", format, v...)
+}
+
+// InfoKV registra uma mensagem de informação com os campos de contexto e os informados
+func (l *ContextLogger) InfoKV(msg string, kv map[string]any) {
+	l.logKV(&infoLogger, "info", msg, kv)
+}
+
+// WarningKV registra uma mensagem de aviso com os campos de contexto e os informados
+func (l *ContextLogger) WarningKV(msg string, kv map[string]any) {
+	l.logKV(&warningLogger, "warning", msg, kv)
+}
+
+// ErrorKV registra uma mensagem de erro com os campos de contexto e os informados
+func (l *ContextLogger) ErrorKV(msg string, kv map[string]any) {
+	l.logKV(&errorLogger, "error", msg, kv)
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFieldsFrom(t *testing.T) {
+	ctx := NewContext(context.Background(), Fields{RequestID: "req-1", ClientIP: "10.0.0.1", Route: "/users/login"})
+
+	fields := FieldsFrom(ctx)
+	if fields.RequestID != "req-1" || fields.ClientIP != "10.0.0.1" || fields.Route != "/users/login" {
+		t.Errorf("campos inesperados: %+v", fields)
+	}
+
+	if got := FieldsFrom(context.Background()); got != (Fields{}) {
+		t.Errorf("contexto sem campos deveria retornar Fields vazio, mas foi %+v", got)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	ctx := NewContext(context.Background(), Fields{ClientIP: "10.0.0.1"})
+
+	fields := FieldsFrom(WithRequestID(ctx, "req-9"))
+	if fields.RequestID != "req-9" || fields.ClientIP != "10.0.0.1" {
+		t.Errorf("o ID da requisição deveria ser somado aos campos existentes: %+v", fields)
+	}
+
+	if got := FieldsFrom(WithRequestID(context.Background(), "req-10")).RequestID; got != "req-10" {
+		t.Errorf("request_id esperado req-10, mas foi %q", got)
+	}
+}
+
+func TestFieldsPrefix(t *testing.T) {
+	full := Fields{RequestID: "req-1", ClientIP: "10.0.0.1", UserID: "u1", Route: "/users/:id"}
+	if got := full.prefix(); got != "[request_id=req-1 ip=10.0.0.1 user=u1 route=/users/:id] " {
+		t.Errorf("prefixo inesperado: %q", got)
+	}
+
+	if got := (Fields{ClientIP: "10.0.0.1"}).prefix(); got != "[ip=10.0.0.1] " {
+		t.Errorf("campos vazios deveriam ser omitidos, mas o prefixo foi %q", got)
+	}
+
+	if got := (Fields{}).prefix(); got != "" {
+		t.Errorf("sem campos o prefixo deveria ser vazio, mas foi %q", got)
+	}
+}
+
+func TestWith(t *testing.T) {
+	ResetLogger()
+	var buf bytes.Buffer
+	SetupLogger(Config{InfoWriter: &buf, WarningWriter: &buf, ErrorWriter: &buf, Flag: 0})
+	defer ResetLogger()
+
+	ctx := NewContext(context.Background(), Fields{RequestID: "100%s", ClientIP: "10.0.0.1"})
+	With(ctx).Info("login de %s", "a@b.com")
+
+	got := buf.String()
+	if !strings.Contains(got, "[request_id=100%s ip=10.0.0.1] login de a@b.com") {
+		t.Errorf("mensagem sem os campos de contexto: %q", got)
+	}
+	if strings.Contains(got, "%!") {
+		t.Errorf("campos do contexto não deveriam ser interpretados como formato: %q", got)
+	}
+}
+
+func TestWith_AfterReset(t *testing.T) {
+	ResetLogger()
+	defer ResetLogger()
+
+	// Sem configuração, o logger de contexto recria os loggers padrão em vez de usar nil
+	With(context.Background()).Debug("mensagem antes da configuração")
+	With(context.Background()).InfoKV("mensagem antes da configuração", map[string]any{"k": "v"})
+}
@@ -1,78 +1,234 @@
 package logging
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Formatos de saída aceitos em Config.Format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
 var (
+	debugLogger   *log.Logger
 	infoLogger    *log.Logger
 	warningLogger *log.Logger
 	errorLogger   *log.Logger
-	once          sync.Once
+
+	// setupMu serializa as (re)configurações; configured indica que os loggers
+	// já existem, evitando o lock a cada mensagem
+	setupMu    sync.Mutex
+	configured atomic.Bool
+
+	// jsonFormat indica que cada mensagem é emitida como um objeto JSON por linha
+	jsonFormat bool
 )
 
 // Config contém as configurações do logger
 type Config struct {
+	DebugWriter   io.Writer
 	InfoWriter    io.Writer
 	WarningWriter io.Writer
 	ErrorWriter   io.Writer
 	Prefix        string
 	Flag          int
+	// Format escolhe entre linhas de texto (FormatText, padrão) e um objeto JSON
+	// por linha (FormatJSON) com level, msg, time e os campos informados. Em JSON,
+	// Prefix e Flag são ignorados.
+	Format string
 }
 
 // DefaultConfig retorna a configuração padrão para o logger
 func DefaultConfig() Config {
 	return Config{
+		DebugWriter:   io.Discard,
 		InfoWriter:    os.Stdout,
 		WarningWriter: os.Stdout,
 		ErrorWriter:   os.Stderr,
 		Prefix:        "",
 		Flag:          log.LstdFlags | log.Lshortfile,
+		Format:        FormatText,
 	}
 }
 
-// SetupLogger configura os loggers com a configuração fornecida
+// SetupLogger configura os loggers com a configuração fornecida. Cada chamada
+// substitui a configuração anterior; reconfigure antes de iniciar goroutines que
+// registram mensagens (na inicialização ou em testes).
 func SetupLogger(config Config) {
-	once.Do(func() {
-		infoLogger = log.New(config.InfoWriter, config.Prefix+"INFO: ", config.Flag)
-		warningLogger = log.New(config.WarningWriter, config.Prefix+"WARNING: ", config.Flag)
-		errorLogger = log.New(config.ErrorWriter, config.Prefix+"ERROR: ", config.Flag)
-	})
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	newLoggers(config)
+	configured.Store(true)
+}
+
+// ResetLogger descarta a configuração atual; a próxima mensagem volta a usar
+// DefaultConfig, como antes de SetupLogger. Destinado a testes.
+func ResetLogger() {
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	configured.Store(false)
+	debugLogger, infoLogger, warningLogger, errorLogger = nil, nil, nil, nil
+	jsonFormat = false
+}
+
+// newLoggers cria os loggers de cada nível; em JSON o nível vai no objeto e
+// não no prefixo
+func newLoggers(config Config) {
+	jsonFormat = config.Format == FormatJSON
+	prefix, flag := config.Prefix, config.Flag
+	levelPrefix := func(level string) string { return prefix + level + ": " }
+	if jsonFormat {
+		flag = 0
+		levelPrefix = func(string) string { return "" }
+	}
+	debugLogger = log.New(writerOrDiscard(config.DebugWriter), levelPrefix("DEBUG"), flag)
+	infoLogger = log.New(config.InfoWriter, levelPrefix("INFO"), flag)
+	warningLogger = log.New(config.WarningWriter, levelPrefix("WARNING"), flag)
+	errorLogger = log.New(config.ErrorWriter, levelPrefix("ERROR"), flag)
+}
+
+// Debug registra uma mensagem de depuração, descartada por padrão
+func Debug(format string, v ...interface{}) {
+	setupIfNeeded()
+	output(debugLogger, "debug", fmt.Sprintf(format, v...), nil)
+}
+
+// SetDebugOutput define o destino das mensagens de depuração; nil as descarta
+func SetDebugOutput(w io.Writer) {
+	setupIfNeeded()
+	debugLogger.SetOutput(writerOrDiscard(w))
 }
 
 // Info registra uma mensagem de informação
 func Info(format string, v ...interface{}) {
 	setupIfNeeded()
-	infoLogger.Printf(format, v...)
+	output(infoLogger, "info", fmt.Sprintf(format, v...), nil)
 }
 
 // Warning registra uma mensagem de aviso
 func Warning(format string, v ...interface{}) {
 	setupIfNeeded()
-	warningLogger.Printf(format, v...)
+	output(warningLogger, "warning", fmt.Sprintf(format, v...), nil)
 }
 
 // Error registra uma mensagem de erro
 func Error(format string, v ...interface{}) {
 	setupIfNeeded()
-	errorLogger.Printf(format, v...)
+	output(errorLogger, "error", fmt.Sprintf(format, v...), nil)
 }
 
 // Fatal registra uma mensagem de erro e encerra o programa
 func Fatal(format string, v ...interface{}) {
 	setupIfNeeded()
-	errorLogger.Fatalf(format, v...)
+	output(errorLogger, "fatal", fmt.Sprintf(format, v...), nil)
+	os.Exit(1)
+}
+
+// DebugKV registra uma mensagem de depuração com campos chave/valor
+func DebugKV(msg string, kv map[string]any) {
+	setupIfNeeded()
+	output(debugLogger, "debug", msg, kv)
+}
+
+// InfoKV registra uma mensagem de informação com campos chave/valor
+func InfoKV(msg string, kv map[string]any) {
+	setupIfNeeded()
+	output(infoLogger, "info", msg, kv)
+}
+
+// WarningKV registra uma mensagem de aviso com campos chave/valor
+func WarningKV(msg string, kv map[string]any) {
+	setupIfNeeded()
+	output(warningLogger, "warning", msg, kv)
+}
+
+// ErrorKV registra uma mensagem de erro com campos chave/valor
+func ErrorKV(msg string, kv map[string]any) {
+	setupIfNeeded()
+	output(errorLogger, "error", msg, kv)
+}
+
+// output escreve a mensagem no formato configurado. Em texto, os campos são
+// anexados como chave=valor em ordem alfabética; em JSON, entram no objeto ao
+// lado de level, msg e time, que têm precedência sobre campos homônimos.
+func output(l *log.Logger, level, msg string, kv map[string]any) {
+	if !jsonFormat {
+		l.Print(msg + formatKV(kv))
+		return
+	}
+
+	entry := make(map[string]any, len(kv)+3)
+	for k, v := range kv {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		entry[k] = v
+	}
+	entry["level"] = level
+	entry["msg"] = msg
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// Campo não serializável: a mensagem não se perde
+		line, _ = json.Marshal(map[string]any{
+			"level": level, "msg": msg, "time": entry["time"], "log_error": err.Error(),
+		})
+	}
+	l.Print(string(line))
+}
+
+// formatKV formata os campos como " chave=valor", entre aspas quando o valor tem
+// espaços, aspas ou "="
+func formatKV(kv map[string]any) string {
+	if len(kv) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		value := fmt.Sprint(kv[k])
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(" " + k + "=" + value)
+	}
+	return b.String()
 }
 
 // setupIfNeeded configura os loggers com a configuração padrão se ainda não foram configurados
 func setupIfNeeded() {
-	once.Do(func() {
-		config := DefaultConfig()
-		infoLogger = log.New(config.InfoWriter, config.Prefix+"INFO: ", config.Flag)
-		warningLogger = log.New(config.WarningWriter, config.Prefix+"WARNING: ", config.Flag)
-		errorLogger = log.New(config.ErrorWriter, config.Prefix+"ERROR: ", config.Flag)
-	})
+	if configured.Load() {
+		return
+	}
+	setupMu.Lock()
+	defer setupMu.Unlock()
+	if !configured.Load() {
+		newLoggers(DefaultConfig())
+		configured.Store(true)
+	}
+}
+
+// writerOrDiscard evita loggers sem destino quando o writer não é informado
+func writerOrDiscard(w io.Writer) io.Writer {
+	if w == nil {
+		return io.Discard
+	}
+	return w
 }
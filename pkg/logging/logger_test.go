@@ -2,11 +2,14 @@ package logging
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"log"
 	"os"
 	"strings"
-	"sync"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -35,11 +38,7 @@ func TestDefaultConfig(t *testing.T) {
 }
 
 func TestSetupLogger(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var infoBuf, warningBuf, errorBuf bytes.Buffer
 
@@ -93,11 +92,7 @@ func TestSetupLogger(t *testing.T) {
 }
 
 func TestInfo(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var buf bytes.Buffer
 	config := Config{
@@ -123,11 +118,7 @@ func TestInfo(t *testing.T) {
 }
 
 func TestWarning(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var buf bytes.Buffer
 	config := Config{
@@ -153,11 +144,7 @@ func TestWarning(t *testing.T) {
 }
 
 func TestError(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var buf bytes.Buffer
 	config := Config{
@@ -183,11 +170,7 @@ func TestError(t *testing.T) {
 }
 
 func TestFatal(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var buf bytes.Buffer
 	config := Config{
@@ -208,11 +191,7 @@ func TestFatal(t *testing.T) {
 }
 
 func TestSetupIfNeeded(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	// Chama Info sem configurar o logger primeiro
 	// Isso deve chamar setupIfNeeded automaticamente
@@ -233,11 +212,7 @@ func TestSetupIfNeeded(t *testing.T) {
 }
 
 func TestMultipleSetupCalls(t *testing.T) {
-	// Reset global variables
-	infoLogger = nil
-	warningLogger = nil
-	errorLogger = nil
-	once = sync.Once{}
+	ResetLogger()
 
 	var buf1, buf2 bytes.Buffer
 
@@ -261,27 +236,155 @@ func TestMultipleSetupCalls(t *testing.T) {
 	SetupLogger(config1)
 	Info("primeira mensagem")
 
-	// Segunda configuração (não deve sobrescrever a primeira)
+	// Segunda configuração substitui a primeira
 	SetupLogger(config2)
 	Info("segunda mensagem")
 
-	// Verifica se apenas a primeira configuração foi usada
 	output1 := buf1.String()
 	output2 := buf2.String()
 
-	if !strings.Contains(output1, "FIRST: INFO: ") {
-		t.Errorf("Primeira configuração deveria ter sido usada: %s", output1)
+	if !strings.Contains(output1, "FIRST: INFO: ") || !strings.Contains(output1, "primeira mensagem") {
+		t.Errorf("Primeira mensagem deveria usar a primeira configuração: %s", output1)
 	}
 
-	if !strings.Contains(output1, "primeira mensagem") {
-		t.Errorf("Primeira mensagem deveria estar na primeira configuração: %s", output1)
+	if strings.Contains(output1, "segunda mensagem") {
+		t.Errorf("Segunda mensagem não deveria ir para o writer antigo: %s", output1)
 	}
 
-	if !strings.Contains(output1, "segunda mensagem") {
-		t.Errorf("Segunda mensagem deveria estar na primeira configuração: %s", output1)
+	if !strings.Contains(output2, "SECOND: INFO: ") || !strings.Contains(output2, "segunda mensagem") {
+		t.Errorf("Segunda mensagem deveria usar a nova configuração: %s", output2)
 	}
+}
+
+func TestResetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLogger(Config{InfoWriter: &buf, WarningWriter: &buf, ErrorWriter: &buf, Format: FormatJSON})
+
+	ResetLogger()
+	if infoLogger != nil || jsonFormat {
+		t.Fatal("ResetLogger deveria descartar a configuração atual")
+	}
+
+	// A próxima mensagem volta à configuração padrão, sem passar pelo writer antigo
+	Info("após o reset")
+	if buf.Len() != 0 {
+		t.Errorf("writer descartado não deveria receber mensagens: %s", buf.String())
+	}
+	if infoLogger == nil {
+		t.Error("infoLogger deveria ser recriado com a configuração padrão")
+	}
+}
+
+func TestDebug(t *testing.T) {
+	ResetLogger()
+
+	var buf bytes.Buffer
+	SetupLogger(Config{
+		InfoWriter:    &buf,
+		WarningWriter: &buf,
+		ErrorWriter:   &buf,
+		Flag:          log.LstdFlags,
+	})
+
+	Debug("descartada")
+	if buf.Len() != 0 {
+		t.Errorf("Debug sem destino deveria ser descartado, mas foi: %s", buf.String())
+	}
+
+	SetDebugOutput(&buf)
+	defer SetDebugOutput(nil)
+
+	Debug("teste debug %d", 42)
+	output := buf.String()
+
+	if !strings.Contains(output, "DEBUG: ") {
+		t.Errorf("Output deveria conter 'DEBUG: ', mas foi: %s", output)
+	}
+
+	if !strings.Contains(output, "teste debug 42") {
+		t.Errorf("Output deveria conter 'teste debug 42', mas foi: %s", output)
+	}
+}
+
+// setupJSON reconfigura os loggers em JSON, escrevendo em buf
+func setupJSON(t *testing.T, buf *bytes.Buffer) {
+	t.Helper()
+	ResetLogger()
+	SetupLogger(Config{DebugWriter: buf, InfoWriter: buf, WarningWriter: buf, ErrorWriter: buf, Prefix: "IGNORADO: ", Flag: log.LstdFlags, Format: FormatJSON})
+	t.Cleanup(ResetLogger)
+}
+
+// jsonLines decodifica cada linha de buf como um objeto JSON
+func jsonLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("linha não é JSON válido: %q (%v)", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestJSONFormat_LevelsAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	setupJSON(t, &buf)
+
+	Info("login de %s", "a@b.com")
+	Warning("tentativa %d", 3)
+	ErrorKV("falha ao salvar", map[string]any{"user_id": "u1", "err": errors.New("timeout"), "attempt": 2})
+
+	entries := jsonLines(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("3 linhas esperadas, mas foram %d: %s", len(entries), buf.String())
+	}
+	for i, level := range []string{"info", "warning", "error"} {
+		if entries[i]["level"] != level {
+			t.Errorf("linha %d: level esperado %q, mas foi %v", i, level, entries[i]["level"])
+		}
+		if _, err := time.Parse(time.RFC3339Nano, entries[i]["time"].(string)); err != nil {
+			t.Errorf("linha %d: time inválido: %v", i, entries[i]["time"])
+		}
+	}
+	if entries[0]["msg"] != "login de a@b.com" {
+		t.Errorf("msg inesperada: %v", entries[0]["msg"])
+	}
+	if entries[2]["user_id"] != "u1" || entries[2]["err"] != "timeout" || entries[2]["attempt"] != float64(2) {
+		t.Errorf("campos chave/valor inesperados: %v", entries[2])
+	}
+	if strings.Contains(buf.String(), "IGNORADO") {
+		t.Errorf("o prefixo não deveria aparecer em JSON: %s", buf.String())
+	}
+}
+
+func TestJSONFormat_ContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	setupJSON(t, &buf)
+
+	ctx := NewContext(context.Background(), Fields{RequestID: "req-1", ClientIP: "10.0.0.1"})
+	With(ctx).Info("100%% concluído")
+	With(ctx).WarningKV("lento", map[string]any{"ms": 250})
+
+	entries := jsonLines(t, &buf)
+	if entries[0]["msg"] != "100% concluído" || entries[0]["request_id"] != "req-1" || entries[0]["ip"] != "10.0.0.1" {
+		t.Errorf("campos de contexto deveriam entrar no objeto: %v", entries[0])
+	}
+	if entries[1]["level"] != "warning" || entries[1]["ms"] != float64(250) || entries[1]["request_id"] != "req-1" {
+		t.Errorf("campos informados deveriam somar-se aos de contexto: %v", entries[1])
+	}
+}
+
+func TestTextFormat_KV(t *testing.T) {
+	ResetLogger()
+	defer ResetLogger()
+	var buf bytes.Buffer
+	SetupLogger(Config{InfoWriter: &buf, WarningWriter: &buf, ErrorWriter: &buf})
+
+	InfoKV("login", map[string]any{"user": "u1", "agent": "curl 8"})
 
-	if output2 != "" {
-		t.Errorf("Segunda configuração não deveria ter sido usada: %s", output2)
+	if got := buf.String(); !strings.Contains(got, `INFO: login agent="curl 8" user=u1`) {
+		t.Errorf("linha de texto inesperada: %q", got)
 	}
 }
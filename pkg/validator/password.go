@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"sync/atomic"
+	"unicode"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+)
+
+// DefaultMinPasswordLength é o tamanho mínimo padrão de uma senha
+const DefaultMinPasswordLength = 8
+
+// minPasswordLength é o tamanho mínimo aplicado por ValidatePasswordStrength
+var minPasswordLength atomic.Int64
+
+func init() {
+	minPasswordLength.Store(DefaultMinPasswordLength)
+}
+
+// SetMinPasswordLength define o tamanho mínimo exigido das senhas; valores
+// menores que 1 restauram DefaultMinPasswordLength
+func SetMinPasswordLength(n int) {
+	if n < 1 {
+		n = DefaultMinPasswordLength
+	}
+	minPasswordLength.Store(int64(n))
+}
+
+// MinPasswordLength retorna o tamanho mínimo de senha em vigor
+func MinPasswordLength() int {
+	return int(minPasswordLength.Load())
+}
+
+// ValidatePasswordStrength exige o tamanho mínimo configurado (contado em
+// caracteres, não em bytes) e ao menos uma letra e um dígito, retornando
+// errors.ErrPasswordTooWeak quando a senha não atende aos requisitos
+func ValidatePasswordStrength(password string) error {
+	var length int
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		length++
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	if length < MinPasswordLength() || !hasLetter || !hasDigit {
+		return errors.ErrPasswordTooWeak
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePasswordStrength(t *testing.T) {
+	cases := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{"letras e dígitos no tamanho mínimo", "abcdef12", true},
+		{"caracteres acentuados contam como letras", "senhaçã1", true},
+		{"curta demais", "abc12", false},
+		{"vazia", "", false},
+		{"sem dígito", "somenteletras", false},
+		{"sem letra", "1234567890", false},
+		{"apenas símbolos e dígitos", "!@#$%123", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePasswordStrength(tc.password)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errors.ErrPasswordTooWeak)
+			}
+		})
+	}
+}
+
+func TestSetMinPasswordLength(t *testing.T) {
+	defer SetMinPasswordLength(DefaultMinPasswordLength)
+
+	SetMinPasswordLength(12)
+	assert.Equal(t, 12, MinPasswordLength())
+	assert.ErrorIs(t, ValidatePasswordStrength("abcdef12"), errors.ErrPasswordTooWeak)
+	assert.NoError(t, ValidatePasswordStrength("abcdefghij12"))
+
+	SetMinPasswordLength(0)
+	assert.Equal(t, DefaultMinPasswordLength, MinPasswordLength())
+}
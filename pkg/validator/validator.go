@@ -2,26 +2,47 @@ package validator
 
 import (
 	"fmt"
+	"net/mail"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
 )
 
 var (
 	validate   *validator.Validate
 	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	// sem "@", para que o identificador de login nunca seja confundido com um email
+	usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9._\-]{3,32}$`)
 )
 
+// maxEmailLength é o maior endereço utilizável em um caminho SMTP (RFC 5321)
+const maxEmailLength = 254
+
+// strictEmail troca a regex permissiva pela análise no estilo RFC 5322 em IsEmail
+var strictEmail atomic.Bool
+
+// SetStrictEmail define se IsEmail usa a validação estrita (net/mail) em vez da
+// regex permissiva, que segue como padrão
+func SetStrictEmail(enabled bool) {
+	strictEmail.Store(enabled)
+}
+
 // ValidationError representa um erro de validação
 type ValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
-// Init inicializa o validador
+// Init inicializa o validador e registra a regra "password", que aplica
+// ValidatePasswordStrength com o tamanho mínimo em vigor
 func Init() {
 	validate = validator.New()
+	_ = validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return ValidatePasswordStrength(fl.Field().String()) == nil
+	})
 }
 
 // ValidateStruct valida uma estrutura e retorna uma lista de erros de validação
@@ -45,11 +66,64 @@ func ValidateStruct(s interface{}) []ValidationError {
 	return errors
 }
 
-// IsEmail valida se uma string é um email válido
+// IsEmail valida se uma string é um email válido, no modo definido por SetStrictEmail
 func IsEmail(email string) bool {
+	if strictEmail.Load() {
+		return IsStrictEmail(email)
+	}
 	return emailRegex.MatchString(email)
 }
 
+// IsStrictEmail valida o endereço com net/mail.ParseAddress, aceitando
+// plus-addressing, caracteres internacionais e TLDs longos, mas recusando nomes
+// de exibição, pontos consecutivos e domínios sem ponto ou com rótulos inválidos
+func IsStrictEmail(email string) bool {
+	if email == "" || len(email) > maxEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return false
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// IsUsername valida se uma string é um username válido (3 a 32 caracteres entre
+// letras, dígitos, ".", "_" e "-")
+func IsUsername(username string) bool {
+	return usernameRegex.MatchString(username)
+}
+
+// IsReservedEmail indica se a parte local do email (antes do @, ignorando
+// sufixos "+tag") pertence à lista de nomes reservados, sem diferenciar maiúsculas
+func IsReservedEmail(email string, reserved []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return false
+	}
+	local := email[:at]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	for _, r := range reserved {
+		if strings.EqualFold(local, strings.TrimSpace(r)) {
+			return true
+		}
+	}
+	return false
+}
+
 // toSnakeCase converte uma string de camelCase para snake_case
 func toSnakeCase(s string) string {
 	var result strings.Builder
@@ -73,6 +147,10 @@ func getErrorMessage(err validator.FieldError) string {
 		return fmt.Sprintf("Deve ter no mínimo %s caracteres", err.Param())
 	case "max":
 		return fmt.Sprintf("Deve ter no máximo %s caracteres", err.Param())
+	case "password":
+		return errors.ErrPasswordTooWeak.Message
+	case "eqfield":
+		return fmt.Sprintf("Deve ser igual ao campo %s", toSnakeCase(err.Param()))
 	default:
 		return fmt.Sprintf("Validação falhou para a regra: %s", err.Tag())
 	}
@@ -2,9 +2,11 @@ package validator
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
-	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/lucas-de-lima/go-auth-system/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,24 +15,26 @@ type testStruct struct {
 	Name  string `validate:"required,min=3,max=10"`
 }
 
+// fakeFieldError embute a interface para herdar os métodos não usados nos testes
+// (como Translate) sem depender do pacote de tradução
 type fakeFieldError struct {
+	validator.FieldError
 	tag   string
 	param string
 }
 
-func (f fakeFieldError) Tag() string                      { return f.tag }
-func (f fakeFieldError) Param() string                    { return f.param }
-func (f fakeFieldError) Field() string                    { return "Field" }
-func (f fakeFieldError) StructField() string              { return "Field" }
-func (f fakeFieldError) StructNamespace() string          { return "" }
-func (f fakeFieldError) Namespace() string                { return "" }
-func (f fakeFieldError) Kind() reflect.Kind               { return reflect.String }
-func (f fakeFieldError) Type() reflect.Type               { return reflect.TypeOf("") }
-func (f fakeFieldError) Value() interface{}               { return "" }
-func (f fakeFieldError) ParamInt() int64                  { return 0 }
-func (f fakeFieldError) ActualTag() string                { return f.tag }
-func (f fakeFieldError) Error() string                    { return "" }
-func (f fakeFieldError) Translate(_ ut.Translator) string { return "" }
+func (f fakeFieldError) Tag() string             { return f.tag }
+func (f fakeFieldError) Param() string           { return f.param }
+func (f fakeFieldError) Field() string           { return "Field" }
+func (f fakeFieldError) StructField() string     { return "Field" }
+func (f fakeFieldError) StructNamespace() string { return "" }
+func (f fakeFieldError) Namespace() string       { return "" }
+func (f fakeFieldError) Kind() reflect.Kind      { return reflect.String }
+func (f fakeFieldError) Type() reflect.Type      { return reflect.TypeOf("") }
+func (f fakeFieldError) Value() interface{}      { return "" }
+func (f fakeFieldError) ParamInt() int64         { return 0 }
+func (f fakeFieldError) ActualTag() string       { return f.tag }
+func (f fakeFieldError) Error() string           { return "" }
 
 func TestIsEmail(t *testing.T) {
 	assert.True(t, IsEmail("a@b.com"))
@@ -38,6 +42,63 @@ func TestIsEmail(t *testing.T) {
 	assert.False(t, IsEmail(""))
 }
 
+func TestIsStrictEmail(t *testing.T) {
+	for _, email := range []string{
+		"a@b.com",
+		"usuario+tag@example.com",
+		"first.last@sub.example.co.uk",
+		"joão@exemplo.com.br",
+		"contato@empresa.photography",
+		"user@xn--exmple-cua.com",
+	} {
+		assert.True(t, IsStrictEmail(email), email)
+	}
+
+	for _, email := range []string{
+		"",
+		"a@b",
+		"a..b@example.com",
+		".a@example.com",
+		"a@example..com",
+		"a@-example.com",
+		"a@exa mple.com",
+		"Nome <a@example.com>",
+		strings.Repeat("a", 250) + "@b.com",
+	} {
+		assert.False(t, IsStrictEmail(email), email)
+	}
+}
+
+func TestIsEmail_StrictMode(t *testing.T) {
+	// A regex permissiva aceita pontos consecutivos e recusa caracteres internacionais
+	assert.True(t, IsEmail("a..b@example.com"))
+	assert.False(t, IsEmail("joão@exemplo.com.br"))
+
+	SetStrictEmail(true)
+	defer SetStrictEmail(false)
+	assert.False(t, IsEmail("a..b@example.com"))
+	assert.True(t, IsEmail("joão@exemplo.com.br"))
+}
+
+func TestIsUsername(t *testing.T) {
+	assert.True(t, IsUsername("joao.silva"))
+	assert.True(t, IsUsername("user_01-x"))
+	assert.False(t, IsUsername("jo"))
+	assert.False(t, IsUsername("joao@example.com"))
+	assert.False(t, IsUsername("joão"))
+	assert.False(t, IsUsername(""))
+}
+
+func TestIsReservedEmail(t *testing.T) {
+	reserved := []string{"admin", "postmaster"}
+	assert.True(t, IsReservedEmail("admin@example.com", reserved))
+	assert.True(t, IsReservedEmail("Admin+teste@example.com", reserved))
+	assert.True(t, IsReservedEmail("POSTMASTER@example.com", reserved))
+	assert.False(t, IsReservedEmail("administrador@example.com", reserved))
+	assert.False(t, IsReservedEmail("joao@example.com", reserved))
+	assert.False(t, IsReservedEmail("admin@example.com", nil))
+}
+
 func TestToSnakeCase(t *testing.T) {
 	assert.Equal(t, "email_test", toSnakeCase("EmailTest"))
 	assert.Equal(t, "nome", toSnakeCase("Nome"))
@@ -53,6 +114,8 @@ func TestGetErrorMessage(t *testing.T) {
 		{"email", "", "Email inválido"},
 		{"min", "3", "mínimo"},
 		{"max", "10", "máximo"},
+		{"eqfield", "Password", "igual ao campo password"},
+		{"password", "", "requisitos mínimos"},
 		{"outra", "", "Validação falhou"},
 	}
 	for _, c := range cases {
@@ -76,3 +139,21 @@ func TestValidateStruct(t *testing.T) {
 	assert.Contains(t, fields, "email")
 	assert.Contains(t, fields, "name")
 }
+
+func TestValidateStruct_PasswordRule(t *testing.T) {
+	Init()
+	defer SetMinPasswordLength(DefaultMinPasswordLength)
+	type passwordStruct struct {
+		Password string `validate:"required,password"`
+	}
+
+	errs := ValidateStruct(passwordStruct{Password: "abc1"})
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "password", errs[0].Field)
+	assert.Equal(t, errors.ErrPasswordTooWeak.Message, errs[0].Message)
+	assert.Empty(t, ValidateStruct(passwordStruct{Password: "senha123"}))
+
+	// A regra acompanha o tamanho mínimo configurado
+	SetMinPasswordLength(4)
+	assert.Empty(t, ValidateStruct(passwordStruct{Password: "abc1"}))
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisabledAccountIsBlocked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	// TTL zero: o status é consultado a cada requisição
+	status := middleware.NewAccountStatusMiddleware(userService, 0, nil)
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService),
+		middleware.WithAccountStatus(status),
+	).Setup(router)
+
+	require.NoError(t, userService.Create(&domain.User{Email: "root@example.com", Password: "adminpass1", Roles: []string{domain.RoleAdmin}}))
+	target := &domain.User{Email: "alvo@example.com", Password: "senha123", Roles: []string{domain.RoleUser}}
+	require.NoError(t, userService.Create(target))
+	assert.Equal(t, domain.UserStatusActive, target.Status)
+	adminToken := loginForToken(t, router, "root@example.com", "adminpass1")
+	access, refresh := loginForTokens(t, router, "alvo@example.com", "senha123")
+
+	// Admin desativa a conta
+	w := doJSON(router, "PUT", "/admin/users/"+target.ID, adminToken, map[string]string{"status": domain.UserStatusDisabled})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// O access token ainda válido deixa de ser aceito, assim como o refresh e o login
+	w = doJSON(router, "POST", "/users/logout", access, map[string]string{"refresh_token": refresh})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": refresh})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "alvo@example.com", "password": "senha123"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Status desconhecido é rejeitado e a reativação devolve o acesso
+	w = doJSON(router, "PUT", "/admin/users/"+target.ID, adminToken, map[string]string{"status": "banido"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doJSON(router, "PUT", "/admin/users/"+target.ID, adminToken, map[string]string{"status": domain.UserStatusActive})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "alvo@example.com", "password": "senha123"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
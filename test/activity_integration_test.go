@@ -0,0 +1,97 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/activity"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupActivityTestEnvironment configura o ambiente com histórico de atividade em memória
+func setupActivityTestEnvironment() (*gin.Engine, *service.UserService) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	store := activity.NewMemoryStore(0)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, service.WithActivityStore(store))
+	userController := user.NewUserController(userService)
+	activityController := user.NewActivityController(store)
+
+	router := gin.New()
+	router.POST("/users/login", userController.Login)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	router.GET("/users/:id/activity", authMiddleware.GinAuthenticate(), activityController.List)
+	return router, userService
+}
+
+func loginForToken(t *testing.T, router *gin.Engine, email, password string) string {
+	body, _ := json.Marshal(map[string]string{"email": email, "password": password})
+	req := httptest.NewRequest("POST", "/users/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Token
+}
+
+func TestUserActivityFeed(t *testing.T) {
+	router, userService := setupActivityTestEnvironment()
+	owner := &domain.User{Email: "owner@example.com", Password: "ownerpass1", Name: "Owner"}
+	require.NoError(t, userService.Create(owner))
+	other := &domain.User{Email: "other@example.com", Password: "otherpass1", Name: "Other"}
+	require.NoError(t, userService.Create(other))
+
+	var token string
+	for i := 0; i < 3; i++ {
+		token = loginForToken(t, router, "owner@example.com", "ownerpass1")
+	}
+
+	t.Run("Feed inclui os logins e pagina", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users/"+owner.ID+"/activity?page=1&page_size=2", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Items []domain.ActivityEvent `json:"items"`
+			Total int                    `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 3, response.Total)
+		require.Len(t, response.Items, 2)
+		assert.Equal(t, domain.ActivityLogin, response.Items[0].Type)
+
+		req = httptest.NewRequest("GET", "/users/"+owner.ID+"/activity?page=2&page_size=2", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 1)
+	})
+
+	t.Run("Outro usuário não acessa o feed", func(t *testing.T) {
+		otherToken := loginForToken(t, router, "other@example.com", "otherpass1")
+		req := httptest.NewRequest("GET", "/users/"+owner.ID+"/activity", nil)
+		req.Header.Set("Authorization", "Bearer "+otherToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
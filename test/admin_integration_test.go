@@ -6,12 +6,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/config"
 	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
 	"github.com/lucas-de-lima/go-auth-system/internal/domain"
 	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
 	"github.com/lucas-de-lima/go-auth-system/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,7 +24,6 @@ import (
 // setupAdminTestEnvironment configura o ambiente de teste com um admin
 func setupAdminTestEnvironment() (*gin.Engine, *service.UserService, *auth.JWTService, string) {
 	gin.SetMode(gin.TestMode)
-	service.ClearRefreshTokenBlacklist()
 	memRepo := NewInMemoryUserRepository()
 	jwtService := auth.NewJWTService(
 		"test-secret-key",
@@ -48,14 +51,14 @@ func setupAdminTestEnvironment() (*gin.Engine, *service.UserService, *auth.JWTSe
 	// Criar usuário admin
 	adminUser := &domain.User{
 		Email:    "admin@example.com",
-		Password: "adminpass",
+		Password: "adminpass1",
 		Name:     "Admin User",
 		Roles:    []string{"admin"},
 	}
 	err := userService.Create(adminUser)
 	require.NoError(nil, err)
 	// Obter token admin
-	accessToken, _, err := userService.Authenticate("admin@example.com", "adminpass")
+	accessToken, _, err := userService.Authenticate("admin@example.com", "adminpass1")
 	require.NoError(nil, err)
 	return router, userService, jwtService, accessToken
 }
@@ -65,7 +68,7 @@ func TestAdminListAllUsers(t *testing.T) {
 	// Criar usuário comum
 	user := &domain.User{
 		Email:    "user1@example.com",
-		Password: "userpass",
+		Password: "userpass1",
 		Name:     "User 1",
 	}
 	err := userService.Create(user)
@@ -86,7 +89,7 @@ func TestAdminGetUserByID(t *testing.T) {
 	router, userService, _, adminToken := setupAdminTestEnvironment()
 	user := &domain.User{
 		Email:    "user2@example.com",
-		Password: "userpass",
+		Password: "userpass1",
 		Name:     "User 2",
 	}
 	err := userService.Create(user)
@@ -113,7 +116,7 @@ func TestAdminUpdateUser(t *testing.T) {
 	router, userService, _, adminToken := setupAdminTestEnvironment()
 	user := &domain.User{
 		Email:    "user3@example.com",
-		Password: "userpass",
+		Password: "userpass1",
 		Name:     "User 3",
 	}
 	err := userService.Create(user)
@@ -135,6 +138,13 @@ func TestAdminUpdateUser(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Novo Nome", response["name"])
 	assert.Contains(t, response["roles"].([]interface{}), "admin")
+	// O admin autenticado fica registrado como autor da alteração
+	admin, err := userService.GetByEmail("admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, response["updated_by"])
+	stored, err := userService.GetByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, stored.UpdatedBy)
 	// Atualizar usuário inexistente
 	req2 := httptest.NewRequest("PUT", "/admin/users/inexistente", bytes.NewBuffer(jsonData))
 	req2.Header.Set("Authorization", "Bearer "+adminToken)
@@ -148,7 +158,7 @@ func TestAdminDeleteUser(t *testing.T) {
 	router, userService, _, adminToken := setupAdminTestEnvironment()
 	user := &domain.User{
 		Email:    "user4@example.com",
-		Password: "userpass",
+		Password: "userpass1",
 		Name:     "User 4",
 	}
 	err := userService.Create(user)
@@ -175,13 +185,13 @@ func TestAdminAccessDenied(t *testing.T) {
 	// Criar usuário comum
 	user := &domain.User{
 		Email:    "user5@example.com",
-		Password: "userpass",
+		Password: "userpass1",
 		Name:     "User 5",
 	}
 	err := userService.Create(user)
 	require.NoError(t, err)
 	// Obter token de usuário comum
-	userToken, _, err := userService.Authenticate("user5@example.com", "userpass")
+	userToken, _, err := userService.Authenticate("user5@example.com", "userpass1")
 	require.NoError(t, err)
 	// Tentar acessar rota admin sem token
 	req := httptest.NewRequest("GET", "/admin/users", nil)
@@ -201,3 +211,153 @@ func TestAdminAccessDenied(t *testing.T) {
 	router.ServeHTTP(w3, req3)
 	assert.Equal(t, http.StatusUnauthorized, w3.Code)
 }
+
+func TestAdminUpdateUser_EmailTaken(t *testing.T) {
+	router, userService, _, adminToken := setupAdminTestEnvironment()
+	target := &domain.User{Email: "alvo@example.com", Password: "userpass1", Name: "Alvo"}
+	require.NoError(t, userService.Create(target))
+	require.NoError(t, userService.Create(&domain.User{Email: "ocupado@example.com", Password: "userpass1", Name: "Outro"}))
+
+	// O próprio email continua aceito
+	w := doJSON(router, "PUT", "/admin/users/"+target.ID, adminToken, map[string]string{"email": "alvo@example.com", "name": "Alvo 2"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// O email de outra conta resulta em 409, não em erro interno
+	w = doJSON(router, "PUT", "/admin/users/"+target.ID, adminToken, map[string]string{"email": "ocupado@example.com"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminListUsersByCreationRange(t *testing.T) {
+	router, userService, _, adminToken := setupAdminTestEnvironment()
+	created := map[string]time.Time{
+		"antes@example.com":  time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		"dentro@example.com": time.Date(2024, 2, 15, 8, 30, 0, 0, time.UTC),
+		"limite@example.com": time.Date(2024, 2, 29, 23, 59, 0, 0, time.UTC),
+		"depois@example.com": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for email, at := range created {
+		u := &domain.User{Email: email, Password: "userpass1", Name: "User"}
+		require.NoError(t, userService.Create(u))
+		// O repositório em memória guarda o ponteiro, permitindo ajustar a data de criação
+		u.CreatedAt = at
+	}
+
+	req := httptest.NewRequest("GET", "/admin/users?created_from=2024-02-01&created_to=2024-02-29", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	emails := make([]string, 0, len(response))
+	for _, u := range response {
+		emails = append(emails, u["email"].(string))
+	}
+	assert.ElementsMatch(t, []string{"dentro@example.com", "limite@example.com"}, emails)
+}
+
+func TestAdminListUsersByCreationRange_InvalidDates(t *testing.T) {
+	router, _, _, adminToken := setupAdminTestEnvironment()
+	for _, query := range []string{
+		"created_from=ontem",
+		"created_to=2024-13-01",
+		"created_from=2024-03-01&created_to=2024-02-01",
+	} {
+		req := httptest.NewRequest("GET", "/admin/users?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestAdminRoutes_ValidateUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+	require.NoError(t, userService.Create(&domain.User{Email: "root@example.com", Password: "adminpass1", Roles: []string{domain.RoleAdmin}}))
+	adminToken, _, err := userService.Authenticate("root@example.com", "adminpass1")
+	require.NoError(t, err)
+
+	// ID malformado é recusado antes de chegar ao serviço
+	w := doJSON(router, "GET", "/admin/users/nao-e-uuid", adminToken, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// UUID válido segue para a busca e resulta em não encontrado
+	w = doJSON(router, "GET", "/admin/users/"+uuid.New().String(), adminToken, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminConfigDump(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	cfg := &config.Config{
+		App:      config.AppConfig{Environment: "development"},
+		Database: config.DatabaseConfig{Host: "db.interno", Password: "senha-do-banco"},
+		JWT:      config.JWTConfig{Secret: "test-secret-key", RefreshSecret: "test-refresh-key", ExpirationHours: 24},
+	}
+	adminController := user.NewAdminController(userService, user.WithConfigSnapshot(cfg.Redacted()))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, adminController).Setup(router)
+	require.NoError(t, userService.Create(&domain.User{Email: "root@example.com", Password: "adminpass1", Roles: []string{domain.RoleAdmin}}))
+	require.NoError(t, userService.Create(&domain.User{Email: "comum@example.com", Password: "userpass1", Roles: []string{domain.RoleUser}}))
+	adminToken, _, err := userService.Authenticate("root@example.com", "adminpass1")
+	require.NoError(t, err)
+	userToken, _, err := userService.Authenticate("comum@example.com", "userpass1")
+	require.NoError(t, err)
+
+	// Exige autenticação e o papel de admin
+	w := doJSON(router, "GET", "/admin/config", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = doJSON(router, "GET", "/admin/config", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Admin recebe a configuração efetiva com os segredos mascarados
+	w = doJSON(router, "GET", "/admin/config", adminToken, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "senha-do-banco")
+	assert.NotContains(t, w.Body.String(), "test-secret-key")
+	var dump map[string]map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dump))
+	assert.Equal(t, "db.interno", dump["database"]["host"])
+	assert.Equal(t, config.RedactedValue, dump["database"]["password"])
+	assert.Equal(t, config.RedactedValue, dump["jwt"]["secret"])
+	assert.Equal(t, config.RedactedValue, dump["jwt"]["refresh_secret"])
+	assert.Equal(t, float64(24), dump["jwt"]["expiration_hours"])
+}
+
+func TestAdminStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	seed := []*domain.User{
+		{Email: "root@example.com", Password: "adminpass1", Roles: []string{domain.RoleAdmin}, EmailVerified: true},
+		{Email: "ops@example.com", Password: "adminpass1", Roles: []string{domain.RoleUser, domain.RoleAdmin}},
+		{Email: "ana@example.com", Password: "userpass1", Roles: []string{domain.RoleUser}, EmailVerified: true},
+		{Email: "bia@example.com", Password: "userpass1", Roles: []string{domain.RoleUser}, EmailVerified: true},
+		{Email: "caio@example.com", Password: "userpass1", Roles: []string{domain.RoleUser}},
+	}
+	for _, u := range seed {
+		require.NoError(t, userService.Create(u))
+	}
+	adminToken, _, err := userService.Authenticate("root@example.com", "adminpass1")
+	require.NoError(t, err)
+	userToken, _, err := userService.Authenticate("ana@example.com", "userpass1")
+	require.NoError(t, err)
+
+	w := doJSON(router, "GET", "/admin/stats", userToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = doJSON(router, "GET", "/admin/stats", adminToken, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats domain.UserStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, domain.UserStats{Total: 5, Verified: 3, Admins: 2}, stats)
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferredRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, service.WithDeferredRefreshToken(true))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	require.NoError(t, userService.Create(&domain.User{Email: "deferred@example.com", Password: "senha123", Name: "Adiado"}))
+
+	// O login entrega apenas o access token
+	w := doJSON(router, "POST", "/users/login", "", map[string]string{"email": "deferred@example.com", "password": "senha123"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var login map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	accessToken, _ := login["token"].(string)
+	require.NotEmpty(t, accessToken)
+	assert.NotContains(t, login, "refresh_token")
+
+	// A rota dedicada exige autenticação
+	w = doJSON(router, "POST", "/users/refresh/initial", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Com o access token, o primeiro refresh token é emitido
+	w = doJSON(router, "POST", "/users/refresh/initial", accessToken, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var initial struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &initial))
+	require.NotEmpty(t, initial.RefreshToken)
+
+	// O mesmo access token não obtém um segundo refresh token
+	w = doJSON(router, "POST", "/users/refresh/initial", accessToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// E renova os tokens normalmente
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": initial.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// O access token renovado também não serve para a emissão inicial
+	var refreshed map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	refreshedToken, _ := refreshed["token"].(string)
+	require.NotEmpty(t, refreshedToken)
+	w = doJSON(router, "POST", "/users/refresh/initial", refreshedToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 	"time"
 
@@ -25,9 +26,6 @@ func setupTestEnvironment() (*gin.Engine, *service.UserService) {
 	// Configurar Gin para modo de teste
 	gin.SetMode(gin.TestMode)
 
-	// Limpar blacklist antes de cada teste
-	clearRefreshTokenBlacklist()
-
 	// Criar repositório em memória
 	memRepo := NewInMemoryUserRepository()
 
@@ -52,14 +50,14 @@ func setupTestEnvironment() (*gin.Engine, *service.UserService) {
 	router.POST("/users/logout", userController.Logout)
 	router.POST("/users/refresh", userController.RefreshToken)
 
-	// Rotas CRUD
-	router.GET("/users/:id", userController.GetByID)
-	router.PUT("/users/:id", userController.Update)
-	router.DELETE("/users/:id", userController.Delete)
-
 	// Criar AuthMiddleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
 
+	// Rotas CRUD; a busca por ID exige o próprio usuário ou um admin
+	router.GET("/users/:id", authMiddleware.GinAuthenticate(), userController.GetByID)
+	router.PUT("/users/:id", userController.Update)
+	router.DELETE("/users/:id", userController.Delete)
+
 	// Rota protegida para teste de middleware
 	router.GET("/protected", authMiddleware.GinAuthenticate(), func(c *gin.Context) {
 		userID, _ := c.Get("user_id")
@@ -69,14 +67,6 @@ func setupTestEnvironment() (*gin.Engine, *service.UserService) {
 	return router, userService
 }
 
-// clearRefreshTokenBlacklist limpa a blacklist de refresh tokens para isolamento dos testes
-func clearRefreshTokenBlacklist() {
-	// Acessar a blacklist através de uma função pública no service
-	// Como a blacklist é privada, vamos usar uma abordagem diferente
-	// Vou criar uma função no service para limpar a blacklist
-	service.ClearRefreshTokenBlacklist()
-}
-
 // InMemoryUserRepository implementa um repositório em memória para testes
 type InMemoryUserRepository struct {
 	users map[string]*domain.User
@@ -96,9 +86,12 @@ func (r *InMemoryUserRepository) Create(user *domain.User) error {
 	return nil
 }
 
+// GetByID retorna uma cópia, como o repositório real: alterações do chamador só
+// valem após Update
 func (r *InMemoryUserRepository) GetByID(id string) (*domain.User, error) {
 	if user, exists := r.users[id]; exists {
-		return user, nil
+		copied := *user
+		return &copied, nil
 	}
 	return nil, nil
 }
@@ -112,14 +105,67 @@ func (r *InMemoryUserRepository) GetByEmail(email string) (*domain.User, error)
 	return nil, nil
 }
 
+func (r *InMemoryUserRepository) GetByOrgAndEmail(orgID, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.OrgID == orgID && user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *InMemoryUserRepository) GetByUsername(username string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Username != "" && user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
 func (r *InMemoryUserRepository) Update(user *domain.User) error {
-	if _, exists := r.users[user.ID]; exists {
+	if existing, exists := r.users[user.ID]; exists {
+		if existing != user && existing.Version != user.Version {
+			return domain.ErrVersionConflict
+		}
+		user.Version++
 		r.users[user.ID] = user
 		return nil
 	}
 	return nil
 }
 
+func (r *InMemoryUserRepository) UpdateFields(id string, fields map[string]any) error {
+	user, exists := r.users[id]
+	if !exists {
+		return nil
+	}
+	user.Version++
+	for field, value := range fields {
+		switch field {
+		case domain.UserFieldEmail:
+			user.Email = value.(string)
+		case domain.UserFieldPassword:
+			user.Password = value.(string)
+		case domain.UserFieldName:
+			user.Name = value.(string)
+		case domain.UserFieldRoles:
+			user.Roles = value.([]string)
+		case domain.UserFieldUpdatedBy:
+			user.UpdatedBy = value.(string)
+		case domain.UserFieldMustChangePassword:
+			user.MustChangePassword = value.(bool)
+		case domain.UserFieldPasswordChangedAt:
+			user.PasswordChangedAt = value.(time.Time)
+		case domain.UserFieldTokensRevokedAt:
+			user.TokensRevokedAt = value.(time.Time)
+		case domain.UserFieldEmailVerified:
+			user.EmailVerified = value.(bool)
+		}
+	}
+	return nil
+}
+
 func (r *InMemoryUserRepository) Delete(id string) error {
 	delete(r.users, id)
 	return nil
@@ -134,6 +180,49 @@ func (r *InMemoryUserRepository) List() ([]*domain.User, error) {
 	return users, nil
 }
 
+// ListCreatedBetween lista os usuários criados no intervalo [from, to)
+func (r *InMemoryUserRepository) ListCreatedBetween(from, to time.Time) ([]*domain.User, error) {
+	users := make([]*domain.User, 0)
+	for _, user := range r.users {
+		if (from.IsZero() || !user.CreatedAt.Before(from)) && (to.IsZero() || user.CreatedAt.Before(to)) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// ListPaginated retorna a página de usuários ordenada pela criação e o total
+func (r *InMemoryUserRepository) ListPaginated(offset, limit int) ([]*domain.User, int, error) {
+	users := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+	if offset >= len(users) {
+		return []*domain.User{}, len(users), nil
+	}
+	return users[offset:min(offset+limit, len(users))], len(users), nil
+}
+
+// Stats conta os usuários, os verificados e os administradores
+func (r *InMemoryUserRepository) Stats() (*domain.UserStats, error) {
+	stats := &domain.UserStats{Total: len(r.users)}
+	for _, user := range r.users {
+		if user.EmailVerified {
+			stats.Verified++
+		}
+		if user.IsAdmin() {
+			stats.Admins++
+		}
+	}
+	return stats, nil
+}
+
 // TestUserRegistration testa o fluxo de registro de usuário
 func TestUserRegistration(t *testing.T) {
 	router, _ := setupTestEnvironment()
@@ -187,6 +276,18 @@ func TestUserRegistration(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("should fail with weak password", func(t *testing.T) {
+		for _, password := range []string{"123", "senhafraca", "12345678"} {
+			w := doJSON(router, "POST", "/users/register", "", map[string]string{
+				"email":    "weakpass@example.com",
+				"password": password,
+				"name":     "Weak Pass",
+			})
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, "senha %q deveria ser recusada", password)
+		}
+	})
+
 	t.Run("should fail with empty password", func(t *testing.T) {
 		// Arrange
 		userData := map[string]interface{}{
@@ -486,6 +587,8 @@ func TestUserCRUD(t *testing.T) {
 	}
 	err := userService.Create(testUser)
 	require.NoError(t, err)
+	accessToken, _, err := userService.Authenticate("crud@example.com", "password123")
+	require.NoError(t, err)
 
 	var userID string
 
@@ -497,6 +600,7 @@ func TestUserCRUD(t *testing.T) {
 
 		// Act
 		req := httptest.NewRequest("GET", "/users/"+userID, nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
@@ -516,14 +620,15 @@ func TestUserCRUD(t *testing.T) {
 		assert.Nil(t, response["password"])
 	})
 
-	t.Run("should fail to get user with invalid ID", func(t *testing.T) {
+	t.Run("should fail to get another user", func(t *testing.T) {
 		// Act
 		req := httptest.NewRequest("GET", "/users/invalid-id", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		// Assert: um usuário comum só consulta a própria conta
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("should update user successfully", func(t *testing.T) {
@@ -638,6 +743,7 @@ func TestUserCRUD(t *testing.T) {
 	t.Run("should fail to get deleted user", func(t *testing.T) {
 		// Act
 		req := httptest.NewRequest("GET", "/users/"+userID, nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
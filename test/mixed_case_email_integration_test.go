@@ -0,0 +1,22 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginWithMixedCaseEmail(t *testing.T) {
+	router, _ := setupTestEnvironment()
+
+	w := doJSON(router, "POST", "/users/register", "", map[string]any{"email": "Foo@Bar.com", "password": "senha123", "name": "Foo"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// O email é gravado normalizado, mas o login aceita a grafia usada no registro
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "Foo@Bar.com", "password": "senha123"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "foo@bar.com", "password": "senha123"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	noncecontroller "github.com/lucas-de-lima/go-auth-system/internal/controller/nonce"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/middleware"
+	"github.com/lucas-de-lima/go-auth-system/internal/nonce"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerWithNonce(router *gin.Engine, value string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/users/register", bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	if value != "" {
+		req.Header.Set(middleware.NonceHeader, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRegisterRequiresSingleUseNonce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	store := nonce.NewMemoryStore(nil)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).
+		WithNonceProtection(middleware.RequireNonce(store)).
+		Setup(router)
+	routes.NewNonceRoutes(noncecontroller.NewNonceController(store, 0, nil)).Setup(router)
+
+	body := map[string]string{"email": "nonce@example.com", "password": "senha123", "name": "Nonce"}
+
+	// Sem nonce o registro é recusado
+	w := registerWithNonce(router, "", body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "GET", "/nonce", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var issued struct {
+		Nonce     string `json:"nonce"`
+		ExpiresIn int64  `json:"expires_in"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+	require.NotEmpty(t, issued.Nonce)
+	assert.Equal(t, int64(noncecontroller.DefaultTTL.Seconds()), issued.ExpiresIn)
+
+	// O nonce vale uma única vez: o reenvio do mesmo formulário é recusado
+	w = registerWithNonce(router, issued.Nonce, body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	w = registerWithNonce(router, issued.Nonce, body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
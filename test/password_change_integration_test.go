@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doJSON(router *gin.Engine, method, path, token string, body any) *httptest.ResponseRecorder {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestForcedPasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	flagged := &domain.User{Email: "reset@example.com", Password: "temporaria1", Name: "Reset", MustChangePassword: true}
+	require.NoError(t, userService.Create(flagged))
+
+	// Login informa a troca obrigatória e não entrega refresh token
+	w := doJSON(router, "POST", "/users/login", "", map[string]string{"email": "reset@example.com", "password": "temporaria1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var login map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	assert.Equal(t, true, login["must_change_password"])
+	assert.NotContains(t, login, "refresh_token")
+	restricted := login["token"].(string)
+
+	// O token restrito não acessa outras rotas protegidas
+	w = doJSON(router, "POST", "/users/logout", restricted, map[string]string{"refresh_token": "x"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Mas permite trocar a senha
+	w = doJSON(router, "PUT", "/users/"+flagged.ID+"/password", restricted, map[string]string{
+		"current_password": "temporaria1",
+		"new_password":     "definitiva1",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Após a troca o login volta ao normal
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "reset@example.com", "password": "definitiva1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var relogin map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &relogin))
+	assert.NotContains(t, relogin, "must_change_password")
+	assert.NotEmpty(t, relogin["refresh_token"])
+}
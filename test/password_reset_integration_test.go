@@ -0,0 +1,90 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetTokenCapture faz o papel do canal de entrega, guardando o último token emitido
+type resetTokenCapture struct {
+	token string
+}
+
+func (c *resetTokenCapture) Publish(event domain.Event) error {
+	if e, ok := event.(domain.PasswordResetRequestedEvent); ok {
+		c.token = e.Token
+	}
+	return nil
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	capture := &resetTokenCapture{}
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, service.WithEventPublisher(capture))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	require.NoError(t, userService.Create(&domain.User{Email: "esqueci@example.com", Password: "senha-antiga1", Name: "Esqueci"}))
+
+	// Emails desconhecidos recebem a mesma resposta, sem emissão de token
+	w := doJSON(router, "POST", "/users/password-reset/request", "", map[string]string{"email": "ninguem@example.com"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, capture.token)
+
+	w = doJSON(router, "POST", "/users/password-reset/request", "", map[string]string{"email": "esqueci@example.com"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.NotEmpty(t, capture.token)
+	assert.NotContains(t, w.Body.String(), capture.token)
+
+	w = doJSON(router, "POST", "/users/password-reset/confirm", "", map[string]string{"token": capture.token})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "POST", "/users/password-reset/confirm", "", map[string]string{"token": capture.token, "new_password": "senha-nova1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// O token é de uso único
+	w = doJSON(router, "POST", "/users/password-reset/confirm", "", map[string]string{"token": capture.token, "new_password": "outra-senha1"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"email": "esqueci@example.com", "password": "senha-antiga1"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, loginForToken(t, router, "esqueci@example.com", "senha-nova1"))
+}
+
+func TestPasswordResetRequest_RedirectAllowlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	capture := &resetTokenCapture{}
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService,
+		service.WithEventPublisher(capture),
+		service.WithResetRedirectAllowlist([]string{"http://localhost:3000/reset-password"}))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	require.NoError(t, userService.Create(&domain.User{Email: "redirect@example.com", Password: "senha-antiga1", Name: "Redirect"}))
+
+	// Destino fora da allowlist: recusado sem emissão de token
+	w := doJSON(router, "POST", "/users/password-reset/request", "", map[string]string{
+		"email":        "redirect@example.com",
+		"redirect_uri": "https://evil.example.com/reset-password",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, capture.token)
+
+	w = doJSON(router, "POST", "/users/password-reset/request", "", map[string]string{
+		"email":        "redirect@example.com",
+		"redirect_uri": "http://localhost:3000/reset-password",
+	})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.NotEmpty(t, capture.token)
+}
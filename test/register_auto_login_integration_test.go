@@ -0,0 +1,51 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterWithAutoLogin(t *testing.T) {
+	router, _ := setupTestEnvironment()
+
+	w := doJSON(router, "POST", "/users/register", "", map[string]any{
+		"email":      "auto@example.com",
+		"password":   "senha123",
+		"name":       "Auto Login",
+		"auto_login": true,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var body struct {
+		Token        string         `json:"token"`
+		RefreshToken string         `json:"refresh_token"`
+		User         map[string]any `json:"user"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.NotEmpty(t, body.Token)
+	assert.NotEmpty(t, body.RefreshToken)
+	assert.Equal(t, "auto@example.com", body.User["email"])
+
+	// O access token emitido no registro já acessa rotas protegidas
+	w = doJSON(router, "GET", "/protected", body.Token, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var protected map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &protected))
+	assert.Equal(t, body.User["id"], protected["user_id"])
+
+	// E o refresh token pode ser rotacionado normalmente
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": body.RefreshToken})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Sem o flag, o registro continua retornando apenas o usuário
+	w = doJSON(router, "POST", "/users/register", "", map[string]any{"email": "manual@example.com", "password": "senha123"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var manual map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &manual))
+	assert.NotContains(t, manual, "token")
+	assert.Equal(t, "manual@example.com", manual["email"])
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/scheduler"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revokedTokenTable simula a tabela revoked_tokens, que sobrevive aos reinícios
+type revokedTokenTable struct {
+	mu   sync.Mutex
+	rows map[string]time.Time
+}
+
+func newRevokedTokenTable() *revokedTokenTable {
+	return &revokedTokenTable{rows: make(map[string]time.Time)}
+}
+
+func (t *revokedTokenTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.rows)
+}
+
+// InMemoryRevokedTokenRepository implementa domain.TokenBlacklist sobre a tabela simulada
+type InMemoryRevokedTokenRepository struct {
+	table *revokedTokenTable
+}
+
+func (r *InMemoryRevokedTokenRepository) Add(jti string, ttl time.Duration) error {
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+	r.table.rows[jti] = time.Now().Add(ttl)
+	return nil
+}
+
+func (r *InMemoryRevokedTokenRepository) Contains(jti string) (bool, error) {
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+	_, revoked := r.table.rows[jti]
+	return revoked, nil
+}
+
+// PurgeExpired remove as revogações já expiradas, como a consulta do repositório Prisma
+func (r *InMemoryRevokedTokenRepository) PurgeExpired() (int, error) {
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+	removed := 0
+	for jti, expiresAt := range r.table.rows {
+		if expiresAt.Before(time.Now()) {
+			delete(r.table.rows, jti)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// startInstance sobe uma instância da aplicação com repositórios novos sobre os mesmos dados
+func startInstance(users domain.UserRepository, table *revokedTokenTable) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	var blacklist domain.TokenBlacklist = &InMemoryRevokedTokenRepository{table: table}
+	userService := service.NewUserService(users, jwtService, service.WithTokenBlacklist(blacklist))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+	return router
+}
+
+// loginForTokens autentica e retorna o access token e o refresh token
+func loginForTokens(t *testing.T, router *gin.Engine, email, password string) (string, string) {
+	w := doJSON(router, "POST", "/users/login", "", map[string]string{"email": email, "password": password})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Token, response.RefreshToken
+}
+
+func TestRevokedRefreshTokenSurvivesRestart(t *testing.T) {
+	users := NewInMemoryUserRepository()
+	table := newRevokedTokenTable()
+	router := startInstance(users, table)
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{"email": "durable@example.com", "password": "senha123", "name": "Durable"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	access, loggedOut := loginForTokens(t, router, "durable@example.com", "senha123")
+	_, rotated := loginForTokens(t, router, "durable@example.com", "senha123")
+
+	// Um token encerrado por logout e outro consumido pela rotação
+	w = doJSON(router, "POST", "/users/logout", access, map[string]string{"refresh_token": loggedOut})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": rotated})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, table.len())
+
+	// Após o reinício os dois continuam recusados
+	router = startInstance(users, table)
+	for _, token := range []string{loggedOut, rotated} {
+		w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": token})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRevokedTokenPurgeSweep(t *testing.T) {
+	repo := &InMemoryRevokedTokenRepository{table: newRevokedTokenTable()}
+	var purger domain.TokenPurger = repo
+	require.NoError(t, repo.Add("expirado", -time.Minute))
+	require.NoError(t, repo.Add("vigente", time.Hour))
+
+	sweeper := scheduler.NewSweeper("revoked_tokens", 5*time.Millisecond, purger.PurgeExpired)
+	sweeper.Start()
+	defer sweeper.Stop()
+
+	assert.Eventually(t, func() bool {
+		revoked, _ := repo.Contains("expirado")
+		return !revoked
+	}, time.Second, time.Millisecond)
+	sweeper.Stop()
+
+	revoked, err := repo.Contains("vigente")
+	assert.NoError(t, err)
+	assert.True(t, revoked, "revogações vigentes devem permanecer")
+}
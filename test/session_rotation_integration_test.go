@@ -0,0 +1,115 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/lucas-de-lima/go-auth-system/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSessionTestEnvironment(opts ...service.UserServiceOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, opts...)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+	return router
+}
+
+func TestRotateSessions_SignsOutOtherDevices(t *testing.T) {
+	router := setupSessionTestEnvironment(service.WithSessionStore(session.NewMemoryStore(), 0))
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{"email": "multi@example.com", "password": "senha123", "name": "Multi"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	_, laptop := loginForTokens(t, router, "multi@example.com", "senha123")
+	_, tablet := loginForTokens(t, router, "multi@example.com", "senha123")
+	access, current := loginForTokens(t, router, "multi@example.com", "senha123")
+
+	w = doJSON(router, "POST", "/users/sessions/rotate", access, map[string]string{"refresh_token": current})
+	require.Equal(t, http.StatusOK, w.Code)
+	var rotated struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+
+	// As demais sessões e o refresh token anterior do chamador deixam de valer
+	for _, token := range []string{laptop, tablet, current} {
+		w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": token})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// O novo par funciona
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": rotated.RefreshToken})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doJSON(router, "POST", "/users/logout", rotated.Token, map[string]string{"refresh_token": rotated.RefreshToken})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRotateSessions_RequiresSessionStoreAndOwnToken(t *testing.T) {
+	router := setupSessionTestEnvironment()
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{"email": "solo@example.com", "password": "senha123", "name": "Solo"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	access, refresh := loginForTokens(t, router, "solo@example.com", "senha123")
+
+	w = doJSON(router, "POST", "/users/sessions/rotate", access, map[string]string{"refresh_token": refresh})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	router = setupSessionTestEnvironment(service.WithSessionStore(session.NewMemoryStore(), 0))
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		w = doJSON(router, "POST", "/users/register", "", map[string]string{"email": email, "password": "senha123", "name": "X"})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	accessA, _ := loginForTokens(t, router, "a@example.com", "senha123")
+	_, refreshB := loginForTokens(t, router, "b@example.com", "senha123")
+
+	// O refresh token precisa pertencer ao usuário autenticado
+	w = doJSON(router, "POST", "/users/sessions/rotate", accessA, map[string]string{"refresh_token": refreshB})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogout_RemovesSession(t *testing.T) {
+	router := setupSessionTestEnvironment(service.WithSessionStore(session.NewMemoryStore(), 0))
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{"email": "logout@example.com", "password": "senha123", "name": "Logout"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	_, kept := loginForTokens(t, router, "logout@example.com", "senha123")
+	access, leaving := loginForTokens(t, router, "logout@example.com", "senha123")
+
+	listSessions := func() []map[string]any {
+		w := doJSON(router, "GET", "/users/sessions", access, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var sessions []map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sessions))
+		return sessions
+	}
+	require.Len(t, listSessions(), 2)
+
+	w = doJSON(router, "POST", "/users/logout", access, map[string]string{"refresh_token": leaving})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Apenas a sessão encerrada sai da listagem, e o seu token não renova mais
+	sessions := listSessions()
+	require.Len(t, sessions, 1)
+	assert.Equal(t, auth.TokenID(kept), sessions[0]["id"])
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": leaving})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = doJSON(router, "POST", "/users/refresh", "", map[string]string{"refresh_token": kept})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestListSessions_WithoutStore(t *testing.T) {
+	router := setupSessionTestEnvironment()
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{"email": "nostore@example.com", "password": "senha123", "name": "Sem Store"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	access := loginForToken(t, router, "nostore@example.com", "senha123")
+
+	w = doJSON(router, "GET", "/users/sessions", access, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantScopedEmailLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, service.WithTenantScopedEmail(true))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	// O mesmo email é registrado em duas organizações
+	ids := map[string]string{}
+	for _, orgID := range []string{"org-a", "org-b"} {
+		w := doJSON(router, "POST", "/users/register", "", map[string]any{
+			"email": "same@example.com", "password": orgID + "-senha1", "name": orgID, "org_id": orgID,
+		})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var created map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		ids[orgID], _ = created["id"].(string)
+	}
+	require.NotEqual(t, ids["org-a"], ids["org-b"])
+
+	// E cada conta faz login pela sua organização
+	for orgID, wantID := range ids {
+		w := doJSON(router, "POST", "/users/login", "", map[string]string{
+			"email": "same@example.com", "password": orgID + "-senha1", "org_id": orgID,
+		})
+		require.Equal(t, http.StatusOK, w.Code, orgID)
+		var login struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+
+		w = doJSON(router, "GET", "/users/me", login.Token, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var me map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+		assert.Equal(t, wantID, me["id"], orgID)
+	}
+}
@@ -0,0 +1,61 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginByUsernameOrEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService, service.WithUsernameLogin(true))
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	w := doJSON(router, "POST", "/users/register", "", map[string]string{
+		"email":    "maria@example.com",
+		"username": "maria",
+		"password": "senha123",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "maria", created["username"])
+
+	// O mesmo usuário entra pelo username e pelo email
+	for _, body := range []map[string]string{
+		{"username": "maria", "password": "senha123"},
+		{"email": "maria", "password": "senha123"},
+		{"email": "maria@example.com", "password": "senha123"},
+	} {
+		w = doJSON(router, "POST", "/users/login", "", body)
+		require.Equal(t, http.StatusOK, w.Code, "login com %v", body)
+		var login map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+		assert.NotEmpty(t, login["token"])
+		assert.NotEmpty(t, login["refresh_token"])
+	}
+
+	// Senha incorreta continua rejeitada pelo username
+	w = doJSON(router, "POST", "/users/login", "", map[string]string{"username": "maria", "password": "errada"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Username já utilizado ou inválido não pode ser registrado
+	w = doJSON(router, "POST", "/users/register", "", map[string]string{
+		"email": "outra@example.com", "username": "maria", "password": "senha123",
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = doJSON(router, "POST", "/users/register", "", map[string]string{
+		"email": "outra@example.com", "username": "maria@example.com", "password": "senha123",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
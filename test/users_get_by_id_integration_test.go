@@ -0,0 +1,69 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserByID_ETagAndOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	owner := &domain.User{Email: "dono@example.com", Password: "senha123", Name: "Dono"}
+	require.NoError(t, userService.Create(owner))
+	other := &domain.User{Email: "outro@example.com", Password: "senha123", Name: "Outro"}
+	require.NoError(t, userService.Create(other))
+	ownerToken, _, err := userService.Authenticate("dono@example.com", "senha123")
+	require.NoError(t, err)
+	otherToken, _, err := userService.Authenticate("outro@example.com", "senha123")
+	require.NoError(t, err)
+
+	get := func(token, id, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/users/"+id, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// O dono consulta a própria conta e recebe a ETag
+	w := get(ownerToken, owner.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "dono@example.com", body["email"])
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Com a mesma ETag, 304 sem corpo
+	w = get(ownerToken, owner.ID, etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// Outro usuário, sem token ou com ID malformado não consultam a conta
+	assert.Equal(t, http.StatusForbidden, get(otherToken, owner.ID, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("", owner.ID, "").Code)
+	assert.Equal(t, http.StatusBadRequest, get(ownerToken, "nao-e-uuid", "").Code)
+
+	// As rotas estáticas continuam respondendo ao lado de /users/:id
+	assert.Equal(t, http.StatusOK, get(ownerToken, "me", "").Code)
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lucas-de-lima/go-auth-system/internal/auth"
+	"github.com/lucas-de-lima/go-auth-system/internal/controller/user"
+	"github.com/lucas-de-lima/go-auth-system/internal/domain"
+	"github.com/lucas-de-lima/go-auth-system/internal/routes"
+	"github.com/lucas-de-lima/go-auth-system/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersMe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("test-secret-key", 24, "test-refresh-key", 168)
+	userService := service.NewUserService(NewInMemoryUserRepository(), jwtService)
+	router := gin.New()
+	routes.NewUserRoutes(user.NewUserController(userService), jwtService, user.NewAdminController(userService)).Setup(router)
+
+	require.NoError(t, userService.Create(&domain.User{Email: "me@example.com", Password: "senha123", Name: "Eu"}))
+
+	w := doJSON(router, "POST", "/users/login", "", map[string]string{"email": "me@example.com", "password": "senha123"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var login struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+
+	// O usuário é resolvido a partir do token, sem informar o ID
+	w = doJSON(router, "GET", "/users/me", login.Token, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var me map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+	assert.Equal(t, "me@example.com", me["email"])
+	assert.NotContains(t, me, "password")
+
+	// Sem token, a rota responde 401
+	w = doJSON(router, "GET", "/users/me", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}